@@ -7,10 +7,13 @@ import (
 	"time"
 
 	"github.com/codepnw/stdlib-ticket-system/internal/config"
+	paymentgateway "github.com/codepnw/stdlib-ticket-system/internal/features/payment/gateway"
+	"github.com/codepnw/stdlib-ticket-system/internal/idempotency"
 	"github.com/codepnw/stdlib-ticket-system/internal/middleware"
 	"github.com/codepnw/stdlib-ticket-system/internal/server"
 	"github.com/codepnw/stdlib-ticket-system/pkg/database"
 	jwttoken "github.com/codepnw/stdlib-ticket-system/pkg/jwt"
+	"github.com/codepnw/stdlib-ticket-system/pkg/ticketcode"
 )
 
 const envPath = ".env.example"
@@ -46,7 +49,10 @@ func setup(cfg *config.EnvConfig, db *sql.DB) (*server.ServerConfig, error) {
 	location, _ := time.LoadLocation("Asia/Bangkok")
 
 	// Database Transaction
-	tx, err := database.NewTransaction(db)
+	tx, err := database.NewTransaction(db, database.RetryPolicy{
+		MaxRetries: cfg.DB.TxMaxRetries,
+		BaseDelay:  cfg.DB.TxRetryDelay,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -57,20 +63,43 @@ func setup(cfg *config.EnvConfig, db *sql.DB) (*server.ServerConfig, error) {
 		return nil, err
 	}
 
+	// Ticket Code Signer
+	tickets, err := ticketcode.NewSigner(cfg.Ticket.SigningKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Payment Gateway
+	gateway, err := paymentgateway.NewGateway(cfg.Payment.Provider)
+	if err != nil {
+		return nil, err
+	}
+
 	// Mux Server
 	mux := http.NewServeMux()
 
 	// New Middleware
 	mid := middleware.NewMiddleware(token)
+	idem := middleware.NewIdempotencyMiddleware(idempotency.NewStore(db), cfg.IdempotencyTTL)
 
 	serverCfg := &server.ServerConfig{
-		Location:   location,
-		DB:         db,
-		Tx:         tx,
-		Mux:        mux,
-		Addr:       ":8080",
-		Token:      token,
-		Middleware: mid,
+		Location:    location,
+		DB:          db,
+		Tx:          tx,
+		Mux:         mux,
+		Addr:        ":8080",
+		Token:       token,
+		Tickets:     tickets,
+		Middleware:  mid,
+		Idempotency: idem,
+		Booking:     cfg.Booking,
+		Waitlist:    cfg.Waitlist,
+		Transfer:    cfg.Transfer,
+		Resale:      cfg.Resale,
+		Stream:      cfg.Stream,
+		Cart:        cfg.Cart,
+		Payment:     cfg.Payment,
+		Gateway:     gateway,
 	}
 	return serverCfg, nil
 }
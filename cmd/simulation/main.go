@@ -5,29 +5,49 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
 )
 
+// Run the same load against servers started with BOOKING_LOCK_STRATEGY=pessimistic,
+// optimistic and skip_locked to compare them.
 const (
-	URL        = "http://localhost:8080/bookings"
+	BaseURL    = "http://localhost:8080"
 	NumUsers   = 20
 	EventID    = 13
 	TargetSeat = 63
+	Password   = "simulation"
 )
 
 func main() {
 	fmt.Println("Start Race Condition Simulation...")
-	fmt.Printf("%d users fighting for Seat ID: %d\n", NumUsers, EventID)
+	fmt.Printf("%d users fighting for Seat ID: %d (Event ID: %d)\n", NumUsers, TargetSeat, EventID)
 	fmt.Println("---------------------------------")
 
+	// Users Login
+	tokens := make([]string, NumUsers+1)
+	for i := 1; i <= NumUsers; i++ {
+		token, err := login(fmt.Sprintf("simuser%d", i))
+		if err != nil {
+			fmt.Printf("User %d Login Error: %v\n", i, err)
+			return
+		}
+		tokens[i] = token
+	}
+
 	var wg sync.WaitGroup
 	startSignal := make(chan struct{})
 
 	var successCount int64
+	var conflictCount int64
 	var failCount int64
 
+	var mu sync.Mutex
+	statuses := make(map[int]int)
+	durations := make([]time.Duration, 0, NumUsers)
+
 	// Users Bookings
 	for i := 1; i <= NumUsers; i++ {
 		wg.Add(1)
@@ -40,13 +60,18 @@ func main() {
 			// 2. Create Payload
 			reqBody := map[string]any{
 				"event_id": EventID,
-				"seat_ids": []int{TargetSeat},
-				"user_id":  userID,
+				"seats":    []map[string]any{{"seat_id": TargetSeat}},
 			}
 			jsonValue, _ := json.Marshal(reqBody)
 
+			req, _ := http.NewRequest(http.MethodPost, BaseURL+"/bookings", bytes.NewBuffer(jsonValue))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+tokens[userID])
+
 			// 3. Send Request
-			resp, err := http.Post(URL, "application/json", bytes.NewBuffer(jsonValue))
+			start := time.Now()
+			resp, err := http.DefaultClient.Do(req)
+			elapsed := time.Since(start)
 			if err != nil {
 				fmt.Printf("User %d Network Error\n", userID)
 				atomic.AddInt64(&failCount, 1)
@@ -54,14 +79,20 @@ func main() {
 			}
 			defer resp.Body.Close()
 
-			// bodyBytes, _ := io.ReadAll(resp.Body)
-			// bodyString := string(bodyBytes)
+			mu.Lock()
+			statuses[resp.StatusCode]++
+			durations = append(durations, elapsed)
+			mu.Unlock()
 
 			// 4. Result
-			if resp.StatusCode == 200 || resp.StatusCode == 201 {
-				fmt.Printf("-----> User %d Success!\n", userID)
+			switch resp.StatusCode {
+			case http.StatusOK, http.StatusCreated:
+				fmt.Printf("-----> User %d Success! (%s)\n", userID, elapsed)
 				atomic.AddInt64(&successCount, 1)
-			} else {
+			case http.StatusConflict:
+				fmt.Printf("User %d Conflict (%s)\n", userID, elapsed)
+				atomic.AddInt64(&conflictCount, 1)
+			default:
 				fmt.Printf("User %d Failed (%d)\n", userID, resp.StatusCode)
 				atomic.AddInt64(&failCount, 1)
 			}
@@ -79,7 +110,23 @@ func main() {
 	fmt.Println("📊 Summary Report")
 	fmt.Printf("Total Requests: %d\n", NumUsers)
 	fmt.Printf("✅ Success:      %d  (Should be 1 only)\n", successCount)
+	fmt.Printf("⚔️  Conflict:     %d\n", conflictCount)
 	fmt.Printf("❌ Failed:       %d\n", failCount)
+
+	codes := make([]int, 0, len(statuses))
+	for code := range statuses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		fmt.Printf("   HTTP %d: %d\n", code, statuses[code])
+	}
+
+	if len(durations) > 0 {
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		fmt.Printf("⏱  Latency min/p50/max: %s / %s / %s\n",
+			durations[0], durations[len(durations)/2], durations[len(durations)-1])
+	}
 	fmt.Println("------------------------------------------------")
 
 	if successCount == 1 {
@@ -90,3 +137,37 @@ func main() {
 		fmt.Println("🤔 TEST WEIRD: No one got the ticket? (Check Seat ID / Logic)")
 	}
 }
+
+// login : register the user if needed and return an access token
+func login(username string) (string, error) {
+	creds, _ := json.Marshal(map[string]string{
+		"username": username,
+		"password": Password,
+	})
+
+	resp, err := http.Post(BaseURL+"/register", "application/json", bytes.NewBuffer(creds))
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	resp, err = http.Post(BaseURL+"/login", "application/json", bytes.NewBuffer(creds))
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("login status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data struct {
+			AccessToken string `json:"access_token"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	return body.Data.AccessToken, nil
+}
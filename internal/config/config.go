@@ -16,15 +16,25 @@ const (
 	ContextTimeout                  = time.Second * 10
 	ContextUserClaimsKey contextKey = "user-claims-context"
 	ContextUserIDKey     contextKey = "user-id-context"
-	
+
 	// JWT Duration
 	AccessTokenDuration  = time.Hour * 1
 	RefreshTokenDuration = time.Hour * 24 * 7
 )
 
 type EnvConfig struct {
-	DB  DBConfig  `envPrefix:"DB_"`
-	JWT JWTConfig `envPrefix:"JWT_"`
+	DB       DBConfig       `envPrefix:"DB_"`
+	JWT      JWTConfig      `envPrefix:"JWT_"`
+	Booking  BookingConfig  `envPrefix:"BOOKING_"`
+	Payment  PaymentConfig  `envPrefix:"PAYMENT_"`
+	Waitlist WaitlistConfig `envPrefix:"WAITLIST_"`
+	Ticket   TicketConfig   `envPrefix:"TICKET_"`
+	Transfer TransferConfig `envPrefix:"TRANSFER_"`
+	Resale   ResaleConfig   `envPrefix:"RESALE_"`
+	Stream   StreamConfig   `envPrefix:"STREAM_"`
+	Cart     CartConfig     `envPrefix:"CART_"`
+
+	IdempotencyTTL time.Duration `env:"IDEMPOTENCY_TTL" envDefault:"24h"`
 }
 
 type DBConfig struct {
@@ -34,6 +44,11 @@ type DBConfig struct {
 	DBHost    string `env:"HOST" envDefault:"localhost"`
 	DBPort    int    `env:"PORT" envDefault:"5432"`
 	DBSSLMode string `env:"SSL_MODE" envDefault:"disable"`
+
+	// TxMaxRetries : reruns of a retrying transaction after a deadlock or serialization failure
+	TxMaxRetries int `env:"TX_MAX_RETRIES" envDefault:"3" validate:"gte=0"`
+	// TxRetryDelay : backoff before the first rerun, doubled for each one after
+	TxRetryDelay time.Duration `env:"TX_RETRY_DELAY" envDefault:"20ms"`
 }
 
 type JWTConfig struct {
@@ -41,6 +56,60 @@ type JWTConfig struct {
 	RefreshKey string `env:"REFRESH_KEY" validate:"required"`
 }
 
+type TicketConfig struct {
+	// SigningKey : HMAC key for ticket codes, rotating it invalidates issued tickets
+	SigningKey string `env:"SIGNING_KEY" validate:"required"`
+}
+
+type TransferConfig struct {
+	// Cutoff : transfers close this long before the event starts
+	Cutoff time.Duration `env:"CUTOFF" envDefault:"24h"`
+}
+
+type ResaleConfig struct {
+	// FeePercent : platform share of a resale price, the seller gets the rest
+	FeePercent int `env:"FEE_PERCENT" envDefault:"10" validate:"gte=0,lte=100"`
+	// SweepInterval : how often PENDING seller payouts are sent again
+	SweepInterval time.Duration `env:"SWEEP_INTERVAL" envDefault:"1m" validate:"required"`
+}
+
+type StreamConfig struct {
+	// ClientBuffer : seat updates queued per live client before it is dropped as too slow
+	ClientBuffer int `env:"CLIENT_BUFFER" envDefault:"64" validate:"gt=0"`
+	// Heartbeat : how often an idle stream sends a keep-alive comment
+	Heartbeat time.Duration `env:"HEARTBEAT" envDefault:"15s" validate:"required"`
+}
+
+type BookingConfig struct {
+	// HoldDuration : how long seats stay RESERVED for a PENDING booking
+	HoldDuration time.Duration `env:"HOLD_DURATION" envDefault:"15m" validate:"required"`
+	// SweepInterval : how often expired bookings are released
+	SweepInterval time.Duration `env:"SWEEP_INTERVAL" envDefault:"1m" validate:"required"`
+	// LockStrategy : "pessimistic" locks seat rows, "optimistic" checks seats.version on hold,
+	// "skip_locked" fails right away on seats another order is holding
+	LockStrategy string `env:"LOCK_STRATEGY" envDefault:"pessimistic" validate:"oneof=pessimistic optimistic skip_locked"`
+}
+
+type CartConfig struct {
+	// HoldDuration : how long seats stay RESERVED in a cart, adding seats to an event starts it over
+	HoldDuration time.Duration `env:"HOLD_DURATION" envDefault:"15m" validate:"required"`
+	// SweepInterval : how often expired cart seats are released
+	SweepInterval time.Duration `env:"SWEEP_INTERVAL" envDefault:"1m" validate:"required"`
+}
+
+type WaitlistConfig struct {
+	// OfferDuration : how long a waitlist offer holds the seats for its user
+	OfferDuration time.Duration `env:"OFFER_DURATION" envDefault:"10m" validate:"required"`
+	// SweepInterval : how often lapsed offers pass to the next in line
+	SweepInterval time.Duration `env:"SWEEP_INTERVAL" envDefault:"1m" validate:"required"`
+}
+
+type PaymentConfig struct {
+	Provider string `env:"PROVIDER" envDefault:"fake" validate:"required"`
+	// SweepInterval : how often PENDING refunds are sent again
+	SweepInterval time.Duration `env:"SWEEP_INTERVAL" envDefault:"1m" validate:"required"`
+}
+
 func LoadConfig(path string) (*EnvConfig, error) {
 	if err := godotenv.Load(path); err != nil {
 		return nil, fmt.Errorf("load env failed: %w", err)
@@ -4,14 +4,105 @@ import "errors"
 
 var (
 	ErrEventNotFound         = errors.New("event not found")
+	ErrRefundPolicyNotFound  = errors.New("refund policy not found")
 	ErrUsernameAlreadyExists = errors.New("username already exists")
 	ErrInvalidCredentials    = errors.New("invalid username or password")
 	ErrSeatNotFound          = errors.New("seat not found")
 	ErrSomeSeatNotAvailable  = errors.New("some seats not available")
-	
+	ErrSeatNotInBooking      = errors.New("seat is not part of this booking")
+	ErrZoneNotFound          = errors.New("zone not found")
+	ErrNotEnoughSeats        = errors.New("not enough available seats in this zone")
+	ErrSeatVersionConflict   = errors.New("seats were changed by another booking, try again")
+	ErrSeatNotInEvent        = errors.New("seat does not belong to this event")
+	ErrZoneNotOnSale         = errors.New("zone is not on sale at this time")
+	ErrInvalidPriceTier      = errors.New("price tier must start before it ends")
+	ErrInvalidFee            = errors.New("fee needs a positive amount, or a percent up to 100 for PERCENT fees")
+	ErrInvalidTicketType     = errors.New("ticket type needs a unique name and one price per zone of the event")
+	ErrTicketTypeNotOffered  = errors.New("ticket type is not offered in the seat's zone")
+	ErrTicketTypeSoldOut     = errors.New("ticket type quota is sold out")
+
+	// Money
+	ErrInvalidMoney     = errors.New("invalid money amount or currency")
+	ErrCurrencyMismatch = errors.New("amounts are in different currencies")
+
 	// Bookings
 	ErrBookingNotFound       = errors.New("booking not found")
 	ErrCancelOtherBooking    = errors.New("you cannot cancel bookings")
 	ErrBookingIsCancel       = errors.New("booking already cancelled")
-	ErrBookingIsPaid         = errors.New("cannot cancel paid booking ")
+	ErrBookingIsRefunded     = errors.New("booking already refunded")
+	ErrBookingIsTransferred  = errors.New("booking seats were all transferred")
+	ErrBookingNotRefundable  = errors.New("booking is not refundable")
+	ErrBookingNotOwned       = errors.New("booking belongs to another user")
+	ErrBookingNotPending     = errors.New("booking is not pending")
+	ErrBookingExpired        = errors.New("booking hold has expired")
+	ErrBookingNotActive      = errors.New("booking is not pending or paid")
+	ErrInvalidTransition     = errors.New("invalid booking status transition")
+	ErrPurchaseLimitExceeded = errors.New("purchase limit exceeded for this event")
+	ErrBookingNotPaid        = errors.New("booking is not paid")
+
+	// Tickets
+	ErrInvalidTicketCode = errors.New("invalid ticket code")
+	ErrTicketNotFound    = errors.New("ticket not found for this event")
+	ErrAlreadyCheckedIn  = errors.New("ticket already checked in")
+	ErrSeatCheckedIn     = errors.New("checked-in tickets cannot be cancelled")
+
+	// Transfers
+	ErrTransferNotFound         = errors.New("transfer not found")
+	ErrTransferNotOwned         = errors.New("transfer belongs to another user")
+	ErrTransferNotPending       = errors.New("transfer is not pending")
+	ErrTransferClosed           = errors.New("transfers are closed for this event")
+	ErrTransferToSelf           = errors.New("cannot transfer tickets to yourself")
+	ErrTransferSeatsUnavailable = errors.New("some seats can no longer be transferred")
+	ErrRecipientNotFound        = errors.New("recipient not found")
+
+	// Resale
+	ErrResaleNotAllowed   = errors.New("resale is not allowed for this event")
+	ErrResalePriceTooHigh = errors.New("resale price is above the event cap")
+	ErrSeatAlreadyListed  = errors.New("seat is already listed for resale")
+	ErrListingNotFound    = errors.New("resale listing not found")
+	ErrListingNotOwned    = errors.New("resale listing belongs to another user")
+	ErrListingUnavailable = errors.New("resale listing is no longer available")
+	ErrBuyOwnListing      = errors.New("cannot buy your own resale listing")
+	ErrPayoutFailed       = errors.New("seller payout failed")
+
+	// Waitlist
+	ErrWaitlistEntryNotFound = errors.New("waitlist entry not found")
+	ErrWaitlistAlreadyJoined = errors.New("already on the waitlist for this event")
+	ErrWaitlistNotOwned      = errors.New("waitlist entry belongs to another user")
+	ErrWaitlistEntryClosed   = errors.New("waitlist entry is already closed")
+	ErrSeatsStillAvailable   = errors.New("seats are still available for this event")
+	ErrOfferNotActive        = errors.New("waitlist entry has no active offer")
+	ErrOfferExpired          = errors.New("waitlist offer has expired")
+
+	// Cart
+	ErrCartEmpty         = errors.New("cart is empty")
+	ErrCartEventNotFound = errors.New("event is not in the cart")
+	ErrCartHoldExpired   = errors.New("cart hold has expired")
+
+	// Promotions
+	ErrPromoNotFound      = errors.New("promo code not found")
+	ErrPromoNotActive     = errors.New("promo code is not active")
+	ErrPromoNotApplicable = errors.New("promo code does not apply to these seats")
+	ErrPromoUsedUp        = errors.New("promo code has been fully redeemed")
+	ErrPromoUserLimit     = errors.New("promo code already used the maximum times by this user")
+	ErrPromoCodeExists    = errors.New("promo code already exists")
+	ErrInvalidPromo       = errors.New("invalid promo code")
+
+	// Payments
+	ErrPaymentFailed   = errors.New("payment failed")
+	ErrPaymentNotFound = errors.New("payment not found")
+	ErrRefundFailed    = errors.New("refund failed")
 )
+
+// SeatsUnavailableError : ErrSomeSeatNotAvailable along with the seats that could not be held
+type SeatsUnavailableError struct {
+	SeatIDs []int64
+}
+
+func (e *SeatsUnavailableError) Error() string {
+	return ErrSomeSeatNotAvailable.Error()
+}
+
+func (e *SeatsUnavailableError) Unwrap() error {
+	return ErrSomeSeatNotAvailable
+}
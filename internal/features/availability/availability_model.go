@@ -0,0 +1,17 @@
+package availability
+
+import "github.com/codepnw/stdlib-ticket-system/internal/features/seat"
+
+// SeatStatus : what a live seat map needs to redraw one seat
+type SeatStatus struct {
+	SeatID int64           `json:"seat_id"`
+	Status seat.SeatStatus `json:"status"`
+}
+
+func FromSeats(seats []seat.Seat) []SeatStatus {
+	statuses := make([]SeatStatus, 0, len(seats))
+	for _, s := range seats {
+		statuses = append(statuses, SeatStatus{SeatID: s.ID, Status: s.Status})
+	}
+	return statuses
+}
@@ -0,0 +1,97 @@
+package availabilityhandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	availabilityusecase "github.com/codepnw/stdlib-ticket-system/internal/features/availability/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/helper"
+)
+
+type availabilityHandler struct {
+	uc        availabilityusecase.AvailabilityUsecase
+	heartbeat time.Duration
+}
+
+func NewAvailabilityHandler(uc availabilityusecase.AvailabilityUsecase, heartbeat time.Duration) *availabilityHandler {
+	return &availabilityHandler{uc: uc, heartbeat: heartbeat}
+}
+
+// StreamSeats : Server-Sent Events, one "snapshot" of every seat then "seats" deltas.
+// A client that falls behind gets "dropped" and should reconnect for a fresh snapshot.
+func (h *availabilityHandler) StreamSeats(w http.ResponseWriter, r *http.Request) {
+	eventID, err := helper.ParseInt64(r.PathValue("event_id"))
+	if err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	snapshot, sub, err := h.uc.Subscribe(r.Context(), eventID)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrEventNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	defer sub.Close()
+
+	rc := http.NewResponseController(w)
+	// The stream stays open far longer than a normal response
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if err := writeEvent(w, rc, "snapshot", snapshot); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case <-heartbeat.C:
+			// Comment line, keeps proxies from closing an idle stream
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+
+		case deltas, ok := <-sub.C():
+			if !ok {
+				_ = writeEvent(w, rc, "dropped", nil)
+				return
+			}
+			if err := writeEvent(w, rc, "seats", deltas); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, rc *http.ResponseController, name string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, payload); err != nil {
+		return err
+	}
+	return rc.Flush()
+}
@@ -0,0 +1,77 @@
+package availabilityusecase
+
+import (
+	"context"
+	"log"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/config"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/availability"
+	eventrepo "github.com/codepnw/stdlib-ticket-system/internal/features/event/repo"
+	seatrepo "github.com/codepnw/stdlib-ticket-system/internal/features/seat/repo"
+	"github.com/codepnw/stdlib-ticket-system/pkg/pubsub"
+)
+
+// Hub : seat status deltas keyed by event ID
+type Hub = pubsub.Hub[int64, []availability.SeatStatus]
+
+// Subscription : deltas of one event, closed when the client falls too far behind
+type Subscription = pubsub.Subscription[int64, []availability.SeatStatus]
+
+type AvailabilityUsecase interface {
+	// Subscribe : follow the event's seats, the snapshot is read after subscribing so no delta is missed
+	Subscribe(ctx context.Context, eventID int64) ([]availability.SeatStatus, *Subscription, error)
+	SeatsChanged(ctx context.Context, eventID int64, seatIDs []int64)
+}
+
+type availabilityUsecase struct {
+	hub       *Hub
+	seatRepo  seatrepo.SeatRepository
+	eventRepo eventrepo.EventRepository
+}
+
+func NewAvailabilityUsecase(hub *Hub, seatRepo seatrepo.SeatRepository, eventRepo eventrepo.EventRepository) AvailabilityUsecase {
+	return &availabilityUsecase{
+		hub:       hub,
+		seatRepo:  seatRepo,
+		eventRepo: eventRepo,
+	}
+}
+
+func (u *availabilityUsecase) Subscribe(ctx context.Context, eventID int64) ([]availability.SeatStatus, *Subscription, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	// 1. Check Event
+	if _, err := u.eventRepo.GetEventByID(ctx, eventID); err != nil {
+		return nil, nil, err
+	}
+
+	// 2. Subscribe before the snapshot, a seat changing in between arrives again as a delta
+	sub := u.hub.Subscribe(eventID)
+
+	// 3. Snapshot
+	seats, err := u.seatRepo.GetSeatStatuses(ctx, eventID, nil)
+	if err != nil {
+		sub.Close()
+		return nil, nil, err
+	}
+	return availability.FromSeats(seats), sub, nil
+}
+
+// SeatsChanged : publish the current status of the seats, the booking is already committed so errors are only logged
+func (u *availabilityUsecase) SeatsChanged(ctx context.Context, eventID int64, seatIDs []int64) {
+	if len(seatIDs) == 0 || u.hub.Subscribers(eventID) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	// Read back the status, a released seat may already be held again by a waitlist offer
+	seats, err := u.seatRepo.GetSeatStatuses(ctx, eventID, seatIDs)
+	if err != nil {
+		log.Printf("load seat status of event %d failed: %v", eventID, err)
+		return
+	}
+	u.hub.Publish(eventID, availability.FromSeats(seats))
+}
@@ -0,0 +1,80 @@
+package availabilityusecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/availability"
+	availabilityusecase "github.com/codepnw/stdlib-ticket-system/internal/features/availability/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	eventrepo "github.com/codepnw/stdlib-ticket-system/internal/features/event/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/seat"
+	seatrepo "github.com/codepnw/stdlib-ticket-system/internal/features/seat/repo"
+	"github.com/codepnw/stdlib-ticket-system/pkg/pubsub"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type mocks struct {
+	seat  *seatrepo.MockSeatRepository
+	event *eventrepo.MockEventRepository
+}
+
+func TestSubscribe(t *testing.T) {
+	uc, m := setup(t)
+
+	m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10}, nil).Times(1)
+
+	m.seat.EXPECT().GetSeatStatuses(gomock.Any(), int64(10), gomock.Nil()).Return([]seat.Seat{
+		{ID: 11, Status: seat.StatusAvailable},
+		{ID: 12, Status: seat.StatusSold},
+	}, nil).Times(1)
+
+	snapshot, sub, err := uc.Subscribe(context.Background(), 10)
+	assert.NoError(t, err)
+	defer sub.Close()
+
+	assert.Equal(t, []availability.SeatStatus{
+		{SeatID: 11, Status: seat.StatusAvailable},
+		{SeatID: 12, Status: seat.StatusSold},
+	}, snapshot)
+
+	// A booking holds seat 11
+	m.seat.EXPECT().GetSeatStatuses(gomock.Any(), int64(10), []int64{11}).Return([]seat.Seat{
+		{ID: 11, Status: seat.StatusReserved},
+	}, nil).Times(1)
+
+	uc.SeatsChanged(context.Background(), 10, []int64{11})
+
+	assert.Equal(t, []availability.SeatStatus{{SeatID: 11, Status: seat.StatusReserved}}, <-sub.C())
+}
+
+func TestSubscribeEventNotFound(t *testing.T) {
+	uc, m := setup(t)
+
+	m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{}, errs.ErrEventNotFound).Times(1)
+
+	_, _, err := uc.Subscribe(context.Background(), 10)
+	assert.ErrorIs(t, err, errs.ErrEventNotFound)
+}
+
+func TestSeatsChangedWithoutSubscribers(t *testing.T) {
+	uc, _ := setup(t)
+
+	// Nobody watches the event, so the seat status is never loaded
+	uc.SeatsChanged(context.Background(), 10, []int64{11})
+}
+
+func setup(t *testing.T) (availabilityusecase.AvailabilityUsecase, mocks) {
+	ctrl := gomock.NewController(t)
+
+	m := mocks{
+		seat:  seatrepo.NewMockSeatRepository(ctrl),
+		event: eventrepo.NewMockEventRepository(ctrl),
+	}
+	hub := pubsub.NewHub[int64, []availability.SeatStatus](8)
+	uc := availabilityusecase.NewAvailabilityUsecase(hub, m.seat, m.event)
+
+	return uc, m
+}
@@ -1,37 +1,170 @@
 package booking
 
-import "time"
+import (
+	"time"
 
-type bookingStatus string
+	"github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+)
+
+type BookingStatus string
 
 const (
-	StatusPending   bookingStatus = "PENDING"
-	StatusPaid      bookingStatus = "PAID"
-	StatusCancelled bookingStatus = "CANCELLED"
-	StatusFailed    bookingStatus = "FAILED"
+	StatusPending     BookingStatus = "PENDING"
+	StatusPaid        BookingStatus = "PAID"
+	StatusCancelled   BookingStatus = "CANCELLED"
+	StatusFailed      BookingStatus = "FAILED"
+	StatusRefunded    BookingStatus = "REFUNDED"
+	StatusTransferred BookingStatus = "TRANSFERRED"
 )
 
+// Booking : TotalAmount is what the user pays, the tickets after DiscountAmount from a promo code plus the fee and tax lines
 type Booking struct {
-	ID          string        `json:"id" db:"id"`
-	UserID      int64         `json:"user_id" db:"user_id"`
-	EventID     int64         `json:"event_id" db:"event_id"`
-	TotalAmount float64       `json:"total_amount" db:"total_amount"`
-	Status      bookingStatus `json:"status" db:"status"`
-	CreatedAt   time.Time     `json:"created_at" db:"created_at"`
-	ExpiresAt   time.Time     `json:"expires_at" db:"expires_at"`
+	ID             string        `json:"id" db:"id"`
+	UserID         int64         `json:"user_id" db:"user_id"`
+	EventID        int64         `json:"event_id" db:"event_id"`
+	TotalAmount    money.Money   `json:"total_amount" db:"total_amount"`
+	DiscountAmount money.Money   `json:"discount_amount" db:"discount_amount"`
+	Status         BookingStatus `json:"status" db:"status"`
+	CreatedAt      time.Time     `json:"created_at" db:"created_at"`
+	ExpiresAt      time.Time     `json:"expires_at" db:"expires_at"`
 }
 
+// BookingItem : TicketTypeID 0 means the seat sold at its zone price.
+// Discount is the seat's share of the booking's promo discount
 type BookingItem struct {
-	BookingID string `db:"booking_id"`
-	SeatID    int64  `db:"seat_id"`
+	BookingID    string      `db:"booking_id"`
+	SeatID       int64       `db:"seat_id"`
+	TicketTypeID int64       `db:"ticket_type_id"`
+	UnitPrice    money.Money `db:"unit_price"`
+	Discount     money.Money `db:"discount"`
+}
+
+// SpreadDiscount : share discount over the items at the covered indexes by their price, so a seat
+// takes its part of the discount with it when it leaves the booking. The last covered item
+// takes the rounding remainder.
+func SpreadDiscount(items []BookingItem, covered []int, discount money.Money) error {
+	if len(covered) == 0 || !discount.IsPositive() {
+		return nil
+	}
+
+	eligible := money.Zero(discount.Currency)
+	for _, i := range covered {
+		var err error
+		if eligible, err = eligible.Add(items[i].UnitPrice); err != nil {
+			return err
+		}
+	}
+	if !eligible.IsPositive() {
+		return nil
+	}
+
+	rest := discount
+	for n, i := range covered {
+		share := money.New(discount.Amount*items[i].UnitPrice.Amount/eligible.Amount, discount.Currency)
+		if n == len(covered)-1 {
+			share = rest
+		}
+		var err error
+		if rest, err = rest.Sub(share); err != nil {
+			return err
+		}
+		items[i].Discount = share
+	}
+	return nil
+}
+
+// SeatTicket : a seat to book and the ticket type chosen for it, 0 books it at the zone price
+type SeatTicket struct {
+	SeatID       int64
+	TicketTypeID int64
+}
+
+// LineKind : what an order line charges on top of the tickets
+type LineKind string
+
+const (
+	LineFee LineKind = "FEE"
+	LineTax LineKind = "TAX"
+)
+
+// Line : a fee or tax charged on the booking, priced again whenever seats leave it
+type Line struct {
+	BookingID string      `db:"booking_id"`
+	Kind      LineKind    `db:"kind"`
+	Name      string      `db:"name"`
+	Amount    money.Money `db:"amount"`
+}
+
+// Breakdown : Total = max(Subtotal - Discount, 0) + the Lines
+type Breakdown struct {
+	Subtotal money.Money
+	Discount money.Money
+	Lines    []Line
+	Total    money.Money
+}
+
+// Price : break an order of tickets seats worth subtotal into fee and tax lines.
+// Percentage fees apply to the tickets after the discount, tax to the tickets and fees.
+func Price(subtotal, discount money.Money, tickets int, fees []event.Fee, taxPercent float64) (Breakdown, error) {
+	net, err := subtotal.Sub(discount)
+	if err != nil {
+		return Breakdown{}, err
+	}
+	if !net.IsPositive() {
+		net = money.Zero(subtotal.Currency)
+	}
+
+	b := Breakdown{Subtotal: subtotal, Discount: discount, Total: net}
+	for _, f := range fees {
+		charge, err := f.Charge(tickets, net)
+		if err != nil {
+			return Breakdown{}, err
+		}
+		if charge.IsZero() {
+			continue
+		}
+		if b.Total, err = b.Total.Add(charge); err != nil {
+			return Breakdown{}, err
+		}
+		b.Lines = append(b.Lines, Line{Kind: LineFee, Name: f.Name, Amount: charge})
+	}
+
+	if tax := b.Total.Percent(taxPercent); !tax.IsZero() {
+		if b.Total, err = b.Total.Add(tax); err != nil {
+			return Breakdown{}, err
+		}
+		b.Lines = append(b.Lines, Line{Kind: LineTax, Name: "VAT", Amount: tax})
+	}
+	return b, nil
 }
 
+// BookingHistoryResponse : Subtotal is the active tickets before DiscountAmount
 type BookingHistoryResponse struct {
-	ID          string    `json:"id" db:"booking_id"`
-	EventName   string    `json:"event_name" db:"event_name"`
-	EventDate   time.Time `json:"event_date" db:"event_date"`
-	TotalAmount float64   `json:"total_amount" db:"total_amount"`
-	Status      string    `json:"status" db:"status"`
-	SeatNumbers string    `json:"seat_numbers" db:"seat_numbers"` // STRING_AGG()
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	ID             string      `json:"id" db:"booking_id"`
+	EventName      string      `json:"event_name" db:"event_name"`
+	EventDate      time.Time   `json:"event_date" db:"event_date"`
+	Subtotal       money.Money `json:"subtotal" db:"subtotal"`
+	DiscountAmount money.Money `json:"discount_amount" db:"discount_amount"`
+	TotalAmount    money.Money `json:"total_amount" db:"total_amount"`
+	Status         string      `json:"status" db:"status"`
+	SeatNumbers    string      `json:"seat_numbers" db:"seat_numbers"` // STRING_AGG()
+	CreatedAt      time.Time   `json:"created_at" db:"created_at"`
+}
+
+type BookingDetailResponse struct {
+	Booking
+	EventName string                `db:"event_name"`
+	EventDate time.Time             `db:"event_date"`
+	Items     []BookingItemResponse `db:"-"`
+}
+
+type BookingItemResponse struct {
+	SeatID      int64       `db:"seat_id"`
+	SeatNumber  string      `db:"seat_number"`
+	Zone        string      `db:"zone"`
+	TicketType  string      `db:"ticket_type"`
+	UnitPrice   money.Money `db:"unit_price"`
+	ReleasedAt  *time.Time  `db:"released_at"`
+	CheckedInAt *time.Time  `db:"checked_in_at"`
 }
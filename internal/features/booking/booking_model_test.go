@@ -0,0 +1,136 @@
+package booking_test
+
+import (
+	"testing"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrice(t *testing.T) {
+	fees := []event.Fee{
+		{Name: "service", Type: event.FeePercent, Percent: 5},
+		{Name: "facility", Type: event.FeePerTicket, Amount: money.New(2000, "THB")},
+		{Name: "handling", Type: event.FeePerOrder, Amount: money.New(1000, "THB")},
+	}
+
+	type testCase struct {
+		name          string
+		discount      money.Money
+		fees          []event.Fee
+		taxPercent    float64
+		expectedLines []booking.Line
+		expectedTotal money.Money
+	}
+
+	testCases := []testCase{
+		{
+			name:          "tickets only",
+			discount:      money.Zero("THB"),
+			expectedTotal: money.New(20000, "THB"),
+		},
+		{
+			name:       "fees and vat",
+			discount:   money.Zero("THB"),
+			fees:       fees,
+			taxPercent: 7,
+			expectedLines: []booking.Line{
+				{Kind: booking.LineFee, Name: "service", Amount: money.New(1000, "THB")},
+				{Kind: booking.LineFee, Name: "facility", Amount: money.New(4000, "THB")},
+				{Kind: booking.LineFee, Name: "handling", Amount: money.New(1000, "THB")},
+				{Kind: booking.LineTax, Name: "VAT", Amount: money.New(1820, "THB")},
+			},
+			expectedTotal: money.New(27820, "THB"),
+		},
+		{
+			name:       "percent fee after the discount",
+			discount:   money.New(10000, "THB"),
+			fees:       fees[:1],
+			taxPercent: 7,
+			expectedLines: []booking.Line{
+				{Kind: booking.LineFee, Name: "service", Amount: money.New(500, "THB")},
+				{Kind: booking.LineTax, Name: "VAT", Amount: money.New(735, "THB")},
+			},
+			expectedTotal: money.New(11235, "THB"),
+		},
+		{
+			name:     "discount covers the tickets, zero percent fee is left out",
+			discount: money.New(20000, "THB"),
+			fees:     fees,
+			expectedLines: []booking.Line{
+				{Kind: booking.LineFee, Name: "facility", Amount: money.New(4000, "THB")},
+				{Kind: booking.LineFee, Name: "handling", Amount: money.New(1000, "THB")},
+			},
+			expectedTotal: money.New(5000, "THB"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			price, err := booking.Price(money.New(20000, "THB"), tc.discount, 2, tc.fees, tc.taxPercent)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedLines, price.Lines)
+			assert.Equal(t, tc.expectedTotal, price.Total)
+		})
+	}
+}
+
+func TestSpreadDiscount(t *testing.T) {
+	type testCase struct {
+		name     string
+		prices   []int64
+		covered  []int
+		discount money.Money
+		expected []int64
+	}
+
+	testCases := []testCase{
+		{
+			name:     "by price",
+			prices:   []int64{10000, 30000},
+			covered:  []int{0, 1},
+			discount: money.New(4000, "THB"),
+			expected: []int64{1000, 3000},
+		},
+		{
+			name:     "last covered seat takes the remainder",
+			prices:   []int64{10000, 10000, 10000},
+			covered:  []int{0, 1, 2},
+			discount: money.New(1000, "THB"),
+			expected: []int64{333, 333, 334},
+		},
+		{
+			name:     "seats outside the zone get nothing",
+			prices:   []int64{10000, 20000},
+			covered:  []int{1},
+			discount: money.New(5000, "THB"),
+			expected: []int64{0, 5000},
+		},
+		{
+			name:     "no discount",
+			prices:   []int64{10000},
+			covered:  []int{0},
+			discount: money.Zero("THB"),
+			expected: []int64{0},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			items := make([]booking.BookingItem, 0, len(tc.prices))
+			for _, p := range tc.prices {
+				items = append(items, booking.BookingItem{UnitPrice: money.New(p, "THB")})
+			}
+
+			err := booking.SpreadDiscount(items, tc.covered, tc.discount)
+
+			assert.NoError(t, err)
+			for i, item := range items {
+				assert.Equal(t, tc.expected[i], item.Discount.Amount)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package booking
+
+import (
+	"fmt"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+)
+
+// transitions : every status a booking may move to from its current status
+var transitions = map[BookingStatus][]BookingStatus{
+	StatusPending: {StatusPaid, StatusCancelled, StatusFailed},
+	StatusPaid:    {StatusRefunded, StatusTransferred},
+}
+
+// TransitionError : a status change the state machine does not allow
+type TransitionError struct {
+	From BookingStatus
+	To   BookingStatus
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("booking cannot move from %s to %s", e.From, e.To)
+}
+
+// Unwrap : match errs.ErrInvalidTransition and the error that explains why
+func (e *TransitionError) Unwrap() []error {
+	return []error{errs.ErrInvalidTransition, e.reason()}
+}
+
+func (e *TransitionError) reason() error {
+	switch e.From {
+	case StatusCancelled:
+		return errs.ErrBookingIsCancel
+	case StatusRefunded:
+		return errs.ErrBookingIsRefunded
+	case StatusTransferred:
+		return errs.ErrBookingIsTransferred
+	}
+
+	switch e.To {
+	case StatusPaid, StatusFailed:
+		return errs.ErrBookingNotPending
+	case StatusRefunded:
+		return errs.ErrBookingNotRefundable
+	}
+	return errs.ErrBookingNotActive
+}
+
+// CanTransition : report whether from -> to is allowed
+func CanTransition(from, to BookingStatus) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition : nil when from -> to is allowed, *TransitionError otherwise
+func Transition(from, to BookingStatus) error {
+	if !CanTransition(from, to) {
+		return &TransitionError{From: from, To: to}
+	}
+	return nil
+}
+
+// CancelStatus : the status a cancelled booking ends in, paid bookings are refunded
+func CancelStatus(from BookingStatus) BookingStatus {
+	if from == StatusPaid {
+		return StatusRefunded
+	}
+	return StatusCancelled
+}
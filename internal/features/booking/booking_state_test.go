@@ -0,0 +1,47 @@
+package booking_test
+
+import (
+	"testing"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransition(t *testing.T) {
+	type testCase struct {
+		name        string
+		from        booking.BookingStatus
+		to          booking.BookingStatus
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{name: "pending to paid", from: booking.StatusPending, to: booking.StatusPaid},
+		{name: "pending to cancelled", from: booking.StatusPending, to: booking.StatusCancelled},
+		{name: "pending to failed", from: booking.StatusPending, to: booking.StatusFailed},
+		{name: "paid to refunded", from: booking.StatusPaid, to: booking.StatusRefunded},
+		{name: "paid to transferred", from: booking.StatusPaid, to: booking.StatusTransferred},
+		{name: "fail pay twice", from: booking.StatusPaid, to: booking.StatusPaid, expectedErr: errs.ErrBookingNotPending},
+		{name: "fail cancel twice", from: booking.StatusCancelled, to: booking.StatusCancelled, expectedErr: errs.ErrBookingIsCancel},
+		{name: "fail refund twice", from: booking.StatusRefunded, to: booking.StatusRefunded, expectedErr: errs.ErrBookingIsRefunded},
+		{name: "fail refund pending", from: booking.StatusPending, to: booking.StatusRefunded, expectedErr: errs.ErrBookingNotRefundable},
+		{name: "fail cancel transferred", from: booking.StatusTransferred, to: booking.StatusCancelled, expectedErr: errs.ErrBookingIsTransferred},
+		{name: "fail cancel failed", from: booking.StatusFailed, to: booking.StatusCancelled, expectedErr: errs.ErrBookingNotActive},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := booking.Transition(tc.from, tc.to)
+
+			if tc.expectedErr != nil {
+				var transErr *booking.TransitionError
+				assert.ErrorAs(t, err, &transErr)
+				assert.ErrorIs(t, err, errs.ErrInvalidTransition)
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
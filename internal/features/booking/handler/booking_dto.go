@@ -1,10 +1,36 @@
 package bookinghandler
 
+import "github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+
+// BookingCreateReq : send seats, or a zone and quantity to let the server pick the best seats.
+// TicketTypeID applies to every picked seat. Ticket types and PromoCode are optional.
 type BookingCreateReq struct {
-	EventID int64   `json:"event_id" validate:"required"`
-	SeatIDs []int64 `json:"seat_ids" validate:"required"`
+	EventID      int64            `json:"event_id" validate:"required"`
+	Seats        []BookingSeatReq `json:"seats" validate:"required_without=Zone,excluded_with=Zone,dive"`
+	Zone         string           `json:"zone" validate:"required_with=Quantity"`
+	Quantity     int              `json:"quantity" validate:"required_with=Zone,gte=0"`
+	TicketTypeID int64            `json:"ticket_type_id" validate:"gte=0,excluded_with=Seats"`
+	PromoCode    string           `json:"promo_code" validate:"max=50"`
+}
+
+// BookingSeatReq : a seat and its ticket type, without one the seat sells at its zone price
+type BookingSeatReq struct {
+	SeatID       int64 `json:"seat_id" validate:"required"`
+	TicketTypeID int64 `json:"ticket_type_id" validate:"gte=0"`
+}
+
+func (r BookingCreateReq) seatTickets() []booking.SeatTicket {
+	seats := make([]booking.SeatTicket, 0, len(r.Seats))
+	for _, s := range r.Seats {
+		seats = append(seats, booking.SeatTicket{SeatID: s.SeatID, TicketTypeID: s.TicketTypeID})
+	}
+	return seats
 }
 
 type BookingCancelReq struct {
 	BookingID string `json:"booking_id" validate:"required"`
 }
+
+type BookingCancelSeatsReq struct {
+	SeatIDs []int64 `json:"seat_ids" validate:"required,min=1"`
+}
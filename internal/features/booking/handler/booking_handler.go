@@ -2,6 +2,7 @@ package bookinghandler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/codepnw/stdlib-ticket-system/internal/errs"
@@ -30,11 +31,34 @@ func (h *bookingHandler) CreateBooking(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.uc.CreateBooking(r.Context(), req.EventID, req.SeatIDs); err != nil {
-		helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+	var data any
+	var err error
+	if req.Zone != "" {
+		data, err = h.uc.CreateBestAvailableBooking(r.Context(), req.EventID, req.Zone, req.Quantity, req.TicketTypeID, req.PromoCode)
+	} else {
+		data, err = h.uc.CreateBooking(r.Context(), req.EventID, req.seatTickets(), req.PromoCode)
+	}
+	if err != nil {
+		var unavailable *errs.SeatsUnavailableError
+		switch {
+		case errors.Is(err, errs.ErrEventNotFound), errors.Is(err, errs.ErrZoneNotFound), errors.Is(err, errs.ErrPromoNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, errs.ErrSeatNotInEvent), errors.Is(err, errs.ErrPromoNotActive), errors.Is(err, errs.ErrPromoNotApplicable),
+			errors.Is(err, errs.ErrTicketTypeNotOffered):
+			helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.As(err, &unavailable):
+			helper.ErrorDataResponse(w, http.StatusConflict, err.Error(), map[string][]int64{"seat_ids": unavailable.SeatIDs})
+		case errors.Is(err, errs.ErrPurchaseLimitExceeded), errors.Is(err, errs.ErrNotEnoughSeats), errors.Is(err, errs.ErrSeatVersionConflict),
+			errors.Is(err, errs.ErrSomeSeatNotAvailable), errors.Is(err, errs.ErrZoneNotOnSale),
+			errors.Is(err, errs.ErrPromoUsedUp), errors.Is(err, errs.ErrPromoUserLimit), errors.Is(err, errs.ErrTicketTypeSoldOut):
+			helper.ErrorResponse(w, http.StatusConflict, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
-	helper.SuccessResponse(w, http.StatusOK, "event booked", nil)
+	helper.SuccessResponse(w, http.StatusOK, "event booked", data)
 }
 
 func (h *bookingHandler) GetBookingHistory(w http.ResponseWriter, r *http.Request) {
@@ -46,6 +70,42 @@ func (h *bookingHandler) GetBookingHistory(w http.ResponseWriter, r *http.Reques
 	helper.SuccessResponse(w, http.StatusOK, "", data)
 }
 
+func (h *bookingHandler) GetBookingDetail(w http.ResponseWriter, r *http.Request) {
+	data, err := h.uc.GetBookingDetail(r.Context(), r.PathValue("booking_id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrBookingNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, errs.ErrBookingNotOwned):
+			helper.ErrorResponse(w, http.StatusForbidden, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	helper.SuccessResponse(w, http.StatusOK, "", data)
+}
+
+func (h *bookingHandler) GetTickets(w http.ResponseWriter, r *http.Request) {
+	data, err := h.uc.GetTickets(r.Context(), r.PathValue("booking_id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrBookingNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, errs.ErrBookingNotOwned):
+			helper.ErrorResponse(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, errs.ErrBookingNotPaid):
+			helper.ErrorResponse(w, http.StatusConflict, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	helper.SuccessResponse(w, http.StatusOK, "", data)
+}
+
 func (h *bookingHandler) CancelBooking(w http.ResponseWriter, r *http.Request) {
 	var req BookingCancelReq
 
@@ -58,22 +118,67 @@ func (h *bookingHandler) CancelBooking(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.uc.CancelBooking(r.Context(), req.BookingID)
+	data, err := h.uc.CancelBooking(r.Context(), req.BookingID)
 	if err != nil {
-		switch err {
-		case errs.ErrBookingNotFound:
+		switch {
+		case errors.Is(err, errs.ErrBookingNotFound):
 			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
-		case errs.ErrCancelOtherBooking:
+		case errors.Is(err, errs.ErrBookingNotOwned):
+			helper.ErrorResponse(w, http.StatusConflict, errs.ErrCancelOtherBooking.Error())
+		case errors.Is(err, errs.ErrBookingIsCancel), errors.Is(err, errs.ErrBookingIsRefunded), errors.Is(err, errs.ErrBookingIsTransferred):
+			helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errs.ErrBookingNotRefundable):
+			helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errs.ErrInvalidTransition), errors.Is(err, errs.ErrSeatCheckedIn):
 			helper.ErrorResponse(w, http.StatusConflict, err.Error())
-		case errs.ErrBookingIsCancel:
+		case errors.Is(err, errs.ErrRefundFailed):
+			helper.ErrorResponse(w, http.StatusBadGateway, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	msg := "booking cancelled"
+	if data.RefundAmount.IsPositive() {
+		msg = "booking refunded"
+	}
+	helper.SuccessResponse(w, http.StatusOK, msg, data)
+}
+
+func (h *bookingHandler) CancelSeats(w http.ResponseWriter, r *http.Request) {
+	var req BookingCancelSeatsReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := utils.Validate(&req); err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.uc.CancelSeats(r.Context(), r.PathValue("booking_id"), req.SeatIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrBookingNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, errs.ErrBookingNotOwned):
+			helper.ErrorResponse(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, errs.ErrBookingIsCancel), errors.Is(err, errs.ErrBookingIsRefunded), errors.Is(err, errs.ErrBookingIsTransferred), errors.Is(err, errs.ErrBookingNotActive):
 			helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
-		case errs.ErrBookingIsPaid:
+		case errors.Is(err, errs.ErrSeatNotInBooking), errors.Is(err, errs.ErrBookingNotRefundable):
 			helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errs.ErrInvalidTransition), errors.Is(err, errs.ErrSeatCheckedIn):
+			helper.ErrorResponse(w, http.StatusConflict, err.Error())
+		case errors.Is(err, errs.ErrRefundFailed):
+			helper.ErrorResponse(w, http.StatusBadGateway, err.Error())
 
 		default:
 			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
 		}
 		return
 	}
-	helper.SuccessResponse(w, http.StatusOK, "booking cancelled", nil)
+	helper.SuccessResponse(w, http.StatusOK, "seats cancelled", data)
 }
@@ -4,9 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"time"
 
 	"github.com/codepnw/stdlib-ticket-system/internal/errs"
 	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
 	"github.com/lib/pq"
 )
 
@@ -14,11 +17,23 @@ import (
 type BookingRepository interface {
 	GetByID(ctx context.Context, bookingID string) (booking.Booking, error)
 	GetHistory(ctx context.Context, userID int64) ([]booking.BookingHistoryResponse, error)
+	GetDetail(ctx context.Context, bookingID string) (booking.BookingDetailResponse, error)
+	GetItems(ctx context.Context, bookingID string) ([]booking.BookingItemResponse, error)
+	GetLines(ctx context.Context, bookingIDs []string) ([]booking.Line, error)
 
 	// Transaction
 	CreateBookingTx(ctx context.Context, tx *sql.Tx, input booking.Booking) (string, error)
-	CreateBookingItemsTx(ctx context.Context, tx *sql.Tx, bookingID string, seatIDs []int64) error
-	CancelBookingTx(ctx context.Context, tx *sql.Tx, bookingID string) error
+	CreateBookingItemsTx(ctx context.Context, tx *sql.Tx, bookingID string, items []booking.BookingItem) error
+	CreateLinesTx(ctx context.Context, tx *sql.Tx, bookingID string, lines []booking.Line) error
+	UpdateStatusTx(ctx context.Context, tx *sql.Tx, bookingID string, from, to booking.BookingStatus) error
+	RecalculateTotalTx(ctx context.Context, tx *sql.Tx, bookingID string, fees []event.Fee, taxPercent float64) (money.Money, error)
+	LockUserEventTx(ctx context.Context, tx *sql.Tx, userID, eventID int64) error
+	CountUserSeatsTx(ctx context.Context, tx *sql.Tx, userID, eventID int64) (int, error)
+	CountTicketTypesTx(ctx context.Context, tx *sql.Tx, ticketTypeIDs []int64) (map[int64]int, error)
+	GetExpiredBookingsTx(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]booking.Booking, error)
+	GetForUpdateTx(ctx context.Context, tx *sql.Tx, bookingID string) (booking.Booking, error)
+	GetActiveSeatIDsTx(ctx context.Context, tx *sql.Tx, bookingID string) ([]int64, error)
+	MoveItemsTx(ctx context.Context, tx *sql.Tx, fromBookingID, toBookingID string, seatIDs []int64) error
 }
 
 type bookingRepository struct {
@@ -29,14 +44,28 @@ func NewBookingRepository(db *sql.DB) BookingRepository {
 	return &bookingRepository{db: db}
 }
 
+const bookingColumns = `id, user_id, event_id, total_amount, discount_amount, currency, status, created_at, expires_at`
+
 func (r *bookingRepository) GetByID(ctx context.Context, bookingID string) (booking.Booking, error) {
-	query := `SELECT id, user_id, status FROM bookings WHERE id = $1`
+	query := `SELECT ` + bookingColumns + ` FROM bookings WHERE id = $1`
+	return scanBooking(r.db.QueryRowContext(ctx, query, bookingID).Scan)
+}
+
+func scanBooking(scan func(dest ...any) error) (booking.Booking, error) {
 	var b booking.Booking
+	var currency string
+	var expiresAt sql.NullTime
 
-	err := r.db.QueryRowContext(ctx, query, bookingID).Scan(
-		&b.ID, 
+	err := scan(
+		&b.ID,
 		&b.UserID,
+		&b.EventID,
+		&b.TotalAmount.Amount,
+		&b.DiscountAmount.Amount,
+		&currency,
 		&b.Status,
+		&b.CreatedAt,
+		&expiresAt,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -44,13 +73,15 @@ func (r *bookingRepository) GetByID(ctx context.Context, bookingID string) (book
 		}
 		return booking.Booking{}, err
 	}
+	b.TotalAmount.Currency, b.DiscountAmount.Currency = currency, currency
+	b.ExpiresAt = expiresAt.Time
 	return b, nil
 }
 
 func (r *bookingRepository) CreateBookingTx(ctx context.Context, tx *sql.Tx, input booking.Booking) (string, error) {
 	query := `
-		INSERT INTO bookings (user_id, event_id, total_amount, status)
-		VALUES ($1, $2, $3, $4) RETURNING id
+		INSERT INTO bookings (user_id, event_id, total_amount, discount_amount, currency, status, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id
 	`
 	var id string
 
@@ -59,8 +90,11 @@ func (r *bookingRepository) CreateBookingTx(ctx context.Context, tx *sql.Tx, inp
 		query,
 		input.UserID,
 		input.EventID,
-		input.TotalAmount,
+		input.TotalAmount.Amount,
+		input.DiscountAmount.Amount,
+		input.TotalAmount.Currency,
 		input.Status,
+		sql.NullTime{Time: input.ExpiresAt, Valid: !input.ExpiresAt.IsZero()},
 	).Scan(&id)
 	if err != nil {
 		return "", err
@@ -68,25 +102,98 @@ func (r *bookingRepository) CreateBookingTx(ctx context.Context, tx *sql.Tx, inp
 	return id, nil
 }
 
-func (r *bookingRepository) CreateBookingItemsTx(ctx context.Context, tx *sql.Tx, bookingID string, seatIDs []int64) error {
+func (r *bookingRepository) CreateBookingItemsTx(ctx context.Context, tx *sql.Tx, bookingID string, items []booking.BookingItem) error {
+	query := `
+		INSERT INTO booking_items (booking_id, seat_id, unit_price, ticket_type_id, discount)
+		SELECT $1, i.seat_id, i.unit_price, NULLIF(i.ticket_type_id, 0), i.discount
+		FROM UNNEST($2::BIGINT[], $3::BIGINT[], $4::BIGINT[], $5::BIGINT[]) AS i(seat_id, unit_price, ticket_type_id, discount)
+	`
+	seatIDs := make([]int64, 0, len(items))
+	prices := make([]int64, 0, len(items))
+	ticketTypeIDs := make([]int64, 0, len(items))
+	discounts := make([]int64, 0, len(items))
+	for _, item := range items {
+		seatIDs = append(seatIDs, item.SeatID)
+		prices = append(prices, item.UnitPrice.Amount)
+		ticketTypeIDs = append(ticketTypeIDs, item.TicketTypeID)
+		discounts = append(discounts, item.Discount.Amount)
+	}
+
+	_, err := tx.ExecContext(ctx, query, bookingID, pq.Array(seatIDs), pq.Array(prices), pq.Array(ticketTypeIDs), pq.Array(discounts))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *bookingRepository) CreateLinesTx(ctx context.Context, tx *sql.Tx, bookingID string, lines []booking.Line) error {
 	query := `
-		INSERT INTO booking_items (booking_id, seat_id)
-		SELECT $1, UNNEST($2::BIGINT[])
+		INSERT INTO booking_lines (booking_id, kind, name, amount)
+		SELECT $1, l.kind::booking_line_kind, l.name, l.amount
+		FROM UNNEST($2::TEXT[], $3::TEXT[], $4::BIGINT[]) AS l(kind, name, amount)
 	`
-	_, err := tx.ExecContext(ctx, query, bookingID, pq.Array(seatIDs))
+	kinds := make([]string, 0, len(lines))
+	names := make([]string, 0, len(lines))
+	amounts := make([]int64, 0, len(lines))
+	for _, l := range lines {
+		kinds = append(kinds, string(l.Kind))
+		names = append(names, l.Name)
+		amounts = append(amounts, l.Amount.Amount)
+	}
+
+	_, err := tx.ExecContext(ctx, query, bookingID, pq.Array(kinds), pq.Array(names), pq.Array(amounts))
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// GetLines : the fee and tax lines of the bookings, in the order they were priced
+func (r *bookingRepository) GetLines(ctx context.Context, bookingIDs []string) ([]booking.Line, error) {
+	query := `
+		SELECT l.booking_id, l.kind, l.name, l.amount, b.currency
+		FROM booking_lines l
+		JOIN bookings b ON b.id = l.booking_id
+		WHERE l.booking_id = ANY($1::UUID[])
+		ORDER BY l.booking_id, l.id ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(bookingIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []booking.Line
+	for rows.Next() {
+		var l booking.Line
+		if err := rows.Scan(
+			&l.BookingID,
+			&l.Kind,
+			&l.Name,
+			&l.Amount.Amount,
+			&l.Amount.Currency,
+		); err != nil {
+			return nil, err
+		}
+		lines = append(lines, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
 func (r *bookingRepository) GetHistory(ctx context.Context, userID int64) ([]booking.BookingHistoryResponse, error) {
 	query := `
 		SELECT
 			b.id AS booking_id,
 			e.name AS event_name,
 			e.event_date,
+			COALESCE(SUM(COALESCE(bi.unit_price, s.price)) FILTER (WHERE bi.released_at IS NULL), 0) AS subtotal,
+			b.discount_amount,
 			b.total_amount,
+			b.currency,
 			b.status,
 			b.created_at,
 			STRING_AGG(s.seat_number, ', ') AS seat_numbers
@@ -112,13 +219,17 @@ func (r *bookingRepository) GetHistory(ctx context.Context, userID int64) ([]boo
 			&h.ID,
 			&h.EventName,
 			&h.EventDate,
-			&h.TotalAmount,
+			&h.Subtotal.Amount,
+			&h.DiscountAmount.Amount,
+			&h.TotalAmount.Amount,
+			&h.TotalAmount.Currency,
 			&h.Status,
 			&h.CreatedAt,
 			&h.SeatNumbers,
 		); err != nil {
 			return nil, err
 		}
+		h.Subtotal.Currency, h.DiscountAmount.Currency = h.TotalAmount.Currency, h.TotalAmount.Currency
 		history = append(history, h)
 	}
 
@@ -128,12 +239,110 @@ func (r *bookingRepository) GetHistory(ctx context.Context, userID int64) ([]boo
 	return history, nil
 }
 
-func (r *bookingRepository) CancelBookingTx(ctx context.Context, tx *sql.Tx, bookingID string) error {
+func (r *bookingRepository) GetDetail(ctx context.Context, bookingID string) (booking.BookingDetailResponse, error) {
+	query := `
+		SELECT
+			b.id, b.user_id, b.event_id, b.total_amount, b.discount_amount, b.currency, b.status, b.created_at, b.expires_at,
+			e.name AS event_name,
+			e.event_date
+		FROM bookings b
+		JOIN events e ON b.event_id = e.id
+		WHERE b.id = $1
+	`
+	var d booking.BookingDetailResponse
+	var currency string
+	var expiresAt sql.NullTime
+
+	err := r.db.QueryRowContext(ctx, query, bookingID).Scan(
+		&d.ID,
+		&d.UserID,
+		&d.EventID,
+		&d.TotalAmount.Amount,
+		&d.DiscountAmount.Amount,
+		&currency,
+		&d.Status,
+		&d.CreatedAt,
+		&expiresAt,
+		&d.EventName,
+		&d.EventDate,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return booking.BookingDetailResponse{}, errs.ErrBookingNotFound
+		}
+		return booking.BookingDetailResponse{}, err
+	}
+	d.TotalAmount.Currency, d.DiscountAmount.Currency = currency, currency
+	d.ExpiresAt = expiresAt.Time
+	return d, nil
+}
+
+func (r *bookingRepository) GetItems(ctx context.Context, bookingID string) ([]booking.BookingItemResponse, error) {
 	query := `
-		UPDATE bookings SET status = 'CANCELLED'
-		WHERE id = $1
+		SELECT
+			bi.seat_id,
+			s.seat_number,
+			COALESCE(s.zone, ''),
+			COALESCE(tt.name, ''),
+			COALESCE(bi.unit_price, s.price),
+			b.currency,
+			bi.released_at,
+			bi.checked_in_at
+		FROM booking_items bi
+		JOIN bookings b ON b.id = bi.booking_id
+		JOIN seats s ON bi.seat_id = s.id
+		LEFT JOIN ticket_types tt ON tt.id = bi.ticket_type_id
+		WHERE bi.booking_id = $1
+		ORDER BY s.id ASC
 	`
-	res, err := tx.ExecContext(ctx, query, bookingID)
+	rows, err := r.db.QueryContext(ctx, query, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []booking.BookingItemResponse
+	for rows.Next() {
+		var i booking.BookingItemResponse
+		var releasedAt, checkedInAt sql.NullTime
+		if err := rows.Scan(
+			&i.SeatID,
+			&i.SeatNumber,
+			&i.Zone,
+			&i.TicketType,
+			&i.UnitPrice.Amount,
+			&i.UnitPrice.Currency,
+			&releasedAt,
+			&checkedInAt,
+		); err != nil {
+			return nil, err
+		}
+		if releasedAt.Valid {
+			i.ReleasedAt = &releasedAt.Time
+		}
+		if checkedInAt.Valid {
+			i.CheckedInAt = &checkedInAt.Time
+		}
+		items = append(items, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UpdateStatusTx : move a booking only if it is still in from, a concurrent change returns *booking.TransitionError
+func (r *bookingRepository) UpdateStatusTx(ctx context.Context, tx *sql.Tx, bookingID string, from, to booking.BookingStatus) error {
+	if err := booking.Transition(from, to); err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE bookings SET status = $3
+		WHERE id = $1 AND status = $2
+	`
+	res, err := tx.ExecContext(ctx, query, bookingID, from, to)
 	if err != nil {
 		return err
 	}
@@ -144,7 +353,208 @@ func (r *bookingRepository) CancelBookingTx(ctx context.Context, tx *sql.Tx, boo
 	}
 
 	if rows == 0 {
-		return errs.ErrBookingNotFound
+		var current booking.BookingStatus
+		query := `SELECT status FROM bookings WHERE id = $1`
+		if err := tx.QueryRowContext(ctx, query, bookingID).Scan(&current); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errs.ErrBookingNotFound
+			}
+			return err
+		}
+		return &booking.TransitionError{From: current, To: to}
+	}
+	return nil
+}
+
+// RecalculateTotalTx : price the active items and their discount shares again with the event's fees and tax,
+// rewriting the fee and tax lines. Only PENDING or PAID bookings change, one left without seats owes nothing.
+func (r *bookingRepository) RecalculateTotalTx(ctx context.Context, tx *sql.Tx, bookingID string, fees []event.Fee, taxPercent float64) (money.Money, error) {
+	query := `
+		SELECT b.currency, COALESCE(SUM(bi.unit_price), 0), COALESCE(SUM(bi.discount), 0), COUNT(bi.seat_id)
+		FROM bookings b
+		LEFT JOIN booking_items bi ON bi.booking_id = b.id AND bi.released_at IS NULL
+		WHERE b.id = $1 AND b.status IN ('PENDING', 'PAID')
+		GROUP BY b.id
+	`
+	var currency string
+	var subtotal, discount int64
+	var tickets int
+
+	err := tx.QueryRowContext(ctx, query, bookingID).Scan(&currency, &subtotal, &discount, &tickets)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return money.Money{}, errs.ErrBookingNotActive
+		}
+		return money.Money{}, err
+	}
+	if tickets == 0 {
+		fees = nil
+	}
+
+	price, err := booking.Price(money.New(subtotal, currency), money.New(discount, currency), tickets, fees, taxPercent)
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM booking_lines WHERE booking_id = $1`, bookingID); err != nil {
+		return money.Money{}, err
+	}
+	if len(price.Lines) > 0 {
+		if err := r.CreateLinesTx(ctx, tx, bookingID, price.Lines); err != nil {
+			return money.Money{}, err
+		}
+	}
+
+	update := `UPDATE bookings SET total_amount = $2, discount_amount = $3 WHERE id = $1`
+	if _, err := tx.ExecContext(ctx, update, bookingID, price.Total.Amount, price.Discount.Amount); err != nil {
+		return money.Money{}, err
+	}
+	return price.Total, nil
+}
+
+// LockUserEventTx : serialize bookings of one user for one event until the transaction ends
+func (r *bookingRepository) LockUserEventTx(ctx context.Context, tx *sql.Tx, userID, eventID int64) error {
+	query := `SELECT pg_advisory_xact_lock(hashtextextended('booking:' || $1::text || ':' || $2::text, 0))`
+	_, err := tx.ExecContext(ctx, query, userID, eventID)
+	return err
+}
+
+// CountUserSeatsTx : seats the user still holds or owns for the event
+func (r *bookingRepository) CountUserSeatsTx(ctx context.Context, tx *sql.Tx, userID, eventID int64) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM booking_items bi
+		JOIN bookings b ON b.id = bi.booking_id
+		WHERE b.user_id = $1 AND b.event_id = $2
+		AND b.status IN ('PENDING', 'PAID') AND bi.released_at IS NULL
+	`
+	var count int
+	if err := tx.QueryRowContext(ctx, query, userID, eventID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountTicketTypesTx : active tickets of each type across PENDING and PAID bookings, types without any are left out
+func (r *bookingRepository) CountTicketTypesTx(ctx context.Context, tx *sql.Tx, ticketTypeIDs []int64) (map[int64]int, error) {
+	query := `
+		SELECT bi.ticket_type_id, COUNT(*) FROM booking_items bi
+		JOIN bookings b ON b.id = bi.booking_id
+		WHERE bi.ticket_type_id = ANY($1)
+		AND b.status IN ('PENDING', 'PAID') AND bi.released_at IS NULL
+		GROUP BY bi.ticket_type_id
+	`
+	rows, err := tx.QueryContext(ctx, query, pq.Array(ticketTypeIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sold := make(map[int64]int, len(ticketTypeIDs))
+	for rows.Next() {
+		var id int64
+		var count int
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, err
+		}
+		sold[id] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return sold, nil
+}
+
+// GetExpiredBookingsTx : lock PENDING bookings whose hold has passed, skip rows other sweepers hold
+func (r *bookingRepository) GetExpiredBookingsTx(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]booking.Booking, error) {
+	query := `
+		SELECT id, event_id FROM bookings
+		WHERE status = 'PENDING' AND expires_at <= $1
+		ORDER BY expires_at ASC
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`
+	rows, err := tx.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bookings []booking.Booking
+	for rows.Next() {
+		var b booking.Booking
+		if err := rows.Scan(&b.ID, &b.EventID); err != nil {
+			return nil, err
+		}
+		bookings = append(bookings, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return bookings, nil
+}
+
+// GetForUpdateTx : lock the booking, changes to its seats then run one at a time
+func (r *bookingRepository) GetForUpdateTx(ctx context.Context, tx *sql.Tx, bookingID string) (booking.Booking, error) {
+	query := `SELECT ` + bookingColumns + ` FROM bookings WHERE id = $1 FOR UPDATE`
+	return scanBooking(tx.QueryRowContext(ctx, query, bookingID).Scan)
+}
+
+// GetActiveSeatIDsTx : seats the booking still holds, read under the booking lock,
+// checked-in seats count as held, CancelSeatsTx and RemoveSeatsTx refuse to release them
+func (r *bookingRepository) GetActiveSeatIDsTx(ctx context.Context, tx *sql.Tx, bookingID string) ([]int64, error) {
+	query := `
+		SELECT seat_id FROM booking_items
+		WHERE booking_id = $1 AND released_at IS NULL
+		ORDER BY seat_id
+	`
+	rows, err := tx.QueryContext(ctx, query, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seatIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		seatIDs = append(seatIDs, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return seatIDs, nil
+}
+
+// MoveItemsTx : hand active, not checked-in seats of a PAID booking to another booking.
+// Open resale listings of the seats close, the old holder can no longer sell them
+func (r *bookingRepository) MoveItemsTx(ctx context.Context, tx *sql.Tx, fromBookingID, toBookingID string, seatIDs []int64) error {
+	query := `
+		WITH delisted AS (
+			UPDATE resale_listings SET status = 'CANCELLED'
+			WHERE seller_booking_id = $1 AND seat_id = ANY($3) AND status = 'LISTED'
+		)
+		UPDATE booking_items bi SET booking_id = $2
+		FROM bookings b
+		WHERE b.id = bi.booking_id AND bi.booking_id = $1 AND b.status = 'PAID'
+			AND bi.seat_id = ANY($3)
+			AND bi.released_at IS NULL AND bi.checked_in_at IS NULL
+	`
+	res, err := tx.ExecContext(ctx, query, fromBookingID, toBookingID, pq.Array(seatIDs))
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows != int64(len(seatIDs)) {
+		return errs.ErrTransferSeatsUnavailable
 	}
 	return nil
 }
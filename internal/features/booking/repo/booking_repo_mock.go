@@ -8,8 +8,11 @@ import (
 	context "context"
 	sql "database/sql"
 	reflect "reflect"
+	time "time"
 
 	booking "github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+	event "github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	money "github.com/codepnw/stdlib-ticket-system/pkg/money"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -36,32 +39,48 @@ func (m *MockBookingRepository) EXPECT() *MockBookingRepositoryMockRecorder {
 	return m.recorder
 }
 
-// CancelBookingTx mocks base method.
-func (m *MockBookingRepository) CancelBookingTx(ctx context.Context, tx *sql.Tx, bookingID string) error {
+// CountTicketTypesTx mocks base method.
+func (m *MockBookingRepository) CountTicketTypesTx(ctx context.Context, tx *sql.Tx, ticketTypeIDs []int64) (map[int64]int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CancelBookingTx", ctx, tx, bookingID)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "CountTicketTypesTx", ctx, tx, ticketTypeIDs)
+	ret0, _ := ret[0].(map[int64]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// CancelBookingTx indicates an expected call of CancelBookingTx.
-func (mr *MockBookingRepositoryMockRecorder) CancelBookingTx(ctx, tx, bookingID interface{}) *gomock.Call {
+// CountTicketTypesTx indicates an expected call of CountTicketTypesTx.
+func (mr *MockBookingRepositoryMockRecorder) CountTicketTypesTx(ctx, tx, ticketTypeIDs interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelBookingTx", reflect.TypeOf((*MockBookingRepository)(nil).CancelBookingTx), ctx, tx, bookingID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTicketTypesTx", reflect.TypeOf((*MockBookingRepository)(nil).CountTicketTypesTx), ctx, tx, ticketTypeIDs)
+}
+
+// CountUserSeatsTx mocks base method.
+func (m *MockBookingRepository) CountUserSeatsTx(ctx context.Context, tx *sql.Tx, userID int64, eventID int64) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUserSeatsTx", ctx, tx, userID, eventID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUserSeatsTx indicates an expected call of CountUserSeatsTx.
+func (mr *MockBookingRepositoryMockRecorder) CountUserSeatsTx(ctx, tx, userID, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUserSeatsTx", reflect.TypeOf((*MockBookingRepository)(nil).CountUserSeatsTx), ctx, tx, userID, eventID)
 }
 
 // CreateBookingItemsTx mocks base method.
-func (m *MockBookingRepository) CreateBookingItemsTx(ctx context.Context, tx *sql.Tx, bookingID string, seatIDs []int64) error {
+func (m *MockBookingRepository) CreateBookingItemsTx(ctx context.Context, tx *sql.Tx, bookingID string, items []booking.BookingItem) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateBookingItemsTx", ctx, tx, bookingID, seatIDs)
+	ret := m.ctrl.Call(m, "CreateBookingItemsTx", ctx, tx, bookingID, items)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // CreateBookingItemsTx indicates an expected call of CreateBookingItemsTx.
-func (mr *MockBookingRepositoryMockRecorder) CreateBookingItemsTx(ctx, tx, bookingID, seatIDs interface{}) *gomock.Call {
+func (mr *MockBookingRepositoryMockRecorder) CreateBookingItemsTx(ctx, tx, bookingID, items interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBookingItemsTx", reflect.TypeOf((*MockBookingRepository)(nil).CreateBookingItemsTx), ctx, tx, bookingID, seatIDs)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBookingItemsTx", reflect.TypeOf((*MockBookingRepository)(nil).CreateBookingItemsTx), ctx, tx, bookingID, items)
 }
 
 // CreateBookingTx mocks base method.
@@ -79,6 +98,35 @@ func (mr *MockBookingRepositoryMockRecorder) CreateBookingTx(ctx, tx, input inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBookingTx", reflect.TypeOf((*MockBookingRepository)(nil).CreateBookingTx), ctx, tx, input)
 }
 
+// CreateLinesTx mocks base method.
+func (m *MockBookingRepository) CreateLinesTx(ctx context.Context, tx *sql.Tx, bookingID string, lines []booking.Line) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLinesTx", ctx, tx, bookingID, lines)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateLinesTx indicates an expected call of CreateLinesTx.
+func (mr *MockBookingRepositoryMockRecorder) CreateLinesTx(ctx, tx, bookingID, lines interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLinesTx", reflect.TypeOf((*MockBookingRepository)(nil).CreateLinesTx), ctx, tx, bookingID, lines)
+}
+
+// GetActiveSeatIDsTx mocks base method.
+func (m *MockBookingRepository) GetActiveSeatIDsTx(ctx context.Context, tx *sql.Tx, bookingID string) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveSeatIDsTx", ctx, tx, bookingID)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveSeatIDsTx indicates an expected call of GetActiveSeatIDsTx.
+func (mr *MockBookingRepositoryMockRecorder) GetActiveSeatIDsTx(ctx, tx, bookingID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSeatIDsTx", reflect.TypeOf((*MockBookingRepository)(nil).GetActiveSeatIDsTx), ctx, tx, bookingID)
+}
+
 // GetByID mocks base method.
 func (m *MockBookingRepository) GetByID(ctx context.Context, bookingID string) (booking.Booking, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +142,51 @@ func (mr *MockBookingRepositoryMockRecorder) GetByID(ctx, bookingID interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockBookingRepository)(nil).GetByID), ctx, bookingID)
 }
 
+// GetDetail mocks base method.
+func (m *MockBookingRepository) GetDetail(ctx context.Context, bookingID string) (booking.BookingDetailResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDetail", ctx, bookingID)
+	ret0, _ := ret[0].(booking.BookingDetailResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDetail indicates an expected call of GetDetail.
+func (mr *MockBookingRepositoryMockRecorder) GetDetail(ctx, bookingID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDetail", reflect.TypeOf((*MockBookingRepository)(nil).GetDetail), ctx, bookingID)
+}
+
+// GetExpiredBookingsTx mocks base method.
+func (m *MockBookingRepository) GetExpiredBookingsTx(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]booking.Booking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredBookingsTx", ctx, tx, now, limit)
+	ret0, _ := ret[0].([]booking.Booking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredBookingsTx indicates an expected call of GetExpiredBookingsTx.
+func (mr *MockBookingRepositoryMockRecorder) GetExpiredBookingsTx(ctx, tx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredBookingsTx", reflect.TypeOf((*MockBookingRepository)(nil).GetExpiredBookingsTx), ctx, tx, now, limit)
+}
+
+// GetForUpdateTx mocks base method.
+func (m *MockBookingRepository) GetForUpdateTx(ctx context.Context, tx *sql.Tx, bookingID string) (booking.Booking, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdateTx", ctx, tx, bookingID)
+	ret0, _ := ret[0].(booking.Booking)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdateTx indicates an expected call of GetForUpdateTx.
+func (mr *MockBookingRepositoryMockRecorder) GetForUpdateTx(ctx, tx, bookingID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdateTx", reflect.TypeOf((*MockBookingRepository)(nil).GetForUpdateTx), ctx, tx, bookingID)
+}
+
 // GetHistory mocks base method.
 func (m *MockBookingRepository) GetHistory(ctx context.Context, userID int64) ([]booking.BookingHistoryResponse, error) {
 	m.ctrl.T.Helper()
@@ -108,3 +201,90 @@ func (mr *MockBookingRepositoryMockRecorder) GetHistory(ctx, userID interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockBookingRepository)(nil).GetHistory), ctx, userID)
 }
+
+// GetItems mocks base method.
+func (m *MockBookingRepository) GetItems(ctx context.Context, bookingID string) ([]booking.BookingItemResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", ctx, bookingID)
+	ret0, _ := ret[0].([]booking.BookingItemResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockBookingRepositoryMockRecorder) GetItems(ctx, bookingID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockBookingRepository)(nil).GetItems), ctx, bookingID)
+}
+
+// GetLines mocks base method.
+func (m *MockBookingRepository) GetLines(ctx context.Context, bookingIDs []string) ([]booking.Line, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLines", ctx, bookingIDs)
+	ret0, _ := ret[0].([]booking.Line)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLines indicates an expected call of GetLines.
+func (mr *MockBookingRepositoryMockRecorder) GetLines(ctx, bookingIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLines", reflect.TypeOf((*MockBookingRepository)(nil).GetLines), ctx, bookingIDs)
+}
+
+// LockUserEventTx mocks base method.
+func (m *MockBookingRepository) LockUserEventTx(ctx context.Context, tx *sql.Tx, userID int64, eventID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockUserEventTx", ctx, tx, userID, eventID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockUserEventTx indicates an expected call of LockUserEventTx.
+func (mr *MockBookingRepositoryMockRecorder) LockUserEventTx(ctx, tx, userID, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockUserEventTx", reflect.TypeOf((*MockBookingRepository)(nil).LockUserEventTx), ctx, tx, userID, eventID)
+}
+
+// MoveItemsTx mocks base method.
+func (m *MockBookingRepository) MoveItemsTx(ctx context.Context, tx *sql.Tx, fromBookingID string, toBookingID string, seatIDs []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveItemsTx", ctx, tx, fromBookingID, toBookingID, seatIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveItemsTx indicates an expected call of MoveItemsTx.
+func (mr *MockBookingRepositoryMockRecorder) MoveItemsTx(ctx, tx, fromBookingID, toBookingID, seatIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveItemsTx", reflect.TypeOf((*MockBookingRepository)(nil).MoveItemsTx), ctx, tx, fromBookingID, toBookingID, seatIDs)
+}
+
+// RecalculateTotalTx mocks base method.
+func (m *MockBookingRepository) RecalculateTotalTx(ctx context.Context, tx *sql.Tx, bookingID string, fees []event.Fee, taxPercent float64) (money.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecalculateTotalTx", ctx, tx, bookingID, fees, taxPercent)
+	ret0, _ := ret[0].(money.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecalculateTotalTx indicates an expected call of RecalculateTotalTx.
+func (mr *MockBookingRepositoryMockRecorder) RecalculateTotalTx(ctx, tx, bookingID, fees, taxPercent interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecalculateTotalTx", reflect.TypeOf((*MockBookingRepository)(nil).RecalculateTotalTx), ctx, tx, bookingID, fees, taxPercent)
+}
+
+// UpdateStatusTx mocks base method.
+func (m *MockBookingRepository) UpdateStatusTx(ctx context.Context, tx *sql.Tx, bookingID string, from booking.BookingStatus, to booking.BookingStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatusTx", ctx, tx, bookingID, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatusTx indicates an expected call of UpdateStatusTx.
+func (mr *MockBookingRepositoryMockRecorder) UpdateStatusTx(ctx, tx, bookingID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatusTx", reflect.TypeOf((*MockBookingRepository)(nil).UpdateStatusTx), ctx, tx, bookingID, from, to)
+}
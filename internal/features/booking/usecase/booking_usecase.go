@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"log"
+	"slices"
 	"time"
 
 	"github.com/codepnw/stdlib-ticket-system/internal/authcontext"
@@ -11,94 +12,429 @@ import (
 	"github.com/codepnw/stdlib-ticket-system/internal/errs"
 	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
 	bookingrepo "github.com/codepnw/stdlib-ticket-system/internal/features/booking/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	eventrepo "github.com/codepnw/stdlib-ticket-system/internal/features/event/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/promo"
+	promorepo "github.com/codepnw/stdlib-ticket-system/internal/features/promo/repo"
 	"github.com/codepnw/stdlib-ticket-system/internal/features/seat"
 	seatrepo "github.com/codepnw/stdlib-ticket-system/internal/features/seat/repo"
 	"github.com/codepnw/stdlib-ticket-system/pkg/database"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+	"github.com/codepnw/stdlib-ticket-system/pkg/ticketcode"
 )
 
+// expireBatchSize : max bookings released per sweep
+const expireBatchSize = 100
+
 type BookingUsecase interface {
-	CreateBooking(ctx context.Context, eventID int64, seatIDs []int64) error
+	CreateBooking(ctx context.Context, eventID int64, seats []booking.SeatTicket, promoCode string) (displayBooking, error)
+	CreateBestAvailableBooking(ctx context.Context, eventID int64, zone string, quantity int, ticketTypeID int64, promoCode string) (displayBooking, error)
 	GetBookingHistory(ctx context.Context) ([]displayBookingHistory, error)
-	CancelBooking(ctx context.Context, bookingID string) error
+	GetBookingDetail(ctx context.Context, bookingID string) (displayBookingDetail, error)
+	GetTickets(ctx context.Context, bookingID string) (displayTickets, error)
+	CancelBooking(ctx context.Context, bookingID string) (displayCancellation, error)
+	CancelSeats(ctx context.Context, bookingID string, seatIDs []int64) (displaySeatCancellation, error)
+	ExpireBookings(ctx context.Context) error
 }
 
 type bookingUsecase struct {
-	location *time.Location
-	tx       database.TxManager
-	bookRepo bookingrepo.BookingRepository
-	seatRepo seatrepo.SeatRepository
+	location     *time.Location
+	holdDuration time.Duration
+	lockStrategy seat.LockStrategy
+	tx           database.TxManager
+	bookRepo     bookingrepo.BookingRepository
+	seatRepo     seatrepo.SeatRepository
+	eventRepo    eventrepo.EventRepository
+	promoRepo    promorepo.PromoRepository
+	refunder     Refunder
+	offerer      SeatOfferer
+	notifier     SeatNotifier
+	signer       ticketcode.Signer
 }
 
-func NewBookingUsecase(location *time.Location, tx database.TxManager, bookRepo bookingrepo.BookingRepository, seatRepo seatrepo.SeatRepository) BookingUsecase {
+func NewBookingUsecase(location *time.Location, holdDuration time.Duration, lockStrategy seat.LockStrategy, tx database.TxManager, bookRepo bookingrepo.BookingRepository, seatRepo seatrepo.SeatRepository, eventRepo eventrepo.EventRepository, promoRepo promorepo.PromoRepository, refunder Refunder, offerer SeatOfferer, notifier SeatNotifier, signer ticketcode.Signer) BookingUsecase {
 	return &bookingUsecase{
-		location: location,
-		tx:       tx,
-		bookRepo: bookRepo,
-		seatRepo: seatRepo,
+		location:     location,
+		holdDuration: holdDuration,
+		lockStrategy: lockStrategy,
+		tx:           tx,
+		bookRepo:     bookRepo,
+		seatRepo:     seatRepo,
+		eventRepo:    eventRepo,
+		promoRepo:    promoRepo,
+		refunder:     refunder,
+		offerer:      offerer,
+		notifier:     notifier,
+		signer:       signer,
 	}
 }
 
-func (u *bookingUsecase) CreateBooking(ctx context.Context, eventID int64, seatIDs []int64) error {
+type displayBooking struct {
+	ID             string      `json:"id"`
+	EventID        int64       `json:"event_id"`
+	TotalAmount    money.Money `json:"total_amount"`
+	DiscountAmount money.Money `json:"discount_amount,omitzero"`
+	Status         string      `json:"status"`
+	ExpiresAt      string      `json:"expires_at"`
+}
+
+func (u *bookingUsecase) CreateBooking(ctx context.Context, eventID int64, seats []booking.SeatTicket, promoCode string) (displayBooking, error) {
 	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
 	defer cancel()
-	
-	userID := authcontext.GetUserID(ctx)
 
-	return u.tx.WithTx(ctx, func(tx *sql.Tx) error {
-		// Get Seats
-		seats, err := u.seatRepo.GetSeatsForUpdateTx(ctx, tx, seatIDs)
+	seatIDs := make([]int64, 0, len(seats))
+	ticketTypes := make(map[int64]int64, len(seats))
+	for _, s := range seats {
+		seatIDs = append(seatIDs, s.SeatID)
+		ticketTypes[s.SeatID] = s.TicketTypeID
+	}
+	ticketTypeOf := func(seatID int64) int64 { return ticketTypes[seatID] }
+
+	return u.createBooking(ctx, eventID, len(seatIDs), promoCode, ticketTypeOf, func(tx *sql.Tx) ([]seat.Seat, error) {
+		// Get Seats, optimistic reads skip the row lock and let holdSeatsTx catch conflicts
+		getSeats := u.seatRepo.GetSeatsForUpdateTx
+		switch u.lockStrategy {
+		case seat.LockOptimistic:
+			getSeats = u.seatRepo.GetSeatsTx
+		case seat.LockSkipLocked:
+			getSeats = u.seatRepo.GetSeatsSkipLockedTx
+		}
+		seats, err := getSeats(ctx, tx, seatIDs)
 		if err != nil {
 			log.Printf("get seats failed: %v", err)
+			return nil, err
+		}
+
+		// Validate Seats
+		if err := seat.CheckAvailable(eventID, seatIDs, seats); err != nil {
+			return nil, err
+		}
+		return seats, nil
+	})
+}
+
+// CreateBestAvailableBooking : let the server pick quantity seats of the zone, see seat.PickBest.
+// Every picked seat gets ticketTypeID, 0 sells them at the zone price.
+func (u *bookingUsecase) CreateBestAvailableBooking(ctx context.Context, eventID int64, zone string, quantity int, ticketTypeID int64, promoCode string) (displayBooking, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	ticketTypeOf := func(int64) int64 { return ticketTypeID }
+
+	return u.createBooking(ctx, eventID, quantity, promoCode, ticketTypeOf, func(tx *sql.Tx) ([]seat.Seat, error) {
+		// Lock the Zone, picks run one at a time so two orders never get the same seats
+		zoneSeats, err := u.seatRepo.GetZoneSeatsForUpdateTx(ctx, tx, eventID, zone)
+		if err != nil {
+			return nil, err
+		}
+		return seat.PickBest(zoneSeats, quantity)
+	})
+}
+
+// createBooking : hold the seats pick returns for a new PENDING booking, pick runs inside the transaction.
+// A promoCode is checked and redeemed in the same transaction, so its caps hold under concurrent orders,
+// and so are the quotas of the ticket types ticketTypeOf chooses per seat.
+func (u *bookingUsecase) createBooking(ctx context.Context, eventID int64, quantity int, promoCode string, ticketTypeOf func(seatID int64) int64, pick func(tx *sql.Tx) ([]seat.Seat, error)) (displayBooking, error) {
+	userID := authcontext.GetUserID(ctx)
+	now := time.Now()
+	expiresAt := now.Add(u.holdDuration)
+
+	// Check Event & Order Limit
+	eventData, err := u.eventRepo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return displayBooking{}, err
+	}
+	if err := eventData.PurchaseLimit(quantity, 0); err != nil {
+		return displayBooking{}, err
+	}
+
+	var created booking.Booking
+	var heldIDs []int64
+	err = u.tx.WithRetryTx(ctx, func(tx *sql.Tx) error {
+		// Check User Limit, the lock keeps concurrent orders of this user in line
+		if err := u.bookRepo.LockUserEventTx(ctx, tx, userID, eventID); err != nil {
 			return err
 		}
-		// Validate Seats Len
-		if len(seats) != len(seatIDs) {
-			return errs.ErrSomeSeatNotAvailable
+		held, err := u.bookRepo.CountUserSeatsTx(ctx, tx, userID, eventID)
+		if err != nil {
+			return err
+		}
+		if err := eventData.PurchaseLimit(quantity, held); err != nil {
+			return err
 		}
 
-		var totalAmount float64
+		// Pick Seats
+		seats, err := pick(tx)
+		if err != nil {
+			return err
+		}
+
+		// Check Ticket Types, their quotas stay locked until commit
+		ticketTypes, err := u.ticketTypesTx(ctx, tx, eventID, seats, ticketTypeOf)
+		if err != nil {
+			return err
+		}
+
+		// Price Seats with their ticket type, or the tier of their zone active now
+		tiers, err := u.eventRepo.GetPriceTiers(ctx, eventID)
+		if err != nil {
+			return err
+		}
+		fees, err := u.eventRepo.GetFees(ctx, eventID)
+		if err != nil {
+			return err
+		}
+
+		totalAmount := money.Zero(eventData.Currency)
+		seatIDs := make([]int64, 0, len(seats))
+		items := make([]booking.BookingItem, 0, len(seats))
+		zones := make([]string, 0, len(seats))
 		for _, s := range seats {
-			if s.Status != seat.StatusAvailable {
-				return errs.ErrSomeSeatNotAvailable
+			ticketTypeID := ticketTypeOf(s.ID)
+			unitPrice, err := seatPrice(tiers, ticketTypes, ticketTypeID, s, now)
+			if err != nil {
+				return err
+			}
+			if totalAmount, err = totalAmount.Add(unitPrice); err != nil {
+				return err
 			}
-			totalAmount += s.Price
+			seatIDs = append(seatIDs, s.ID)
+			items = append(items, booking.BookingItem{SeatID: s.ID, TicketTypeID: ticketTypeID, UnitPrice: unitPrice})
+			zones = append(zones, s.Zone)
 		}
 
-		// Update Seats Status
-		if err := u.seatRepo.UpdateSeatsStatusTx(ctx, tx, seatIDs, string(seat.StatusSold)); err != nil {
+		// Apply Promo, the code stays locked until commit so its uses can't be overtaken
+		var code promo.Promo
+		discount := money.Zero(eventData.Currency)
+		if promoCode != "" {
+			code, discount, err = u.applyPromoTx(ctx, tx, promoCode, userID, eventID, now, items, zones)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Hold Seats until the booking is paid or expires
+		if err := u.holdSeatsTx(ctx, tx, seats, seatIDs); err != nil {
 			log.Printf("update seats failed: %v", err)
 			return err
 		}
 
-		// Create Booking
-		bookingID, err := u.bookRepo.CreateBookingTx(ctx, tx, booking.Booking{
-			UserID:      userID,
-			EventID:     eventID,
-			TotalAmount: totalAmount,
-			Status:      booking.StatusPending,
-		})
+		// Create Booking, priced with the fees and tax of the event
+		price, err := booking.Price(totalAmount, discount, len(seats), fees, eventData.TaxPercent)
+		if err != nil {
+			return err
+		}
+		input := booking.Booking{
+			UserID:         userID,
+			EventID:        eventID,
+			TotalAmount:    price.Total,
+			DiscountAmount: discount,
+			Status:         booking.StatusPending,
+			ExpiresAt:      expiresAt,
+		}
+		bookingID, err := u.bookRepo.CreateBookingTx(ctx, tx, input)
 		if err != nil {
 			log.Printf("create booking failed: %v", err)
 			return err
 		}
 
 		// Create Booking Items
-		if err := u.bookRepo.CreateBookingItemsTx(ctx, tx, bookingID, seatIDs); err != nil {
+		if err := u.bookRepo.CreateBookingItemsTx(ctx, tx, bookingID, items); err != nil {
 			log.Printf("create booking items failed: %v", err)
 			return err
 		}
+
+		// Create Fee & Tax Lines
+		if len(price.Lines) > 0 {
+			if err := u.bookRepo.CreateLinesTx(ctx, tx, bookingID, price.Lines); err != nil {
+				return err
+			}
+		}
+
+		// Redeem Promo
+		if promoCode != "" {
+			if err := u.promoRepo.RedeemTx(ctx, tx, promo.Redemption{
+				PromoID:   code.ID,
+				UserID:    userID,
+				BookingID: bookingID,
+				Discount:  discount,
+			}); err != nil {
+				return err
+			}
+		}
+
+		input.ID = bookingID
+		created = input
+		heldIDs = seatIDs
 		return nil
 	})
+	if err != nil {
+		return displayBooking{}, err
+	}
+	u.notifier.SeatsChanged(ctx, eventID, heldIDs)
+
+	return displayBooking{
+		ID:             created.ID,
+		EventID:        created.EventID,
+		TotalAmount:    created.TotalAmount,
+		DiscountAmount: created.DiscountAmount,
+		Status:         string(created.Status),
+		ExpiresAt:      created.ExpiresAt.In(u.location).Format(time.DateTime),
+	}, nil
+}
+
+// applyPromoTx : lock the code, check its window, scope and caps, and price the discount
+// on the items in its zone. zones[i] is the zone of items[i], each covered item gets its share.
+func (u *bookingUsecase) applyPromoTx(ctx context.Context, tx *sql.Tx, promoCode string, userID, eventID int64, now time.Time, items []booking.BookingItem, zones []string) (promo.Promo, money.Money, error) {
+	code, err := u.promoRepo.GetByCodeForUpdateTx(ctx, tx, promo.NormalizeCode(promoCode))
+	if err != nil {
+		return promo.Promo{}, money.Money{}, err
+	}
+	if err := code.Check(eventID, now); err != nil {
+		return promo.Promo{}, money.Money{}, err
+	}
+
+	used, usedByUser, err := u.promoRepo.CountRedemptionsTx(ctx, tx, code.ID, userID)
+	if err != nil {
+		return promo.Promo{}, money.Money{}, err
+	}
+	if err := code.CheckUsage(used, usedByUser); err != nil {
+		return promo.Promo{}, money.Money{}, err
+	}
+
+	var eligible money.Money
+	var covered []int
+	for i, item := range items {
+		if !code.AppliesToZone(zones[i]) {
+			continue
+		}
+		covered = append(covered, i)
+		if eligible.Currency == "" {
+			eligible = money.Zero(item.UnitPrice.Currency)
+		}
+		if eligible, err = eligible.Add(item.UnitPrice); err != nil {
+			return promo.Promo{}, money.Money{}, err
+		}
+	}
+	discount, err := code.Discount(eligible)
+	if err != nil {
+		return promo.Promo{}, money.Money{}, err
+	}
+	if err := booking.SpreadDiscount(items, covered, discount); err != nil {
+		return promo.Promo{}, money.Money{}, err
+	}
+	return code, discount, nil
+}
+
+// ticketTypesTx : the ticket types of the event by ID when a seat has one chosen, nil otherwise.
+// Types with a quota are locked and checked against their active tickets plus this order.
+func (u *bookingUsecase) ticketTypesTx(ctx context.Context, tx *sql.Tx, eventID int64, seats []seat.Seat, ticketTypeOf func(seatID int64) int64) (map[int64]event.TicketType, error) {
+	requested := make(map[int64]int)
+	for _, s := range seats {
+		if id := ticketTypeOf(s.ID); id != 0 {
+			requested[id]++
+		}
+	}
+	if len(requested) == 0 {
+		return nil, nil
+	}
+
+	types, err := u.eventRepo.GetTicketTypes(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]event.TicketType, len(types))
+	for _, t := range types {
+		byID[t.ID] = t
+	}
+
+	var capped []int64
+	for id := range requested {
+		t, ok := byID[id]
+		if !ok {
+			return nil, errs.ErrTicketTypeNotOffered
+		}
+		if t.Quota > 0 {
+			capped = append(capped, id)
+		}
+	}
+	if len(capped) == 0 {
+		return byID, nil
+	}
+	slices.Sort(capped)
+
+	if err := u.eventRepo.LockTicketTypesTx(ctx, tx, capped); err != nil {
+		return nil, err
+	}
+	sold, err := u.bookRepo.CountTicketTypesTx(ctx, tx, capped)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range capped {
+		if err := byID[id].CheckQuota(sold[id], requested[id]); err != nil {
+			return nil, err
+		}
+	}
+	return byID, nil
+}
+
+// seatPrice : the zone has to be on sale at `at`, a ticket type's price then replaces the tier price
+func seatPrice(tiers []event.PriceTier, ticketTypes map[int64]event.TicketType, ticketTypeID int64, s seat.Seat, at time.Time) (money.Money, error) {
+	price, err := event.ZonePrice(tiers, s.Zone, s.Price, at)
+	if err != nil || ticketTypeID == 0 {
+		return price, err
+	}
+	price, ok := ticketTypes[ticketTypeID].PriceIn(s.Zone)
+	if !ok {
+		return money.Money{}, errs.ErrTicketTypeNotOffered
+	}
+	return price, nil
+}
+
+// holdSeatsTx : mark the seats RESERVED, optimistic holds fail with ErrSeatVersionConflict when a seat changed after it was read
+func (u *bookingUsecase) holdSeatsTx(ctx context.Context, tx *sql.Tx, seats []seat.Seat, seatIDs []int64) error {
+	if u.lockStrategy == seat.LockOptimistic {
+		return u.seatRepo.ReserveSeatsVersionedTx(ctx, tx, seats)
+	}
+	return u.seatRepo.UpdateSeatsStatusTx(ctx, tx, seatIDs, string(seat.StatusReserved))
 }
 
 type displayBookingHistory struct {
-	ID          string  `json:"id" `
-	EventName   string  `json:"event_name" `
-	TotalAmount float64 `json:"total_amount" `
-	Status      string  `json:"status" `
-	SeatNumbers string  `json:"seat_numbers"`
-	EventDate   string  `json:"event_date" `
-	CreatedAt   string  `json:"created_at" `
+	ID          string           `json:"id" `
+	EventName   string           `json:"event_name" `
+	TotalAmount money.Money      `json:"total_amount" `
+	Breakdown   displayBreakdown `json:"breakdown"`
+	Status      string           `json:"status" `
+	SeatNumbers string           `json:"seat_numbers"`
+	EventDate   string           `json:"event_date" `
+	CreatedAt   string           `json:"created_at" `
+}
+
+// displayBreakdown : Total = Subtotal - Discount + Fees + Taxes
+type displayBreakdown struct {
+	Subtotal money.Money   `json:"subtotal"`
+	Discount money.Money   `json:"discount,omitzero"`
+	Fees     []displayLine `json:"fees,omitempty"`
+	Taxes    []displayLine `json:"taxes,omitempty"`
+	Total    money.Money   `json:"total"`
+}
+
+type displayLine struct {
+	Name   string      `json:"name"`
+	Amount money.Money `json:"amount"`
+}
+
+func newDisplayBreakdown(subtotal, discount, total money.Money, lines []booking.Line) displayBreakdown {
+	b := displayBreakdown{Subtotal: subtotal, Discount: discount, Total: total}
+	for _, l := range lines {
+		line := displayLine{Name: l.Name, Amount: l.Amount}
+		if l.Kind == booking.LineTax {
+			b.Taxes = append(b.Taxes, line)
+		} else {
+			b.Fees = append(b.Fees, line)
+		}
+	}
+	return b
 }
 
 func (u *bookingUsecase) GetBookingHistory(ctx context.Context) ([]displayBookingHistory, error) {
@@ -111,6 +447,22 @@ func (u *bookingUsecase) GetBookingHistory(ctx context.Context) ([]displayBookin
 	if err != nil {
 		return nil, err
 	}
+	if len(history) == 0 {
+		return nil, nil
+	}
+
+	bookingIDs := make([]string, 0, len(history))
+	for _, h := range history {
+		bookingIDs = append(bookingIDs, h.ID)
+	}
+	lines, err := u.bookRepo.GetLines(ctx, bookingIDs)
+	if err != nil {
+		return nil, err
+	}
+	linesByBooking := make(map[string][]booking.Line, len(history))
+	for _, l := range lines {
+		linesByBooking[l.BookingID] = append(linesByBooking[l.BookingID], l)
+	}
 
 	var result []displayBookingHistory
 	timeFormat := time.DateTime
@@ -120,6 +472,7 @@ func (u *bookingUsecase) GetBookingHistory(ctx context.Context) ([]displayBookin
 			ID:          h.ID,
 			EventName:   h.EventName,
 			TotalAmount: h.TotalAmount,
+			Breakdown:   newDisplayBreakdown(h.Subtotal, h.DiscountAmount, h.TotalAmount, linesByBooking[h.ID]),
 			Status:      h.Status,
 			SeatNumbers: h.SeatNumbers,
 			// Format time.Time -> Asia/Bangkok "2006-01-02 15:04:05"
@@ -130,40 +483,477 @@ func (u *bookingUsecase) GetBookingHistory(ctx context.Context) ([]displayBookin
 	return result, nil
 }
 
-func (u *bookingUsecase) CancelBooking(ctx context.Context, bookingID string) error {
+type displayBookingDetail struct {
+	ID             string               `json:"id"`
+	EventID        int64                `json:"event_id"`
+	EventName      string               `json:"event_name"`
+	EventDate      string               `json:"event_date"`
+	TotalAmount    money.Money          `json:"total_amount"`
+	DiscountAmount money.Money          `json:"discount_amount,omitzero"`
+	Breakdown      displayBreakdown     `json:"breakdown"`
+	Status         string               `json:"status"`
+	CreatedAt      string               `json:"created_at"`
+	ExpiresAt      string               `json:"expires_at,omitempty"`
+	Items          []displayBookingItem `json:"items"`
+}
+
+type displayBookingItem struct {
+	SeatID      int64       `json:"seat_id"`
+	SeatNumber  string      `json:"seat_number"`
+	Zone        string      `json:"zone"`
+	TicketType  string      `json:"ticket_type,omitempty"`
+	UnitPrice   money.Money `json:"unit_price"`
+	ReleasedAt  string      `json:"released_at,omitempty"`
+	CheckedInAt string      `json:"checked_in_at,omitempty"`
+}
+
+func (u *bookingUsecase) GetBookingDetail(ctx context.Context, bookingID string) (displayBookingDetail, error) {
 	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
 	defer cancel()
 
-	userID := authcontext.GetUserID(ctx)
+	// 1. Check Booking ID
+	detail, err := u.bookRepo.GetDetail(ctx, bookingID)
+	if err != nil {
+		return displayBookingDetail{}, err
+	}
+	// 2. Check Ownership
+	if err := checkOwnership(ctx, detail.Booking); err != nil {
+		return displayBookingDetail{}, err
+	}
+	// 3. Get Items & Lines
+	items, err := u.bookRepo.GetItems(ctx, bookingID)
+	if err != nil {
+		return displayBookingDetail{}, err
+	}
+	lines, err := u.bookRepo.GetLines(ctx, []string{bookingID})
+	if err != nil {
+		return displayBookingDetail{}, err
+	}
+
+	timeFormat := time.DateTime
+	result := displayBookingDetail{
+		ID:             detail.ID,
+		EventID:        detail.EventID,
+		EventName:      detail.EventName,
+		EventDate:      detail.EventDate.In(u.location).Format(timeFormat),
+		TotalAmount:    detail.TotalAmount,
+		DiscountAmount: detail.DiscountAmount,
+		Status:         string(detail.Status),
+		CreatedAt:      detail.CreatedAt.In(u.location).Format(timeFormat),
+		Items:          make([]displayBookingItem, 0, len(items)),
+	}
+	if !detail.ExpiresAt.IsZero() {
+		result.ExpiresAt = detail.ExpiresAt.In(u.location).Format(timeFormat)
+	}
+
+	for _, i := range items {
+		item := displayBookingItem{
+			SeatID:     i.SeatID,
+			SeatNumber: i.SeatNumber,
+			Zone:       i.Zone,
+			TicketType: i.TicketType,
+			UnitPrice:  i.UnitPrice,
+		}
+		if i.ReleasedAt != nil {
+			item.ReleasedAt = i.ReleasedAt.In(u.location).Format(timeFormat)
+		}
+		if i.CheckedInAt != nil {
+			item.CheckedInAt = i.CheckedInAt.In(u.location).Format(timeFormat)
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	// 4. Break Down, the subtotal counts the seats still held
+	subtotal := money.Zero(detail.TotalAmount.Currency)
+	for _, i := range items {
+		if i.ReleasedAt != nil {
+			continue
+		}
+		if subtotal, err = subtotal.Add(i.UnitPrice); err != nil {
+			return displayBookingDetail{}, err
+		}
+	}
+	result.Breakdown = newDisplayBreakdown(subtotal, detail.DiscountAmount, detail.TotalAmount, lines)
+	return result, nil
+}
+
+type displayTickets struct {
+	BookingID string          `json:"booking_id"`
+	EventID   int64           `json:"event_id"`
+	EventName string          `json:"event_name"`
+	EventDate string          `json:"event_date"`
+	Tickets   []displayTicket `json:"tickets"`
+}
+
+type displayTicket struct {
+	SeatID     int64  `json:"seat_id"`
+	SeatNumber string `json:"seat_number"`
+	Zone       string `json:"zone"`
+	Code       string `json:"code"`
+}
+
+func (u *bookingUsecase) GetTickets(ctx context.Context, bookingID string) (displayTickets, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	// 1. Check Booking ID
+	detail, err := u.bookRepo.GetDetail(ctx, bookingID)
+	if err != nil {
+		return displayTickets{}, err
+	}
+	// 2. Check Ownership
+	if err := checkOwnership(ctx, detail.Booking); err != nil {
+		return displayTickets{}, err
+	}
+	// 3. Tickets are only issued once paid
+	if detail.Status != booking.StatusPaid {
+		return displayTickets{}, errs.ErrBookingNotPaid
+	}
+	// 4. Get Items
+	items, err := u.bookRepo.GetItems(ctx, bookingID)
+	if err != nil {
+		return displayTickets{}, err
+	}
+
+	result := displayTickets{
+		BookingID: detail.ID,
+		EventID:   detail.EventID,
+		EventName: detail.EventName,
+		EventDate: detail.EventDate.In(u.location).Format(time.DateTime),
+		Tickets:   make([]displayTicket, 0, len(items)),
+	}
+	for _, i := range items {
+		// Seats cancelled from the booking have no ticket
+		if i.ReleasedAt != nil {
+			continue
+		}
+		result.Tickets = append(result.Tickets, displayTicket{
+			SeatID:     i.SeatID,
+			SeatNumber: i.SeatNumber,
+			Zone:       i.Zone,
+			Code: u.signer.Sign(ticketcode.Claims{
+				BookingID: detail.ID,
+				SeatID:    i.SeatID,
+				EventID:   detail.EventID,
+			}),
+		})
+	}
+	return result, nil
+}
+
+// checkOwnership : only the user who made the booking can see or change it
+func checkOwnership(ctx context.Context, b booking.Booking) error {
+	if authcontext.GetUserID(ctx) != b.UserID {
+		return errs.ErrBookingNotOwned
+	}
+	return nil
+}
+
+type displayCancellation struct {
+	BookingID    string      `json:"booking_id"`
+	Status       string      `json:"status"`
+	RefundAmount money.Money `json:"refund_amount"`
+}
+
+func (u *bookingUsecase) CancelBooking(ctx context.Context, bookingID string) (displayCancellation, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
 
 	// 1. Check Booking ID
 	bookData, err := u.bookRepo.GetByID(ctx, bookingID)
 	if err != nil {
+		return displayCancellation{}, err
+	}
+	// 2. Check Ownership
+	if err := checkOwnership(ctx, bookData); err != nil {
+		return displayCancellation{}, err
+	}
+	// 3. Check Status, a paid booking is refunded instead of cancelled
+	target := booking.CancelStatus(bookData.Status)
+	if err := booking.Transition(bookData.Status, target); err != nil {
+		return displayCancellation{}, err
+	}
+	// 4. Paid Status goes through the refund policy
+	if target == booking.StatusRefunded {
+		return u.refundBooking(ctx, bookData)
+	}
+
+	var released []int64
+	err = u.tx.WithTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		released, err = u.cancelBookingTx(ctx, tx, bookData)
 		return err
+	})
+	if err != nil {
+		return displayCancellation{}, err
+	}
+	u.notifier.SeatsChanged(ctx, bookData.EventID, released)
+
+	return displayCancellation{
+		BookingID:    bookData.ID,
+		Status:       string(booking.StatusCancelled),
+		RefundAmount: money.Zero(bookData.TotalAmount.Currency),
+	}, nil
+}
+
+// cancelBookingTx : cancel an unpaid booking, release its seats and offer them to the waitlist
+func (u *bookingUsecase) cancelBookingTx(ctx context.Context, tx *sql.Tx, bookData booking.Booking) ([]int64, error) {
+	// 1. Cancel Booking
+	if err := u.bookRepo.UpdateStatusTx(ctx, tx, bookData.ID, bookData.Status, booking.StatusCancelled); err != nil {
+		return nil, err
+	}
+
+	// 2. Cancel Seats
+	released, err := u.seatRepo.CancelSeatsTx(ctx, tx, bookData.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Offer Seats to the waitlist
+	if err := u.offerer.OfferSeatsTx(ctx, tx, bookData.EventID); err != nil {
+		return nil, err
+	}
+	return released, nil
+}
+
+func (u *bookingUsecase) refundBooking(ctx context.Context, bookData booking.Booking) (displayCancellation, error) {
+	var refunded money.Money
+	var released []int64
+
+	err := u.tx.WithTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		refunded, released, err = u.refundBookingTx(ctx, tx, bookData)
+		return err
+	})
+	if err != nil {
+		return displayCancellation{}, err
+	}
+	u.notifier.SeatsChanged(ctx, bookData.EventID, released)
+
+	// Send the Refund, a failed one stays PENDING for the refund worker
+	if err := u.refunder.SendRefunds(ctx, bookData.ID); err != nil {
+		log.Printf("send refund for booking %s failed, retrying later: %v", bookData.ID, err)
+	}
+
+	return displayCancellation{
+		BookingID:    bookData.ID,
+		Status:       string(booking.StatusRefunded),
+		RefundAmount: refunded,
+	}, nil
+}
+
+// refundBookingTx : refund a paid booking by the refund policy and release its seats,
+// returns the refund amount and the freed seat IDs
+func (u *bookingUsecase) refundBookingTx(ctx context.Context, tx *sql.Tx, bookData booking.Booking) (money.Money, []int64, error) {
+	// 1. Refund Booking
+	if err := u.bookRepo.UpdateStatusTx(ctx, tx, bookData.ID, booking.StatusPaid, booking.StatusRefunded); err != nil {
+		return money.Money{}, nil, err
+	}
+
+	// 2. Release Seats & offer them to the waitlist
+	released, err := u.seatRepo.CancelSeatsTx(ctx, tx, bookData.ID)
+	if err != nil {
+		return money.Money{}, nil, err
+	}
+	if err := u.offerer.OfferSeatsTx(ctx, tx, bookData.EventID); err != nil {
+		return money.Money{}, nil, err
+	}
+
+	// 3. Refund Money
+	refunded, err := u.refunder.RefundTx(ctx, tx, bookData, bookData.TotalAmount, true)
+	if err != nil {
+		return money.Money{}, nil, err
 	}
-	// 2. Check Ownership (userID != booking.UserID)
-	if userID != bookData.UserID {
-		return errs.ErrCancelOtherBooking
+	return refunded, released, nil
+}
+
+type displaySeatCancellation struct {
+	BookingID      string      `json:"booking_id"`
+	Status         string      `json:"status"`
+	RemovedSeatIDs []int64     `json:"removed_seat_ids"`
+	TotalAmount    money.Money `json:"total_amount"`
+	RefundAmount   money.Money `json:"refund_amount"`
+}
+
+// CancelSeats : remove some seats from a booking, removing the last seat cancels the whole booking
+func (u *bookingUsecase) CancelSeats(ctx context.Context, bookingID string, seatIDs []int64) (displaySeatCancellation, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	// 1. Check Booking ID
+	bookData, err := u.bookRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return displaySeatCancellation{}, err
 	}
-	// 3. Check Cancelled Status
-	if bookData.Status == booking.StatusCancelled {
-		return errs.ErrBookingIsCancel
+	// 2. Check Ownership
+	if err := checkOwnership(ctx, bookData); err != nil {
+		return displaySeatCancellation{}, err
 	}
-	// 4. Check Paid Status cannot cancel
-	if bookData.Status == booking.StatusPaid {
-		return errs.ErrBookingIsPaid
+	// 3. Check Status, seats can only leave a booking that could be cancelled
+	if err := booking.Transition(bookData.Status, booking.CancelStatus(bookData.Status)); err != nil {
+		return displaySeatCancellation{}, err
 	}
 
-	return u.tx.WithTx(ctx, func(tx *sql.Tx) error {
-		// 5. Cancel Booking
-		if err := u.bookRepo.CancelBookingTx(ctx, tx, bookData.ID); err != nil {
+	eventData, err := u.eventRepo.GetEventByID(ctx, bookData.EventID)
+	if err != nil {
+		return displaySeatCancellation{}, err
+	}
+	fees, err := u.eventRepo.GetFees(ctx, bookData.EventID)
+	if err != nil {
+		return displaySeatCancellation{}, err
+	}
+
+	var removeIDs, released []int64
+	var locked booking.Booking
+	var status booking.BookingStatus
+	var total money.Money
+	refunded := money.Zero(bookData.TotalAmount.Currency)
+	err = u.tx.WithTx(ctx, func(tx *sql.Tx) error {
+		// 4. Lock Booking, a concurrent cancel must see the seats this one removes
+		var err error
+		locked, err = u.bookRepo.GetForUpdateTx(ctx, tx, bookingID)
+		if err != nil {
+			return err
+		}
+		status = locked.Status
+		target := booking.CancelStatus(locked.Status)
+		if err := booking.Transition(locked.Status, target); err != nil {
+			return err
+		}
+
+		// 5. Check Seats belong to the booking
+		activeIDs, err := u.bookRepo.GetActiveSeatIDsTx(ctx, tx, bookingID)
+		if err != nil {
 			return err
 		}
+		active := make(map[int64]bool, len(activeIDs))
+		for _, id := range activeIDs {
+			active[id] = true
+		}
 
-		// 6. Cancel Seats
-		if err := u.seatRepo.CancelSeatsTx(ctx, tx, bookData.ID); err != nil {
+		removeIDs = make([]int64, 0, len(seatIDs))
+		seen := make(map[int64]bool, len(seatIDs))
+		for _, id := range seatIDs {
+			if !active[id] {
+				return errs.ErrSeatNotInBooking
+			}
+			if !seen[id] {
+				seen[id] = true
+				removeIDs = append(removeIDs, id)
+			}
+		}
+
+		// 6. Last Seats cancel the whole booking
+		if len(removeIDs) == len(active) {
+			total, status = money.Zero(locked.TotalAmount.Currency), target
+			if target == booking.StatusRefunded {
+				refunded, released, err = u.refundBookingTx(ctx, tx, locked)
+				return err
+			}
+			released, err = u.cancelBookingTx(ctx, tx, locked)
+			return err
+		}
+
+		// 7. Remove Items, free Seats & offer them to the waitlist
+		if err := u.seatRepo.RemoveSeatsTx(ctx, tx, locked.ID, removeIDs); err != nil {
+			return err
+		}
+		if err := u.offerer.OfferSeatsTx(ctx, tx, locked.EventID); err != nil {
+			return err
+		}
+		released = removeIDs
+
+		// 8. Price the remaining Seats again, their fees and tax shrink with them
+		total, err = u.bookRepo.RecalculateTotalTx(ctx, tx, locked.ID, fees, eventData.TaxPercent)
+		if err != nil {
 			return err
 		}
+
+		// 9. Paid Booking gets the drop in total back by the refund policy,
+		// the seats less their discount share plus their part of the fees and tax
+		removed, err := locked.TotalAmount.Sub(total)
+		if err != nil {
+			return err
+		}
+		if locked.Status == booking.StatusPaid && removed.IsPositive() {
+			refunded, err = u.refunder.RefundTx(ctx, tx, locked, removed, false)
+			if err != nil {
+				return err
+			}
+		}
 		return nil
 	})
+	if err != nil {
+		return displaySeatCancellation{}, err
+	}
+	u.notifier.SeatsChanged(ctx, locked.EventID, released)
+
+	// 10. Send the Refund, a failed one stays PENDING for the refund worker
+	if locked.Status == booking.StatusPaid {
+		if err := u.refunder.SendRefunds(ctx, locked.ID); err != nil {
+			log.Printf("send refund for booking %s failed, retrying later: %v", locked.ID, err)
+		}
+	}
+
+	return displaySeatCancellation{
+		BookingID:      locked.ID,
+		Status:         string(status),
+		RemovedSeatIDs: removeIDs,
+		TotalAmount:    total,
+		RefundAmount:   refunded,
+	}, nil
+}
+
+// ExpireBookings : fail PENDING bookings past their hold and release the seats
+func (u *bookingUsecase) ExpireBookings(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	var expired int
+	var released map[int64][]int64
+	err := u.tx.WithRetryTx(ctx, func(tx *sql.Tx) error {
+		released = make(map[int64][]int64)
+		bookings, err := u.bookRepo.GetExpiredBookingsTx(ctx, tx, time.Now(), expireBatchSize)
+		if err != nil {
+			return err
+		}
+
+		var eventIDs []int64
+		for _, b := range bookings {
+			// 1. Fail Booking
+			if err := u.bookRepo.UpdateStatusTx(ctx, tx, b.ID, booking.StatusPending, booking.StatusFailed); err != nil {
+				return err
+			}
+
+			// 2. Release Seats
+			seatIDs, err := u.seatRepo.CancelSeatsTx(ctx, tx, b.ID)
+			if err != nil {
+				return err
+			}
+			if _, ok := released[b.EventID]; !ok {
+				eventIDs = append(eventIDs, b.EventID)
+			}
+			released[b.EventID] = append(released[b.EventID], seatIDs...)
+		}
+
+		// 3. Offer released Seats to the waitlists
+		for _, eventID := range eventIDs {
+			if err := u.offerer.OfferSeatsTx(ctx, tx, eventID); err != nil {
+				return err
+			}
+		}
+		expired = len(bookings)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for eventID, seatIDs := range released {
+		u.notifier.SeatsChanged(ctx, eventID, seatIDs)
+	}
+
+	if expired > 0 {
+		log.Printf("expired %d bookings", expired)
+	}
+	return nil
 }
@@ -12,21 +12,47 @@ import (
 	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
 	bookingrepo "github.com/codepnw/stdlib-ticket-system/internal/features/booking/repo"
 	bookingusecase "github.com/codepnw/stdlib-ticket-system/internal/features/booking/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	eventrepo "github.com/codepnw/stdlib-ticket-system/internal/features/event/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/promo"
+	promorepo "github.com/codepnw/stdlib-ticket-system/internal/features/promo/repo"
 	"github.com/codepnw/stdlib-ticket-system/internal/features/seat"
 	seatrepo "github.com/codepnw/stdlib-ticket-system/internal/features/seat/repo"
 	"github.com/codepnw/stdlib-ticket-system/pkg/database"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+	"github.com/codepnw/stdlib-ticket-system/pkg/ticketcode"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
 
 var ErrMockDBError = errors.New("db error")
 
+var testSigner, _ = ticketcode.NewSigner("test-signing-key")
+
+// thb : whole baht in satang
+func thb(baht int64) money.Money {
+	return money.New(baht*100, "THB")
+}
+
+// seatTickets : the seats booked at their zone price
+func seatTickets(seatIDs []int64) []booking.SeatTicket {
+	seats := make([]booking.SeatTicket, 0, len(seatIDs))
+	for _, id := range seatIDs {
+		seats = append(seats, booking.SeatTicket{SeatID: id})
+	}
+	return seats
+}
+
 type mockTx struct{}
 
 func (m mockTx) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
 	return fn(nil)
 }
 
+func (m mockTx) WithRetryTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	return fn(nil)
+}
+
 func TestCreateBooking(t *testing.T) {
 	type testCase struct {
 		name    string
@@ -36,6 +62,7 @@ func TestCreateBooking(t *testing.T) {
 			tx database.TxManager,
 			mockBook bookingrepo.MockBookingRepository,
 			mockSeat seatrepo.MockSeatRepository,
+			mockEvent eventrepo.MockEventRepository,
 			eventID int64,
 			seatIDs []int64,
 		)
@@ -47,32 +74,84 @@ func TestCreateBooking(t *testing.T) {
 			name:    "success",
 			eventID: 10,
 			seatIDs: []int64{11, 20, 30},
-			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, eventID int64, seatIDs []int64) {
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockEvent eventrepo.MockEventRepository, eventID int64, seatIDs []int64) {
+				mockEvent.EXPECT().GetEventByID(gomock.Any(), eventID).Return(event.Event{ID: eventID, Currency: "THB"}, nil).Times(1)
+
+				mockBook.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), eventID).Return(nil).Times(1)
+
+				mockBook.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), eventID).Return(0, nil).Times(1)
+
 				mockSeats := []seat.Seat{
-					{ID: 11, Price: 100, Status: seat.StatusAvailable},
-					{ID: 20, Price: 100, Status: seat.StatusAvailable},
-					{ID: 30, Price: 200, Status: seat.StatusAvailable},
+					{ID: 11, EventID: 10, Price: thb(100), Status: seat.StatusAvailable},
+					{ID: 20, EventID: 10, Price: thb(100), Status: seat.StatusAvailable},
+					{ID: 30, EventID: 10, Price: thb(200), Status: seat.StatusAvailable},
 				}
 				mockSeat.EXPECT().GetSeatsForUpdateTx(gomock.Any(), gomock.Any(), seatIDs).Return(mockSeats, nil).Times(1)
 
-				mockSeat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), seatIDs, string(seat.StatusSold)).Return(nil).Times(1)
+				mockEvent.EXPECT().GetPriceTiers(gomock.Any(), eventID).Return(nil, nil).Times(1)
+
+				mockEvent.EXPECT().GetFees(gomock.Any(), eventID).Return(nil, nil).Times(1)
+
+				mockSeat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), seatIDs, string(seat.StatusReserved)).Return(nil).Times(1)
 
 				mockBookID := "mock-uuid-1"
 				mockBook.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).Return(mockBookID, nil).Times(1)
 
-				mockBook.EXPECT().CreateBookingItemsTx(gomock.Any(), gomock.Any(), mockBookID, seatIDs).Return(nil).Times(1)
+				mockItems := []booking.BookingItem{
+					{SeatID: 11, UnitPrice: thb(100)},
+					{SeatID: 20, UnitPrice: thb(100)},
+					{SeatID: 30, UnitPrice: thb(200)},
+				}
+				mockBook.EXPECT().CreateBookingItemsTx(gomock.Any(), gomock.Any(), mockBookID, mockItems).Return(nil).Times(1)
 			},
 			expectedErr: nil,
 		},
+		{
+			name:    "fail seats per order limit",
+			eventID: 10,
+			seatIDs: []int64{11, 20, 30},
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockEvent eventrepo.MockEventRepository, eventID int64, seatIDs []int64) {
+				mockEvent.EXPECT().GetEventByID(gomock.Any(), eventID).Return(event.Event{ID: eventID, Currency: "THB", MaxSeatsPerOrder: 2}, nil).Times(1)
+			},
+			expectedErr: errs.ErrPurchaseLimitExceeded,
+		},
+		{
+			name:    "fail seats per user limit",
+			eventID: 10,
+			seatIDs: []int64{11, 20},
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockEvent eventrepo.MockEventRepository, eventID int64, seatIDs []int64) {
+				mockEvent.EXPECT().GetEventByID(gomock.Any(), eventID).Return(event.Event{ID: eventID, Currency: "THB", MaxSeatsPerUser: 4}, nil).Times(1)
+
+				mockBook.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), eventID).Return(nil).Times(1)
+
+				mockBook.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), eventID).Return(3, nil).Times(1)
+			},
+			expectedErr: errs.ErrPurchaseLimitExceeded,
+		},
+		{
+			name:    "fail event not found",
+			eventID: 10,
+			seatIDs: []int64{11},
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockEvent eventrepo.MockEventRepository, eventID int64, seatIDs []int64) {
+				mockEvent.EXPECT().GetEventByID(gomock.Any(), eventID).Return(event.Event{}, errs.ErrEventNotFound).Times(1)
+			},
+			expectedErr: errs.ErrEventNotFound,
+		},
 		{
 			name:    "fail some seat not available",
 			eventID: 10,
 			seatIDs: []int64{11, 20, 30},
-			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, eventID int64, seatIDs []int64) {
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockEvent eventrepo.MockEventRepository, eventID int64, seatIDs []int64) {
+				mockEvent.EXPECT().GetEventByID(gomock.Any(), eventID).Return(event.Event{ID: eventID, Currency: "THB"}, nil).Times(1)
+
+				mockBook.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), eventID).Return(nil).Times(1)
+
+				mockBook.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), eventID).Return(0, nil).Times(1)
+
 				mockSeats := []seat.Seat{
-					{ID: 11, Price: 100, Status: seat.StatusSold},
-					{ID: 20, Price: 100, Status: seat.StatusAvailable},
-					{ID: 30, Price: 200, Status: seat.StatusAvailable},
+					{ID: 11, EventID: 10, Price: thb(100), Status: seat.StatusSold},
+					{ID: 20, EventID: 10, Price: thb(100), Status: seat.StatusAvailable},
+					{ID: 30, EventID: 10, Price: thb(200), Status: seat.StatusAvailable},
 				}
 				mockSeat.EXPECT().GetSeatsForUpdateTx(gomock.Any(), gomock.Any(), seatIDs).Return(mockSeats, nil).Times(1)
 			},
@@ -82,15 +161,25 @@ func TestCreateBooking(t *testing.T) {
 			name:    "fail update seats",
 			eventID: 10,
 			seatIDs: []int64{11, 20, 30},
-			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, eventID int64, seatIDs []int64) {
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockEvent eventrepo.MockEventRepository, eventID int64, seatIDs []int64) {
+				mockEvent.EXPECT().GetEventByID(gomock.Any(), eventID).Return(event.Event{ID: eventID, Currency: "THB"}, nil).Times(1)
+
+				mockBook.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), eventID).Return(nil).Times(1)
+
+				mockBook.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), eventID).Return(0, nil).Times(1)
+
 				mockSeats := []seat.Seat{
-					{ID: 11, Price: 100, Status: seat.StatusAvailable},
-					{ID: 20, Price: 100, Status: seat.StatusAvailable},
-					{ID: 30, Price: 200, Status: seat.StatusAvailable},
+					{ID: 11, EventID: 10, Price: thb(100), Status: seat.StatusAvailable},
+					{ID: 20, EventID: 10, Price: thb(100), Status: seat.StatusAvailable},
+					{ID: 30, EventID: 10, Price: thb(200), Status: seat.StatusAvailable},
 				}
 				mockSeat.EXPECT().GetSeatsForUpdateTx(gomock.Any(), gomock.Any(), seatIDs).Return(mockSeats, nil).Times(1)
 
-				mockSeat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), seatIDs, string(seat.StatusSold)).Return(ErrMockDBError).Times(1)
+				mockEvent.EXPECT().GetPriceTiers(gomock.Any(), eventID).Return(nil, nil).Times(1)
+
+				mockEvent.EXPECT().GetFees(gomock.Any(), eventID).Return(nil, nil).Times(1)
+
+				mockSeat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), seatIDs, string(seat.StatusReserved)).Return(ErrMockDBError).Times(1)
 			},
 			expectedErr: ErrMockDBError,
 		},
@@ -98,15 +187,25 @@ func TestCreateBooking(t *testing.T) {
 			name:    "fail create booking",
 			eventID: 10,
 			seatIDs: []int64{11, 20, 30},
-			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, eventID int64, seatIDs []int64) {
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockEvent eventrepo.MockEventRepository, eventID int64, seatIDs []int64) {
+				mockEvent.EXPECT().GetEventByID(gomock.Any(), eventID).Return(event.Event{ID: eventID, Currency: "THB"}, nil).Times(1)
+
+				mockBook.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), eventID).Return(nil).Times(1)
+
+				mockBook.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), eventID).Return(0, nil).Times(1)
+
 				mockSeats := []seat.Seat{
-					{ID: 11, Price: 100, Status: seat.StatusAvailable},
-					{ID: 20, Price: 100, Status: seat.StatusAvailable},
-					{ID: 30, Price: 200, Status: seat.StatusAvailable},
+					{ID: 11, EventID: 10, Price: thb(100), Status: seat.StatusAvailable},
+					{ID: 20, EventID: 10, Price: thb(100), Status: seat.StatusAvailable},
+					{ID: 30, EventID: 10, Price: thb(200), Status: seat.StatusAvailable},
 				}
 				mockSeat.EXPECT().GetSeatsForUpdateTx(gomock.Any(), gomock.Any(), seatIDs).Return(mockSeats, nil).Times(1)
 
-				mockSeat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), seatIDs, string(seat.StatusSold)).Return(nil).Times(1)
+				mockEvent.EXPECT().GetPriceTiers(gomock.Any(), eventID).Return(nil, nil).Times(1)
+
+				mockEvent.EXPECT().GetFees(gomock.Any(), eventID).Return(nil, nil).Times(1)
+
+				mockSeat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), seatIDs, string(seat.StatusReserved)).Return(nil).Times(1)
 
 				mockBook.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).Return("", ErrMockDBError).Times(1)
 			},
@@ -116,20 +215,30 @@ func TestCreateBooking(t *testing.T) {
 			name:    "fail create items",
 			eventID: 10,
 			seatIDs: []int64{11, 20, 30},
-			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, eventID int64, seatIDs []int64) {
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockEvent eventrepo.MockEventRepository, eventID int64, seatIDs []int64) {
+				mockEvent.EXPECT().GetEventByID(gomock.Any(), eventID).Return(event.Event{ID: eventID, Currency: "THB"}, nil).Times(1)
+
+				mockBook.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), eventID).Return(nil).Times(1)
+
+				mockBook.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), eventID).Return(0, nil).Times(1)
+
 				mockSeats := []seat.Seat{
-					{ID: 11, Price: 100, Status: seat.StatusAvailable},
-					{ID: 20, Price: 100, Status: seat.StatusAvailable},
-					{ID: 30, Price: 200, Status: seat.StatusAvailable},
+					{ID: 11, EventID: 10, Price: thb(100), Status: seat.StatusAvailable},
+					{ID: 20, EventID: 10, Price: thb(100), Status: seat.StatusAvailable},
+					{ID: 30, EventID: 10, Price: thb(200), Status: seat.StatusAvailable},
 				}
 				mockSeat.EXPECT().GetSeatsForUpdateTx(gomock.Any(), gomock.Any(), seatIDs).Return(mockSeats, nil).Times(1)
 
-				mockSeat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), seatIDs, string(seat.StatusSold)).Return(nil).Times(1)
+				mockEvent.EXPECT().GetPriceTiers(gomock.Any(), eventID).Return(nil, nil).Times(1)
+
+				mockEvent.EXPECT().GetFees(gomock.Any(), eventID).Return(nil, nil).Times(1)
+
+				mockSeat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), seatIDs, string(seat.StatusReserved)).Return(nil).Times(1)
 
 				mockBookID := "mock-uuid-1"
 				mockBook.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).Return(mockBookID, nil).Times(1)
 
-				mockBook.EXPECT().CreateBookingItemsTx(gomock.Any(), gomock.Any(), mockBookID, seatIDs).Return(ErrMockDBError).Times(1)
+				mockBook.EXPECT().CreateBookingItemsTx(gomock.Any(), gomock.Any(), mockBookID, gomock.Any()).Return(ErrMockDBError).Times(1)
 			},
 			expectedErr: ErrMockDBError,
 		},
@@ -138,17 +247,20 @@ func TestCreateBooking(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// Setup
-			uc, mockTx, mockBook, mockSeat := setup(t)
+			uc, mockTx, m := setupMocks(t)
 
 			// Mock FN
-			tc.mockFn(mockTx, mockBook, mockSeat, tc.eventID, tc.seatIDs)
+			tc.mockFn(mockTx, *m.book, *m.seat, *m.event, tc.eventID, tc.seatIDs)
+			if tc.expectedErr == nil {
+				m.notifier.EXPECT().SeatsChanged(gomock.Any(), tc.eventID, tc.seatIDs).Times(1)
+			}
 
 			// Create Booking
 			ctx := authcontext.SetUserID(context.Background(), int64(1))
-			err := uc.CreateBooking(ctx, tc.eventID, tc.seatIDs)
+			_, err := uc.CreateBooking(ctx, tc.eventID, seatTickets(tc.seatIDs), "")
 
 			if tc.expectedErr != nil {
-				assert.Error(t, err)
+				assert.ErrorIs(t, err, tc.expectedErr)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -156,55 +268,79 @@ func TestCreateBooking(t *testing.T) {
 	}
 }
 
-func TestGetBookingHistory(t *testing.T) {
+func TestCreateBookingOptimistic(t *testing.T) {
+	seatIDs := []int64{11, 20}
+	mockSeats := []seat.Seat{
+		{ID: 11, EventID: 10, Price: thb(100), Status: seat.StatusAvailable, Version: 3},
+		{ID: 20, EventID: 10, Price: thb(100), Status: seat.StatusAvailable, Version: 1},
+	}
+
 	type testCase struct {
-		name   string
-		userID int64
-		mockFn func(
-			tx database.TxManager,
-			mockBook bookingrepo.MockBookingRepository,
-			mockSeat seatrepo.MockSeatRepository,
-			userID int64,
-		)
+		name        string
+		mockFn      func(m mocks)
 		expectedErr error
 	}
 
 	testCases := []testCase{
 		{
-			name:   "success",
-			userID: 1,
-			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, userID int64) {
-				mockData := []booking.BookingHistoryResponse{
-					{ID: "mock-uuid-1", EventDate: time.Now(), CreatedAt: time.Now()},
-					{ID: "mock-uuid-2", EventDate: time.Now(), CreatedAt: time.Now()},
-				}
-				mockBook.EXPECT().GetHistory(gomock.Any(), userID).Return(mockData, nil).Times(1)
+			name: "success",
+			mockFn: func(m mocks) {
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10, Currency: "THB"}, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(nil).Times(1)
+
+				m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(0, nil).Times(1)
+
+				m.seat.EXPECT().GetSeatsTx(gomock.Any(), gomock.Any(), seatIDs).Return(mockSeats, nil).Times(1)
+
+				m.event.EXPECT().GetPriceTiers(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
+
+				m.event.EXPECT().GetFees(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
+
+				m.seat.EXPECT().ReserveSeatsVersionedTx(gomock.Any(), gomock.Any(), mockSeats).Return(nil).Times(1)
+
+				m.book.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).Return("mock-uuid-1", nil).Times(1)
+
+				m.book.EXPECT().CreateBookingItemsTx(gomock.Any(), gomock.Any(), "mock-uuid-1", gomock.Any()).Return(nil).Times(1)
+
+				m.notifier.EXPECT().SeatsChanged(gomock.Any(), int64(10), seatIDs).Times(1)
 			},
 			expectedErr: nil,
 		},
 		{
-			name:   "fail",
-			userID: 1,
-			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, userID int64) {
-				mockBook.EXPECT().GetHistory(gomock.Any(), userID).Return(nil, ErrMockDBError).Times(1)
+			name: "fail version conflict",
+			mockFn: func(m mocks) {
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10, Currency: "THB"}, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(nil).Times(1)
+
+				m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(0, nil).Times(1)
+
+				m.seat.EXPECT().GetSeatsTx(gomock.Any(), gomock.Any(), seatIDs).Return(mockSeats, nil).Times(1)
+
+				m.event.EXPECT().GetPriceTiers(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
+
+				m.event.EXPECT().GetFees(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
+
+				m.seat.EXPECT().ReserveSeatsVersionedTx(gomock.Any(), gomock.Any(), mockSeats).Return(errs.ErrSeatVersionConflict).Times(1)
 			},
-			expectedErr: ErrMockDBError,
+			expectedErr: errs.ErrSeatVersionConflict,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// Setup
-			uc, mockTx, mockBook, mockSeat := setup(t)
+			uc, _, m := setupStrategy(t, seat.LockOptimistic)
 
 			// Mock FN
-			tc.mockFn(mockTx, mockBook, mockSeat, tc.userID)
-			
+			tc.mockFn(m)
+
 			ctx := authcontext.SetUserID(context.Background(), int64(1))
-			_, err := uc.GetBookingHistory(ctx)
+			_, err := uc.CreateBooking(ctx, 10, seatTickets(seatIDs), "")
 
 			if tc.expectedErr != nil {
-				assert.Error(t, err)
+				assert.ErrorIs(t, err, tc.expectedErr)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -212,107 +348,295 @@ func TestGetBookingHistory(t *testing.T) {
 	}
 }
 
-func TestCancelBooking(t *testing.T) {
+func TestCreateBookingSkipLocked(t *testing.T) {
+	seatIDs := []int64{11, 20, 21}
+
 	type testCase struct {
-		name      string
-		userID    int64
-		bookingID string
-		mockFn    func(
-			tx database.TxManager,
-			mockBook bookingrepo.MockBookingRepository,
-			mockSeat seatrepo.MockSeatRepository,
-			userID int64,
-			bookingID string,
-		)
-		expectedErr error
+		name           string
+		mockFn         func(m mocks)
+		expectedErr    error
+		expectedLocked []int64
 	}
 
 	testCases := []testCase{
 		{
-			name:      "success",
-			userID:    1,
-			bookingID: "mock-uuid-1",
-			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, userID int64, bookingID string) {
-				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, Status: booking.StatusPending}
-				mockBook.EXPECT().GetByID(gomock.Any(), bookingID).Return(mockBookData, nil).Times(1)
+			name: "success",
+			mockFn: func(m mocks) {
+				mockSeats := []seat.Seat{
+					{ID: 11, EventID: 10, Price: thb(100), Status: seat.StatusAvailable},
+					{ID: 20, EventID: 10, Price: thb(100), Status: seat.StatusAvailable},
+					{ID: 21, EventID: 10, Price: thb(100), Status: seat.StatusAvailable},
+				}
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10, Currency: "THB"}, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(nil).Times(1)
+
+				m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(0, nil).Times(1)
+
+				m.seat.EXPECT().GetSeatsSkipLockedTx(gomock.Any(), gomock.Any(), seatIDs).Return(mockSeats, nil).Times(1)
+
+				m.event.EXPECT().GetPriceTiers(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
 
-				mockBook.EXPECT().CancelBookingTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(nil).Times(1)
+				m.event.EXPECT().GetFees(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
 
-				mockSeat.EXPECT().CancelSeatsTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(nil).Times(1)
+				m.seat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), seatIDs, string(seat.StatusReserved)).Return(nil).Times(1)
+
+				m.book.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).Return("mock-uuid-1", nil).Times(1)
+
+				m.book.EXPECT().CreateBookingItemsTx(gomock.Any(), gomock.Any(), "mock-uuid-1", gomock.Any()).Return(nil).Times(1)
+
+				m.notifier.EXPECT().SeatsChanged(gomock.Any(), int64(10), seatIDs).Times(1)
 			},
 			expectedErr: nil,
 		},
 		{
-			name:      "fail user other booking",
-			userID:    2,
-			bookingID: "mock-uuid-1",
-			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, userID int64, bookingID string) {
-				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, Status: booking.StatusCancelled}
-				mockBook.EXPECT().GetByID(gomock.Any(), bookingID).Return(mockBookData, nil).Times(1)
+			name: "fail seats locked or taken",
+			mockFn: func(m mocks) {
+				// 20 is locked by another order and skipped, 21 is already sold
+				mockSeats := []seat.Seat{
+					{ID: 11, EventID: 10, Price: thb(100), Status: seat.StatusAvailable},
+					{ID: 21, EventID: 10, Price: thb(100), Status: seat.StatusSold},
+				}
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10, Currency: "THB"}, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(nil).Times(1)
+
+				m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(0, nil).Times(1)
+
+				m.seat.EXPECT().GetSeatsSkipLockedTx(gomock.Any(), gomock.Any(), seatIDs).Return(mockSeats, nil).Times(1)
 			},
-			expectedErr: errs.ErrCancelOtherBooking,
+			expectedErr:    errs.ErrSomeSeatNotAvailable,
+			expectedLocked: []int64{20, 21},
 		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, _, m := setupStrategy(t, seat.LockSkipLocked)
+
+			// Mock FN
+			tc.mockFn(m)
+
+			ctx := authcontext.SetUserID(context.Background(), int64(1))
+			_, err := uc.CreateBooking(ctx, 10, seatTickets(seatIDs), "")
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+
+				var unavailable *errs.SeatsUnavailableError
+				assert.ErrorAs(t, err, &unavailable)
+				assert.Equal(t, tc.expectedLocked, unavailable.SeatIDs)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCreateBookingPriceTier(t *testing.T) {
+	// Setup
+	uc, _, m := setupMocks(t)
+
+	seatIDs := []int64{11, 20}
+	mockSeats := []seat.Seat{
+		{ID: 11, EventID: 10, Zone: "VIP", Price: thb(100), Status: seat.StatusAvailable},
+		{ID: 20, EventID: 10, Zone: "A", Price: thb(50), Status: seat.StatusAvailable},
+	}
+	started := time.Now().Add(-time.Hour)
+	tiers := []event.PriceTier{
+		{Zone: "VIP", Name: "early bird", Price: thb(80), StartsAt: &started},
+	}
+
+	m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10, Currency: "THB"}, nil).Times(1)
+
+	m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(nil).Times(1)
+
+	m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(0, nil).Times(1)
+
+	m.seat.EXPECT().GetSeatsForUpdateTx(gomock.Any(), gomock.Any(), seatIDs).Return(mockSeats, nil).Times(1)
+
+	m.event.EXPECT().GetPriceTiers(gomock.Any(), int64(10)).Return(tiers, nil).Times(1)
+
+	m.event.EXPECT().GetFees(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
+
+	m.seat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), seatIDs, string(seat.StatusReserved)).Return(nil).Times(1)
+
+	m.book.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, tx *sql.Tx, input booking.Booking) (string, error) {
+			assert.Equal(t, thb(130), input.TotalAmount)
+			return "mock-uuid-1", nil
+		}).Times(1)
+
+	m.book.EXPECT().CreateBookingItemsTx(gomock.Any(), gomock.Any(), "mock-uuid-1", []booking.BookingItem{
+		{SeatID: 11, UnitPrice: thb(80)},
+		{SeatID: 20, UnitPrice: thb(50)},
+	}).Return(nil).Times(1)
+
+	m.notifier.EXPECT().SeatsChanged(gomock.Any(), int64(10), seatIDs).Times(1)
+
+	ctx := authcontext.SetUserID(context.Background(), int64(1))
+	result, err := uc.CreateBooking(ctx, 10, seatTickets(seatIDs), "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, thb(130), result.TotalAmount)
+}
+
+func TestCreateBookingFees(t *testing.T) {
+	// Setup
+	uc, _, m := setupMocks(t)
+
+	seatIDs := []int64{11, 12}
+	mockSeats := []seat.Seat{
+		{ID: 11, EventID: 10, Zone: "A", Price: thb(100), Status: seat.StatusAvailable},
+		{ID: 12, EventID: 10, Zone: "A", Price: thb(100), Status: seat.StatusAvailable},
+	}
+	fees := []event.Fee{
+		{Name: "service", Type: event.FeePercent, Percent: 5},
+		{Name: "facility", Type: event.FeePerTicket, Amount: thb(20)},
+	}
+
+	m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10, Currency: "THB", TaxPercent: 7}, nil).Times(1)
+
+	m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(nil).Times(1)
+
+	m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(0, nil).Times(1)
+
+	m.seat.EXPECT().GetSeatsForUpdateTx(gomock.Any(), gomock.Any(), seatIDs).Return(mockSeats, nil).Times(1)
+
+	m.event.EXPECT().GetPriceTiers(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
+
+	m.event.EXPECT().GetFees(gomock.Any(), int64(10)).Return(fees, nil).Times(1)
+
+	m.seat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), seatIDs, string(seat.StatusReserved)).Return(nil).Times(1)
+
+	// 200 tickets + 10 service + 40 facility, then 7% VAT on 250
+	m.book.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, tx *sql.Tx, input booking.Booking) (string, error) {
+			assert.Equal(t, money.New(26750, "THB"), input.TotalAmount)
+			return "mock-uuid-1", nil
+		}).Times(1)
+
+	m.book.EXPECT().CreateBookingItemsTx(gomock.Any(), gomock.Any(), "mock-uuid-1", gomock.Any()).Return(nil).Times(1)
+
+	m.book.EXPECT().CreateLinesTx(gomock.Any(), gomock.Any(), "mock-uuid-1", []booking.Line{
+		{Kind: booking.LineFee, Name: "service", Amount: thb(10)},
+		{Kind: booking.LineFee, Name: "facility", Amount: thb(40)},
+		{Kind: booking.LineTax, Name: "VAT", Amount: money.New(1750, "THB")},
+	}).Return(nil).Times(1)
+
+	m.notifier.EXPECT().SeatsChanged(gomock.Any(), int64(10), seatIDs).Times(1)
+
+	ctx := authcontext.SetUserID(context.Background(), int64(1))
+	result, err := uc.CreateBooking(ctx, 10, seatTickets(seatIDs), "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, money.New(26750, "THB"), result.TotalAmount)
+}
+
+func TestCreateBookingTicketTypes(t *testing.T) {
+	seatIDs := []int64{11, 20}
+	mockSeats := []seat.Seat{
+		{ID: 11, EventID: 10, Zone: "VIP", Price: thb(100), Status: seat.StatusAvailable},
+		{ID: 20, EventID: 10, Zone: "A", Price: thb(50), Status: seat.StatusAvailable},
+	}
+	ticketTypes := []event.TicketType{
+		{ID: 1, EventID: 10, Name: "child", Prices: []event.TicketPrice{
+			{Zone: "VIP", Price: thb(60)},
+			{Zone: "A", Price: thb(30)},
+		}},
+		{ID: 2, EventID: 10, Name: "concession", Quota: 20, Prices: []event.TicketPrice{
+			{Zone: "A", Price: thb(35)},
+		}},
+	}
+
+	type testCase struct {
+		name          string
+		seats         []booking.SeatTicket
+		mockFn        func(m mocks)
+		expectedItems []booking.BookingItem
+		expectedErr   error
+	}
+
+	testCases := []testCase{
 		{
-			name:      "fail cancel already",
-			userID:    1,
-			bookingID: "mock-uuid-1",
-			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, userID int64, bookingID string) {
-				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, Status: booking.StatusCancelled}
-				mockBook.EXPECT().GetByID(gomock.Any(), bookingID).Return(mockBookData, nil).Times(1)
+			name:  "success child and concession",
+			seats: []booking.SeatTicket{{SeatID: 11, TicketTypeID: 1}, {SeatID: 20, TicketTypeID: 2}},
+			mockFn: func(m mocks) {
+				m.event.EXPECT().LockTicketTypesTx(gomock.Any(), gomock.Any(), []int64{2}).Return(nil).Times(1)
+				m.book.EXPECT().CountTicketTypesTx(gomock.Any(), gomock.Any(), []int64{2}).Return(map[int64]int{2: 19}, nil).Times(1)
+			},
+			expectedItems: []booking.BookingItem{
+				{SeatID: 11, TicketTypeID: 1, UnitPrice: thb(60)},
+				{SeatID: 20, TicketTypeID: 2, UnitPrice: thb(35)},
 			},
-			expectedErr: errs.ErrBookingIsCancel,
 		},
 		{
-			name:      "fail cancel paid status",
-			userID:    1,
-			bookingID: "mock-uuid-1",
-			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, userID int64, bookingID string) {
-				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, Status: booking.StatusPaid}
-				mockBook.EXPECT().GetByID(gomock.Any(), bookingID).Return(mockBookData, nil).Times(1)
+			name:  "success without a type keeps the zone price",
+			seats: []booking.SeatTicket{{SeatID: 11, TicketTypeID: 1}, {SeatID: 20}},
+			expectedItems: []booking.BookingItem{
+				{SeatID: 11, TicketTypeID: 1, UnitPrice: thb(60)},
+				{SeatID: 20, UnitPrice: thb(50)},
 			},
-			expectedErr: errs.ErrBookingIsPaid,
 		},
 		{
-			name:      "fail cancel booking",
-			userID:    1,
-			bookingID: "mock-uuid-1",
-			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, userID int64, bookingID string) {
-				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, Status: booking.StatusPending}
-				mockBook.EXPECT().GetByID(gomock.Any(), bookingID).Return(mockBookData, nil).Times(1)
-
-				mockBook.EXPECT().CancelBookingTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(ErrMockDBError).Times(1)
+			name:  "fail concession quota sold out",
+			seats: []booking.SeatTicket{{SeatID: 11}, {SeatID: 20, TicketTypeID: 2}},
+			mockFn: func(m mocks) {
+				m.event.EXPECT().LockTicketTypesTx(gomock.Any(), gomock.Any(), []int64{2}).Return(nil).Times(1)
+				m.book.EXPECT().CountTicketTypesTx(gomock.Any(), gomock.Any(), []int64{2}).Return(map[int64]int{2: 20}, nil).Times(1)
 			},
-			expectedErr: ErrMockDBError,
+			expectedErr: errs.ErrTicketTypeSoldOut,
 		},
 		{
-			name:      "fail cancel seats",
-			userID:    1,
-			bookingID: "mock-uuid-1",
-			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, userID int64, bookingID string) {
-				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, Status: booking.StatusPending}
-				mockBook.EXPECT().GetByID(gomock.Any(), bookingID).Return(mockBookData, nil).Times(1)
-
-				mockBook.EXPECT().CancelBookingTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(nil).Times(1)
-				
-				mockSeat.EXPECT().CancelSeatsTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(ErrMockDBError).Times(1)
+			name:  "fail type not offered in the seat's zone",
+			seats: []booking.SeatTicket{{SeatID: 11, TicketTypeID: 2}, {SeatID: 20}},
+			mockFn: func(m mocks) {
+				m.event.EXPECT().LockTicketTypesTx(gomock.Any(), gomock.Any(), []int64{2}).Return(nil).Times(1)
+				m.book.EXPECT().CountTicketTypesTx(gomock.Any(), gomock.Any(), []int64{2}).Return(nil, nil).Times(1)
+				m.event.EXPECT().GetPriceTiers(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
+				m.event.EXPECT().GetFees(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
 			},
-			expectedErr: ErrMockDBError,
+			expectedErr: errs.ErrTicketTypeNotOffered,
+		},
+		{
+			name:        "fail type of another event",
+			seats:       []booking.SeatTicket{{SeatID: 11, TicketTypeID: 99}, {SeatID: 20}},
+			expectedErr: errs.ErrTicketTypeNotOffered,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// Setup
-			uc, mockTx, mockBook, mockSeat := setup(t)
+			uc, _, m := setupMocks(t)
 
 			// Mock FN
-			tc.mockFn(mockTx, mockBook, mockSeat, tc.userID, tc.bookingID)
-			
+			m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10, Currency: "THB"}, nil).Times(1)
+			m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(nil).Times(1)
+			m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(0, nil).Times(1)
+			m.seat.EXPECT().GetSeatsForUpdateTx(gomock.Any(), gomock.Any(), seatIDs).Return(mockSeats, nil).Times(1)
+			m.event.EXPECT().GetTicketTypes(gomock.Any(), int64(10)).Return(ticketTypes, nil).Times(1)
+			if tc.mockFn != nil {
+				tc.mockFn(m)
+			}
+
+			if tc.expectedErr == nil {
+				m.event.EXPECT().GetPriceTiers(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
+				m.event.EXPECT().GetFees(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
+				m.seat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), seatIDs, string(seat.StatusReserved)).Return(nil).Times(1)
+				m.book.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).Return("mock-uuid-1", nil).Times(1)
+				m.book.EXPECT().CreateBookingItemsTx(gomock.Any(), gomock.Any(), "mock-uuid-1", tc.expectedItems).Return(nil).Times(1)
+				m.notifier.EXPECT().SeatsChanged(gomock.Any(), int64(10), seatIDs).Times(1)
+			}
+
 			ctx := authcontext.SetUserID(context.Background(), int64(1))
-			err := uc.CancelBooking(ctx, tc.bookingID)
+			_, err := uc.CreateBooking(ctx, 10, tc.seats, "")
 
 			if tc.expectedErr != nil {
-				assert.Error(t, err)
+				assert.ErrorIs(t, err, tc.expectedErr)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -320,16 +644,1114 @@ func TestCancelBooking(t *testing.T) {
 	}
 }
 
-func setup(t *testing.T) (bookingusecase.BookingUsecase, mockTx, bookingrepo.MockBookingRepository, seatrepo.MockSeatRepository) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
+func TestCreateBookingPromo(t *testing.T) {
+	seatIDs := []int64{11, 20}
+	mockSeats := []seat.Seat{
+		{ID: 11, EventID: 10, Zone: "VIP", Price: thb(100), Status: seat.StatusAvailable},
+		{ID: 20, EventID: 10, Zone: "A", Price: thb(50), Status: seat.StatusAvailable},
+	}
+	eventID := int64(10)
+	ended := time.Now().Add(-time.Hour)
+	thirty := thb(30)
 
-	loc, _ := time.LoadLocation("Asia/Bangkok")
+	type testCase struct {
+		name             string
+		code             promo.Promo
+		used, usedByUser int
+		mockFn           func(m mocks, code promo.Promo, used, usedByUser int)
+		expectedTotal    money.Money
+		expectedErr      error
+	}
 
-	mockTx := mockTx{}
-	mockBook := bookingrepo.NewMockBookingRepository(ctrl)
-	mockSeat := seatrepo.NewMockSeatRepository(ctrl)
-	uc := bookingusecase.NewBookingUsecase(loc, mockTx, mockBook, mockSeat)
+	// pickAndPrice : the steps before the promo, shared by every case that reaches it
+	pickAndPrice := func(m mocks) {
+		m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10, Currency: "THB"}, nil).Times(1)
+		m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(nil).Times(1)
+		m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(0, nil).Times(1)
+		m.seat.EXPECT().GetSeatsForUpdateTx(gomock.Any(), gomock.Any(), seatIDs).Return(mockSeats, nil).Times(1)
+		m.event.EXPECT().GetPriceTiers(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
+		m.event.EXPECT().GetFees(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
+	}
+
+	testCases := []testCase{
+		{
+			name: "success percent off one zone",
+			code: promo.Promo{ID: 5, Code: "VIP10", DiscountType: promo.DiscountPercent, Percent: 10, EventID: &eventID, Zone: "VIP", MaxUses: 100, MaxUsesPerUser: 1},
+			mockFn: func(m mocks, code promo.Promo, used, usedByUser int) {
+				pickAndPrice(m)
+				m.promo.EXPECT().GetByCodeForUpdateTx(gomock.Any(), gomock.Any(), "VIP10").Return(code, nil).Times(1)
+				m.promo.EXPECT().CountRedemptionsTx(gomock.Any(), gomock.Any(), int64(5), int64(1)).Return(used, usedByUser, nil).Times(1)
+
+				m.seat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), seatIDs, string(seat.StatusReserved)).Return(nil).Times(1)
+				m.book.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sql.Tx, input booking.Booking) (string, error) {
+						assert.Equal(t, thb(140), input.TotalAmount)
+						assert.Equal(t, thb(10), input.DiscountAmount)
+						return "mock-uuid-1", nil
+					}).Times(1)
+				m.book.EXPECT().CreateBookingItemsTx(gomock.Any(), gomock.Any(), "mock-uuid-1", gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sql.Tx, bookingID string, items []booking.BookingItem) error {
+						// Only the VIP seat carries the discount
+						assert.Equal(t, thb(10), items[0].Discount)
+						assert.True(t, items[1].Discount.IsZero())
+						return nil
+					}).Times(1)
+				m.promo.EXPECT().RedeemTx(gomock.Any(), gomock.Any(), promo.Redemption{
+					PromoID:   5,
+					UserID:    1,
+					BookingID: "mock-uuid-1",
+					Discount:  thb(10),
+				}).Return(nil).Times(1)
+				m.notifier.EXPECT().SeatsChanged(gomock.Any(), int64(10), seatIDs).Times(1)
+			},
+			expectedTotal: thb(140),
+		},
+		{
+			name: "success fixed amount",
+			code: promo.Promo{ID: 5, Code: "VIP10", DiscountType: promo.DiscountFixed, FixedAmount: &thirty},
+			mockFn: func(m mocks, code promo.Promo, used, usedByUser int) {
+				pickAndPrice(m)
+				m.promo.EXPECT().GetByCodeForUpdateTx(gomock.Any(), gomock.Any(), "VIP10").Return(code, nil).Times(1)
+				m.promo.EXPECT().CountRedemptionsTx(gomock.Any(), gomock.Any(), int64(5), int64(1)).Return(used, usedByUser, nil).Times(1)
+
+				m.seat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), seatIDs, string(seat.StatusReserved)).Return(nil).Times(1)
+				m.book.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).Return("mock-uuid-1", nil).Times(1)
+				m.book.EXPECT().CreateBookingItemsTx(gomock.Any(), gomock.Any(), "mock-uuid-1", gomock.Any()).Return(nil).Times(1)
+				m.promo.EXPECT().RedeemTx(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				m.notifier.EXPECT().SeatsChanged(gomock.Any(), int64(10), seatIDs).Times(1)
+			},
+			expectedTotal: thb(120),
+		},
+		{
+			name: "success 100 percent code",
+			code: promo.Promo{ID: 5, Code: "VIP10", DiscountType: promo.DiscountPercent, Percent: 100},
+			mockFn: func(m mocks, code promo.Promo, used, usedByUser int) {
+				pickAndPrice(m)
+				m.promo.EXPECT().GetByCodeForUpdateTx(gomock.Any(), gomock.Any(), "VIP10").Return(code, nil).Times(1)
+				m.promo.EXPECT().CountRedemptionsTx(gomock.Any(), gomock.Any(), int64(5), int64(1)).Return(used, usedByUser, nil).Times(1)
+
+				m.seat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), seatIDs, string(seat.StatusReserved)).Return(nil).Times(1)
+				m.book.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sql.Tx, input booking.Booking) (string, error) {
+						assert.True(t, input.TotalAmount.IsZero())
+						assert.Equal(t, thb(150), input.DiscountAmount)
+						return "mock-uuid-1", nil
+					}).Times(1)
+				m.book.EXPECT().CreateBookingItemsTx(gomock.Any(), gomock.Any(), "mock-uuid-1", gomock.Any()).Return(nil).Times(1)
+				m.promo.EXPECT().RedeemTx(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+				m.notifier.EXPECT().SeatsChanged(gomock.Any(), int64(10), seatIDs).Times(1)
+			},
+			expectedTotal: thb(0),
+		},
+		{
+			name: "fail promo not found",
+			mockFn: func(m mocks, code promo.Promo, used, usedByUser int) {
+				pickAndPrice(m)
+				m.promo.EXPECT().GetByCodeForUpdateTx(gomock.Any(), gomock.Any(), "VIP10").Return(promo.Promo{}, errs.ErrPromoNotFound).Times(1)
+			},
+			expectedErr: errs.ErrPromoNotFound,
+		},
+		{
+			name: "fail promo expired",
+			code: promo.Promo{ID: 5, Code: "VIP10", DiscountType: promo.DiscountPercent, Percent: 10, EndsAt: &ended},
+			mockFn: func(m mocks, code promo.Promo, used, usedByUser int) {
+				pickAndPrice(m)
+				m.promo.EXPECT().GetByCodeForUpdateTx(gomock.Any(), gomock.Any(), "VIP10").Return(code, nil).Times(1)
+			},
+			expectedErr: errs.ErrPromoNotActive,
+		},
+		{
+			name: "fail promo used up",
+			code: promo.Promo{ID: 5, Code: "VIP10", DiscountType: promo.DiscountPercent, Percent: 10, MaxUses: 2},
+			used: 2,
+			mockFn: func(m mocks, code promo.Promo, used, usedByUser int) {
+				pickAndPrice(m)
+				m.promo.EXPECT().GetByCodeForUpdateTx(gomock.Any(), gomock.Any(), "VIP10").Return(code, nil).Times(1)
+				m.promo.EXPECT().CountRedemptionsTx(gomock.Any(), gomock.Any(), int64(5), int64(1)).Return(used, usedByUser, nil).Times(1)
+			},
+			expectedErr: errs.ErrPromoUsedUp,
+		},
+		{
+			name: "fail promo user limit",
+			code: promo.Promo{ID: 5, Code: "VIP10", DiscountType: promo.DiscountPercent, Percent: 10, MaxUsesPerUser: 1},
+			used: 3, usedByUser: 1,
+			mockFn: func(m mocks, code promo.Promo, used, usedByUser int) {
+				pickAndPrice(m)
+				m.promo.EXPECT().GetByCodeForUpdateTx(gomock.Any(), gomock.Any(), "VIP10").Return(code, nil).Times(1)
+				m.promo.EXPECT().CountRedemptionsTx(gomock.Any(), gomock.Any(), int64(5), int64(1)).Return(used, usedByUser, nil).Times(1)
+			},
+			expectedErr: errs.ErrPromoUserLimit,
+		},
+		{
+			name: "fail promo zone not in order",
+			code: promo.Promo{ID: 5, Code: "VIP10", DiscountType: promo.DiscountPercent, Percent: 10, EventID: &eventID, Zone: "B"},
+			mockFn: func(m mocks, code promo.Promo, used, usedByUser int) {
+				pickAndPrice(m)
+				m.promo.EXPECT().GetByCodeForUpdateTx(gomock.Any(), gomock.Any(), "VIP10").Return(code, nil).Times(1)
+				m.promo.EXPECT().CountRedemptionsTx(gomock.Any(), gomock.Any(), int64(5), int64(1)).Return(used, usedByUser, nil).Times(1)
+			},
+			expectedErr: errs.ErrPromoNotApplicable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uc, _, m := setupMocks(t)
+
+			tc.mockFn(m, tc.code, tc.used, tc.usedByUser)
+
+			ctx := authcontext.SetUserID(context.Background(), int64(1))
+			result, err := uc.CreateBooking(ctx, 10, seatTickets(seatIDs), " vip10")
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTotal, result.TotalAmount)
+			}
+		})
+	}
+}
+
+func TestCreateBestAvailableBooking(t *testing.T) {
+	zoneSeats := []seat.Seat{
+		{ID: 1, SeatNumber: "A1", Zone: "A", Section: "A", Row: "1", X: 0, Price: thb(100), Status: seat.StatusSold},
+		{ID: 2, SeatNumber: "A2", Zone: "A", Section: "A", Row: "1", X: 1, Price: thb(100), Status: seat.StatusAvailable},
+		{ID: 3, SeatNumber: "A3", Zone: "A", Section: "A", Row: "1", X: 2, Price: thb(100), Status: seat.StatusAvailable},
+		{ID: 4, SeatNumber: "A4", Zone: "A", Section: "A", Row: "1", X: 3, Price: thb(100), Status: seat.StatusAvailable},
+		{ID: 5, SeatNumber: "A5", Zone: "A", Section: "A", Row: "1", X: 4, Price: thb(100), Status: seat.StatusReserved},
+	}
+
+	type testCase struct {
+		name        string
+		quantity    int
+		mockFn      func(m mocks)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:     "success",
+			quantity: 2,
+			mockFn: func(m mocks) {
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10, Currency: "THB"}, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(nil).Times(1)
+
+				m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(0, nil).Times(1)
+
+				m.seat.EXPECT().GetZoneSeatsForUpdateTx(gomock.Any(), gomock.Any(), int64(10), "A").Return(zoneSeats, nil).Times(1)
+
+				m.event.EXPECT().GetPriceTiers(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
+
+				m.event.EXPECT().GetFees(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
+
+				m.seat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), []int64{2, 3}, string(seat.StatusReserved)).Return(nil).Times(1)
+
+				mockBookID := "mock-uuid-1"
+				m.book.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).Return(mockBookID, nil).Times(1)
+
+				mockItems := []booking.BookingItem{
+					{SeatID: 2, UnitPrice: thb(100)},
+					{SeatID: 3, UnitPrice: thb(100)},
+				}
+				m.book.EXPECT().CreateBookingItemsTx(gomock.Any(), gomock.Any(), mockBookID, mockItems).Return(nil).Times(1)
+
+				m.notifier.EXPECT().SeatsChanged(gomock.Any(), int64(10), []int64{2, 3}).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:     "fail not enough seats",
+			quantity: 4,
+			mockFn: func(m mocks) {
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10, Currency: "THB"}, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(nil).Times(1)
+
+				m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(0, nil).Times(1)
+
+				m.seat.EXPECT().GetZoneSeatsForUpdateTx(gomock.Any(), gomock.Any(), int64(10), "A").Return(zoneSeats, nil).Times(1)
+			},
+			expectedErr: errs.ErrNotEnoughSeats,
+		},
+		{
+			name:     "fail zone not found",
+			quantity: 2,
+			mockFn: func(m mocks) {
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10, Currency: "THB"}, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(nil).Times(1)
+
+				m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(0, nil).Times(1)
+
+				m.seat.EXPECT().GetZoneSeatsForUpdateTx(gomock.Any(), gomock.Any(), int64(10), "A").Return(nil, errs.ErrZoneNotFound).Times(1)
+			},
+			expectedErr: errs.ErrZoneNotFound,
+		},
+		{
+			name:     "fail seats per order limit",
+			quantity: 3,
+			mockFn: func(m mocks) {
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10, Currency: "THB", MaxSeatsPerOrder: 2}, nil).Times(1)
+			},
+			expectedErr: errs.ErrPurchaseLimitExceeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, _, m := setupMocks(t)
+
+			// Mock FN
+			tc.mockFn(m)
+
+			ctx := authcontext.SetUserID(context.Background(), int64(1))
+			_, err := uc.CreateBestAvailableBooking(ctx, 10, "A", tc.quantity, 0, "")
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetBookingHistory(t *testing.T) {
+	type testCase struct {
+		name   string
+		userID int64
+		mockFn func(
+			tx database.TxManager,
+			mockBook bookingrepo.MockBookingRepository,
+			mockSeat seatrepo.MockSeatRepository,
+			userID int64,
+		)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:   "success",
+			userID: 1,
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, userID int64) {
+				mockData := []booking.BookingHistoryResponse{
+					{ID: "mock-uuid-1", EventDate: time.Now(), CreatedAt: time.Now()},
+					{ID: "mock-uuid-2", EventDate: time.Now(), CreatedAt: time.Now()},
+				}
+				mockBook.EXPECT().GetHistory(gomock.Any(), userID).Return(mockData, nil).Times(1)
+				mockBook.EXPECT().GetLines(gomock.Any(), []string{"mock-uuid-1", "mock-uuid-2"}).Return(nil, nil).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:   "fail",
+			userID: 1,
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, userID int64) {
+				mockBook.EXPECT().GetHistory(gomock.Any(), userID).Return(nil, ErrMockDBError).Times(1)
+			},
+			expectedErr: ErrMockDBError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, mockTx, mockBook, mockSeat := setup(t)
+
+			// Mock FN
+			tc.mockFn(mockTx, mockBook, mockSeat, tc.userID)
+
+			ctx := authcontext.SetUserID(context.Background(), int64(1))
+			_, err := uc.GetBookingHistory(ctx)
+
+			if tc.expectedErr != nil {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetBookingDetail(t *testing.T) {
+	type testCase struct {
+		name      string
+		bookingID string
+		mockFn    func(
+			tx database.TxManager,
+			mockBook bookingrepo.MockBookingRepository,
+			mockSeat seatrepo.MockSeatRepository,
+			bookingID string,
+		)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:      "success",
+			bookingID: "mock-uuid-1",
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, bookingID string) {
+				mockDetail := booking.BookingDetailResponse{
+					Booking:   booking.Booking{ID: bookingID, UserID: 1, TotalAmount: thb(200), Status: booking.StatusPending, CreatedAt: time.Now()},
+					EventName: "mock-event",
+					EventDate: time.Now(),
+				}
+				mockBook.EXPECT().GetDetail(gomock.Any(), bookingID).Return(mockDetail, nil).Times(1)
+
+				mockItems := []booking.BookingItemResponse{
+					{SeatID: 11, SeatNumber: "A1", Zone: "A", UnitPrice: thb(100)},
+					{SeatID: 12, SeatNumber: "A2", Zone: "A", UnitPrice: thb(100)},
+				}
+				mockBook.EXPECT().GetItems(gomock.Any(), bookingID).Return(mockItems, nil).Times(1)
+				mockBook.EXPECT().GetLines(gomock.Any(), []string{bookingID}).Return(nil, nil).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:      "fail not found",
+			bookingID: "mock-uuid-1",
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, bookingID string) {
+				mockBook.EXPECT().GetDetail(gomock.Any(), bookingID).Return(booking.BookingDetailResponse{}, errs.ErrBookingNotFound).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotFound,
+		},
+		{
+			name:      "fail other user booking",
+			bookingID: "mock-uuid-1",
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, bookingID string) {
+				mockDetail := booking.BookingDetailResponse{
+					Booking: booking.Booking{ID: bookingID, UserID: 2, Status: booking.StatusPending},
+				}
+				mockBook.EXPECT().GetDetail(gomock.Any(), bookingID).Return(mockDetail, nil).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotOwned,
+		},
+		{
+			name:      "fail get items",
+			bookingID: "mock-uuid-1",
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, bookingID string) {
+				mockDetail := booking.BookingDetailResponse{
+					Booking: booking.Booking{ID: bookingID, UserID: 1, Status: booking.StatusPending},
+				}
+				mockBook.EXPECT().GetDetail(gomock.Any(), bookingID).Return(mockDetail, nil).Times(1)
+
+				mockBook.EXPECT().GetItems(gomock.Any(), bookingID).Return(nil, ErrMockDBError).Times(1)
+			},
+			expectedErr: ErrMockDBError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, mockTx, mockBook, mockSeat := setup(t)
+
+			// Mock FN
+			tc.mockFn(mockTx, mockBook, mockSeat, tc.bookingID)
+
+			ctx := authcontext.SetUserID(context.Background(), int64(1))
+			_, err := uc.GetBookingDetail(ctx, tc.bookingID)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGetTickets(t *testing.T) {
+	type testCase struct {
+		name      string
+		bookingID string
+		mockFn    func(
+			mockBook bookingrepo.MockBookingRepository,
+			bookingID string,
+		)
+		expectedSeats []int64
+		expectedErr   error
+	}
+
+	testCases := []testCase{
+		{
+			name:      "success skips released seats",
+			bookingID: "mock-uuid-1",
+			mockFn: func(mockBook bookingrepo.MockBookingRepository, bookingID string) {
+				mockDetail := booking.BookingDetailResponse{
+					Booking:   booking.Booking{ID: bookingID, UserID: 1, EventID: 10, Status: booking.StatusPaid},
+					EventName: "mock-event",
+					EventDate: time.Now(),
+				}
+				mockBook.EXPECT().GetDetail(gomock.Any(), bookingID).Return(mockDetail, nil).Times(1)
+
+				released := time.Now()
+				mockItems := []booking.BookingItemResponse{
+					{SeatID: 11, SeatNumber: "A1", Zone: "A", UnitPrice: thb(100)},
+					{SeatID: 12, SeatNumber: "A2", Zone: "A", UnitPrice: thb(100), ReleasedAt: &released},
+				}
+				mockBook.EXPECT().GetItems(gomock.Any(), bookingID).Return(mockItems, nil).Times(1)
+			},
+			expectedSeats: []int64{11},
+			expectedErr:   nil,
+		},
+		{
+			name:      "fail other user booking",
+			bookingID: "mock-uuid-1",
+			mockFn: func(mockBook bookingrepo.MockBookingRepository, bookingID string) {
+				mockDetail := booking.BookingDetailResponse{
+					Booking: booking.Booking{ID: bookingID, UserID: 2, Status: booking.StatusPaid},
+				}
+				mockBook.EXPECT().GetDetail(gomock.Any(), bookingID).Return(mockDetail, nil).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotOwned,
+		},
+		{
+			name:      "fail not paid",
+			bookingID: "mock-uuid-1",
+			mockFn: func(mockBook bookingrepo.MockBookingRepository, bookingID string) {
+				mockDetail := booking.BookingDetailResponse{
+					Booking: booking.Booking{ID: bookingID, UserID: 1, Status: booking.StatusPending},
+				}
+				mockBook.EXPECT().GetDetail(gomock.Any(), bookingID).Return(mockDetail, nil).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotPaid,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, _, mockBook, _ := setup(t)
+
+			// Mock FN
+			tc.mockFn(mockBook, tc.bookingID)
+
+			ctx := authcontext.SetUserID(context.Background(), int64(1))
+			result, err := uc.GetTickets(ctx, tc.bookingID)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, result.Tickets, len(tc.expectedSeats))
+			for i, ticket := range result.Tickets {
+				claims, err := testSigner.Verify(ticket.Code)
+				assert.NoError(t, err)
+				assert.Equal(t, ticketcode.Claims{BookingID: tc.bookingID, SeatID: tc.expectedSeats[i], EventID: 10}, claims)
+			}
+		})
+	}
+}
+
+func TestCancelBooking(t *testing.T) {
+	type testCase struct {
+		name      string
+		userID    int64
+		bookingID string
+		mockFn    func(
+			tx database.TxManager,
+			mockBook bookingrepo.MockBookingRepository,
+			mockSeat seatrepo.MockSeatRepository,
+			mockRefunder bookingusecase.MockRefunder,
+			mockOfferer bookingusecase.MockSeatOfferer,
+			userID int64,
+			bookingID string,
+		)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:      "success",
+			userID:    1,
+			bookingID: "mock-uuid-1",
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, userID int64, bookingID string) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, Status: booking.StatusPending}
+				mockBook.EXPECT().GetByID(gomock.Any(), bookingID).Return(mockBookData, nil).Times(1)
+
+				mockBook.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), mockBookData.ID, booking.StatusPending, booking.StatusCancelled).Return(nil).Times(1)
+
+				mockSeat.EXPECT().CancelSeatsTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return([]int64{11, 20}, nil).Times(1)
+
+				mockOfferer.EXPECT().OfferSeatsTx(gomock.Any(), gomock.Any(), mockBookData.EventID).Return(nil).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:      "fail user other booking",
+			userID:    2,
+			bookingID: "mock-uuid-1",
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, userID int64, bookingID string) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 2, Status: booking.StatusPending}
+				mockBook.EXPECT().GetByID(gomock.Any(), bookingID).Return(mockBookData, nil).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotOwned,
+		},
+		{
+			name:      "fail cancel already",
+			userID:    1,
+			bookingID: "mock-uuid-1",
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, userID int64, bookingID string) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, Status: booking.StatusCancelled}
+				mockBook.EXPECT().GetByID(gomock.Any(), bookingID).Return(mockBookData, nil).Times(1)
+			},
+			expectedErr: errs.ErrBookingIsCancel,
+		},
+		{
+			name:      "success refund paid booking",
+			userID:    1,
+			bookingID: "mock-uuid-1",
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, userID int64, bookingID string) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, TotalAmount: thb(300), Status: booking.StatusPaid}
+				mockBook.EXPECT().GetByID(gomock.Any(), bookingID).Return(mockBookData, nil).Times(1)
+
+				mockBook.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), mockBookData.ID, booking.StatusPaid, booking.StatusRefunded).Return(nil).Times(1)
+
+				mockSeat.EXPECT().CancelSeatsTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return([]int64{11, 20}, nil).Times(1)
+
+				mockOfferer.EXPECT().OfferSeatsTx(gomock.Any(), gomock.Any(), mockBookData.EventID).Return(nil).Times(1)
+
+				mockRefunder.EXPECT().RefundTx(gomock.Any(), gomock.Any(), mockBookData, mockBookData.TotalAmount, true).Return(thb(150), nil).Times(1)
+
+				mockRefunder.EXPECT().SendRefunds(gomock.Any(), mockBookData.ID).Return(nil).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:      "fail paid booking not refundable",
+			userID:    1,
+			bookingID: "mock-uuid-1",
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, userID int64, bookingID string) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, TotalAmount: thb(300), Status: booking.StatusPaid}
+				mockBook.EXPECT().GetByID(gomock.Any(), bookingID).Return(mockBookData, nil).Times(1)
+
+				mockBook.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), mockBookData.ID, booking.StatusPaid, booking.StatusRefunded).Return(nil).Times(1)
+
+				mockSeat.EXPECT().CancelSeatsTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return([]int64{11, 20}, nil).Times(1)
+
+				mockOfferer.EXPECT().OfferSeatsTx(gomock.Any(), gomock.Any(), mockBookData.EventID).Return(nil).Times(1)
+
+				mockRefunder.EXPECT().RefundTx(gomock.Any(), gomock.Any(), mockBookData, mockBookData.TotalAmount, true).Return(money.Money{}, errs.ErrBookingNotRefundable).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotRefundable,
+		},
+		{
+			name:      "fail refund checked-in seats",
+			userID:    1,
+			bookingID: "mock-uuid-1",
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, userID int64, bookingID string) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, TotalAmount: thb(300), Status: booking.StatusPaid}
+				mockBook.EXPECT().GetByID(gomock.Any(), bookingID).Return(mockBookData, nil).Times(1)
+
+				mockBook.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), mockBookData.ID, booking.StatusPaid, booking.StatusRefunded).Return(nil).Times(1)
+
+				mockSeat.EXPECT().CancelSeatsTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(nil, errs.ErrSeatCheckedIn).Times(1)
+			},
+			expectedErr: errs.ErrSeatCheckedIn,
+		},
+		{
+			name:      "fail refund already",
+			userID:    1,
+			bookingID: "mock-uuid-1",
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, userID int64, bookingID string) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, Status: booking.StatusRefunded}
+				mockBook.EXPECT().GetByID(gomock.Any(), bookingID).Return(mockBookData, nil).Times(1)
+			},
+			expectedErr: errs.ErrBookingIsRefunded,
+		},
+		{
+			name:      "fail cancel booking",
+			userID:    1,
+			bookingID: "mock-uuid-1",
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, userID int64, bookingID string) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, Status: booking.StatusPending}
+				mockBook.EXPECT().GetByID(gomock.Any(), bookingID).Return(mockBookData, nil).Times(1)
+
+				mockBook.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), mockBookData.ID, booking.StatusPending, booking.StatusCancelled).Return(ErrMockDBError).Times(1)
+			},
+			expectedErr: ErrMockDBError,
+		},
+		{
+			name:      "fail cancel seats",
+			userID:    1,
+			bookingID: "mock-uuid-1",
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, userID int64, bookingID string) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, Status: booking.StatusPending}
+				mockBook.EXPECT().GetByID(gomock.Any(), bookingID).Return(mockBookData, nil).Times(1)
+
+				mockBook.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), mockBookData.ID, booking.StatusPending, booking.StatusCancelled).Return(nil).Times(1)
+
+				mockSeat.EXPECT().CancelSeatsTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(nil, ErrMockDBError).Times(1)
+			},
+			expectedErr: ErrMockDBError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, mockTx, m := setupMocks(t)
+
+			// Mock FN
+			tc.mockFn(mockTx, *m.book, *m.seat, *m.refunder, *m.offerer, tc.userID, tc.bookingID)
+			if tc.expectedErr == nil {
+				m.notifier.EXPECT().SeatsChanged(gomock.Any(), gomock.Any(), []int64{11, 20}).Times(1)
+			}
+
+			ctx := authcontext.SetUserID(context.Background(), int64(1))
+			_, err := uc.CancelBooking(ctx, tc.bookingID)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCancelSeats(t *testing.T) {
+	activeIDs := []int64{1, 2, 3}
+
+	type testCase struct {
+		name    string
+		seatIDs []int64
+		mockFn  func(
+			mockBook bookingrepo.MockBookingRepository,
+			mockSeat seatrepo.MockSeatRepository,
+			mockEvent eventrepo.MockEventRepository,
+			mockRefunder bookingusecase.MockRefunder,
+			mockOfferer bookingusecase.MockSeatOfferer,
+			seatIDs []int64,
+		)
+		expectedTotal  money.Money
+		expectedRefund money.Money
+		expectedErr    error
+	}
+
+	testCases := []testCase{
+		{
+			name:    "success pending booking",
+			seatIDs: []int64{1},
+			mockFn: func(mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockEvent eventrepo.MockEventRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, seatIDs []int64) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, TotalAmount: thb(300), Status: booking.StatusPending}
+				mockBook.EXPECT().GetByID(gomock.Any(), mockBookData.ID).Return(mockBookData, nil).Times(1)
+
+				mockEvent.EXPECT().GetEventByID(gomock.Any(), mockBookData.EventID).Return(event.Event{Currency: "THB"}, nil).Times(1)
+
+				mockEvent.EXPECT().GetFees(gomock.Any(), mockBookData.EventID).Return(nil, nil).Times(1)
+
+				mockBook.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(mockBookData, nil).Times(1)
+
+				mockBook.EXPECT().GetActiveSeatIDsTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(activeIDs, nil).Times(1)
+
+				mockSeat.EXPECT().RemoveSeatsTx(gomock.Any(), gomock.Any(), mockBookData.ID, seatIDs).Return(nil).Times(1)
+
+				mockOfferer.EXPECT().OfferSeatsTx(gomock.Any(), gomock.Any(), mockBookData.EventID).Return(nil).Times(1)
+
+				mockBook.EXPECT().RecalculateTotalTx(gomock.Any(), gomock.Any(), mockBookData.ID, gomock.Any(), gomock.Any()).Return(thb(200), nil).Times(1)
+			},
+			expectedTotal:  thb(200),
+			expectedRefund: thb(0),
+			expectedErr:    nil,
+		},
+		{
+			name:    "success paid booking refunds removed seats",
+			seatIDs: []int64{1, 2},
+			mockFn: func(mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockEvent eventrepo.MockEventRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, seatIDs []int64) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, TotalAmount: thb(300), Status: booking.StatusPaid}
+				mockBook.EXPECT().GetByID(gomock.Any(), mockBookData.ID).Return(mockBookData, nil).Times(1)
+
+				mockEvent.EXPECT().GetEventByID(gomock.Any(), mockBookData.EventID).Return(event.Event{Currency: "THB"}, nil).Times(1)
+
+				mockEvent.EXPECT().GetFees(gomock.Any(), mockBookData.EventID).Return(nil, nil).Times(1)
+
+				mockBook.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(mockBookData, nil).Times(1)
+
+				mockBook.EXPECT().GetActiveSeatIDsTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(activeIDs, nil).Times(1)
+
+				mockSeat.EXPECT().RemoveSeatsTx(gomock.Any(), gomock.Any(), mockBookData.ID, seatIDs).Return(nil).Times(1)
+
+				mockOfferer.EXPECT().OfferSeatsTx(gomock.Any(), gomock.Any(), mockBookData.EventID).Return(nil).Times(1)
+
+				mockBook.EXPECT().RecalculateTotalTx(gomock.Any(), gomock.Any(), mockBookData.ID, gomock.Any(), gomock.Any()).Return(thb(100), nil).Times(1)
+
+				mockRefunder.EXPECT().RefundTx(gomock.Any(), gomock.Any(), mockBookData, thb(200), false).Return(thb(200), nil).Times(1)
+
+				mockRefunder.EXPECT().SendRefunds(gomock.Any(), mockBookData.ID).Return(nil).Times(1)
+			},
+			expectedTotal:  thb(100),
+			expectedRefund: thb(200),
+			expectedErr:    nil,
+		},
+		{
+			name:    "success last seats cancel booking",
+			seatIDs: []int64{1, 2, 3},
+			mockFn: func(mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockEvent eventrepo.MockEventRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, seatIDs []int64) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, TotalAmount: thb(300), Status: booking.StatusPending}
+				mockBook.EXPECT().GetByID(gomock.Any(), mockBookData.ID).Return(mockBookData, nil).Times(1)
+
+				mockEvent.EXPECT().GetEventByID(gomock.Any(), mockBookData.EventID).Return(event.Event{Currency: "THB"}, nil).Times(1)
+
+				mockEvent.EXPECT().GetFees(gomock.Any(), mockBookData.EventID).Return(nil, nil).Times(1)
+
+				mockBook.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(mockBookData, nil).Times(1)
+
+				mockBook.EXPECT().GetActiveSeatIDsTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(activeIDs, nil).Times(1)
+
+				mockBook.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), mockBookData.ID, booking.StatusPending, booking.StatusCancelled).Return(nil).Times(1)
+
+				mockSeat.EXPECT().CancelSeatsTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return([]int64{11, 20}, nil).Times(1)
+
+				mockOfferer.EXPECT().OfferSeatsTx(gomock.Any(), gomock.Any(), mockBookData.EventID).Return(nil).Times(1)
+			},
+			expectedTotal:  thb(0),
+			expectedRefund: thb(0),
+			expectedErr:    nil,
+		},
+		{
+			name:    "success last paid seats refund booking",
+			seatIDs: []int64{1, 2, 3},
+			mockFn: func(mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockEvent eventrepo.MockEventRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, seatIDs []int64) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, TotalAmount: thb(300), Status: booking.StatusPaid}
+				mockBook.EXPECT().GetByID(gomock.Any(), mockBookData.ID).Return(mockBookData, nil).Times(1)
+
+				mockEvent.EXPECT().GetEventByID(gomock.Any(), mockBookData.EventID).Return(event.Event{Currency: "THB"}, nil).Times(1)
+
+				mockEvent.EXPECT().GetFees(gomock.Any(), mockBookData.EventID).Return(nil, nil).Times(1)
+
+				mockBook.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(mockBookData, nil).Times(1)
+
+				mockBook.EXPECT().GetActiveSeatIDsTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(activeIDs, nil).Times(1)
+
+				mockBook.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), mockBookData.ID, booking.StatusPaid, booking.StatusRefunded).Return(nil).Times(1)
+
+				mockSeat.EXPECT().CancelSeatsTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(seatIDs, nil).Times(1)
+
+				mockOfferer.EXPECT().OfferSeatsTx(gomock.Any(), gomock.Any(), mockBookData.EventID).Return(nil).Times(1)
+
+				mockRefunder.EXPECT().RefundTx(gomock.Any(), gomock.Any(), mockBookData, thb(300), true).Return(thb(300), nil).Times(1)
+
+				mockRefunder.EXPECT().SendRefunds(gomock.Any(), mockBookData.ID).Return(nil).Times(1)
+			},
+			expectedTotal:  thb(0),
+			expectedRefund: thb(300),
+			expectedErr:    nil,
+		},
+		{
+			name:    "success seats removed concurrently cancel booking",
+			seatIDs: []int64{3},
+			mockFn: func(mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockEvent eventrepo.MockEventRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, seatIDs []int64) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, TotalAmount: thb(300), Status: booking.StatusPending}
+				mockBook.EXPECT().GetByID(gomock.Any(), mockBookData.ID).Return(mockBookData, nil).Times(1)
+
+				mockEvent.EXPECT().GetEventByID(gomock.Any(), mockBookData.EventID).Return(event.Event{Currency: "THB"}, nil).Times(1)
+
+				mockEvent.EXPECT().GetFees(gomock.Any(), mockBookData.EventID).Return(nil, nil).Times(1)
+
+				// Seats 1 and 2 left the booking after it was read, seat 3 is the last one
+				lockedData := mockBookData
+				lockedData.TotalAmount = thb(100)
+				mockBook.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(lockedData, nil).Times(1)
+
+				mockBook.EXPECT().GetActiveSeatIDsTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return([]int64{3}, nil).Times(1)
+
+				mockBook.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), mockBookData.ID, booking.StatusPending, booking.StatusCancelled).Return(nil).Times(1)
+
+				mockSeat.EXPECT().CancelSeatsTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(seatIDs, nil).Times(1)
+
+				mockOfferer.EXPECT().OfferSeatsTx(gomock.Any(), gomock.Any(), mockBookData.EventID).Return(nil).Times(1)
+			},
+			expectedTotal:  thb(0),
+			expectedRefund: thb(0),
+			expectedErr:    nil,
+		},
+		{
+			name:    "success paid refund uses locked total",
+			seatIDs: []int64{2},
+			mockFn: func(mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockEvent eventrepo.MockEventRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, seatIDs []int64) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, TotalAmount: thb(300), Status: booking.StatusPaid}
+				mockBook.EXPECT().GetByID(gomock.Any(), mockBookData.ID).Return(mockBookData, nil).Times(1)
+
+				mockEvent.EXPECT().GetEventByID(gomock.Any(), mockBookData.EventID).Return(event.Event{Currency: "THB"}, nil).Times(1)
+
+				mockEvent.EXPECT().GetFees(gomock.Any(), mockBookData.EventID).Return(nil, nil).Times(1)
+
+				// Seat 1 was cancelled after the booking was read
+				lockedData := mockBookData
+				lockedData.TotalAmount = thb(200)
+				mockBook.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(lockedData, nil).Times(1)
+
+				mockBook.EXPECT().GetActiveSeatIDsTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return([]int64{2, 3}, nil).Times(1)
+
+				mockSeat.EXPECT().RemoveSeatsTx(gomock.Any(), gomock.Any(), mockBookData.ID, seatIDs).Return(nil).Times(1)
+
+				mockOfferer.EXPECT().OfferSeatsTx(gomock.Any(), gomock.Any(), mockBookData.EventID).Return(nil).Times(1)
+
+				mockBook.EXPECT().RecalculateTotalTx(gomock.Any(), gomock.Any(), mockBookData.ID, gomock.Any(), gomock.Any()).Return(thb(100), nil).Times(1)
+
+				mockRefunder.EXPECT().RefundTx(gomock.Any(), gomock.Any(), lockedData, thb(100), false).Return(thb(100), nil).Times(1)
+
+				mockRefunder.EXPECT().SendRefunds(gomock.Any(), mockBookData.ID).Return(nil).Times(1)
+			},
+			expectedTotal:  thb(100),
+			expectedRefund: thb(100),
+			expectedErr:    nil,
+		},
+		{
+			name:    "fail seat not in booking",
+			seatIDs: []int64{1, 9},
+			mockFn: func(mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockEvent eventrepo.MockEventRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, seatIDs []int64) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, Status: booking.StatusPending}
+				mockBook.EXPECT().GetByID(gomock.Any(), mockBookData.ID).Return(mockBookData, nil).Times(1)
+
+				mockEvent.EXPECT().GetEventByID(gomock.Any(), mockBookData.EventID).Return(event.Event{Currency: "THB"}, nil).Times(1)
+
+				mockEvent.EXPECT().GetFees(gomock.Any(), mockBookData.EventID).Return(nil, nil).Times(1)
+
+				mockBook.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(mockBookData, nil).Times(1)
+
+				mockBook.EXPECT().GetActiveSeatIDsTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(activeIDs, nil).Times(1)
+			},
+			expectedErr: errs.ErrSeatNotInBooking,
+		},
+		{
+			name:    "fail seat checked in",
+			seatIDs: []int64{1},
+			mockFn: func(mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockEvent eventrepo.MockEventRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, seatIDs []int64) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, TotalAmount: thb(300), Status: booking.StatusPaid}
+				mockBook.EXPECT().GetByID(gomock.Any(), mockBookData.ID).Return(mockBookData, nil).Times(1)
+
+				mockEvent.EXPECT().GetEventByID(gomock.Any(), mockBookData.EventID).Return(event.Event{Currency: "THB"}, nil).Times(1)
+
+				mockEvent.EXPECT().GetFees(gomock.Any(), mockBookData.EventID).Return(nil, nil).Times(1)
+
+				mockBook.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(mockBookData, nil).Times(1)
+
+				mockBook.EXPECT().GetActiveSeatIDsTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(activeIDs, nil).Times(1)
+
+				mockSeat.EXPECT().RemoveSeatsTx(gomock.Any(), gomock.Any(), mockBookData.ID, seatIDs).Return(errs.ErrSeatCheckedIn).Times(1)
+			},
+			expectedErr: errs.ErrSeatCheckedIn,
+		},
+		{
+			name:    "fail user other booking",
+			seatIDs: []int64{1},
+			mockFn: func(mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockEvent eventrepo.MockEventRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, seatIDs []int64) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 2, Status: booking.StatusPending}
+				mockBook.EXPECT().GetByID(gomock.Any(), mockBookData.ID).Return(mockBookData, nil).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotOwned,
+		},
+		{
+			name:    "fail booking failed",
+			seatIDs: []int64{1},
+			mockFn: func(mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockEvent eventrepo.MockEventRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, seatIDs []int64) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, Status: booking.StatusFailed}
+				mockBook.EXPECT().GetByID(gomock.Any(), mockBookData.ID).Return(mockBookData, nil).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotActive,
+		},
+		{
+			name:    "fail refund not allowed",
+			seatIDs: []int64{1},
+			mockFn: func(mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockEvent eventrepo.MockEventRepository, mockRefunder bookingusecase.MockRefunder, mockOfferer bookingusecase.MockSeatOfferer, seatIDs []int64) {
+				mockBookData := booking.Booking{ID: "mock-uuid-1", UserID: 1, TotalAmount: thb(300), Status: booking.StatusPaid}
+				mockBook.EXPECT().GetByID(gomock.Any(), mockBookData.ID).Return(mockBookData, nil).Times(1)
+
+				mockEvent.EXPECT().GetEventByID(gomock.Any(), mockBookData.EventID).Return(event.Event{Currency: "THB"}, nil).Times(1)
+
+				mockEvent.EXPECT().GetFees(gomock.Any(), mockBookData.EventID).Return(nil, nil).Times(1)
+
+				mockBook.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(mockBookData, nil).Times(1)
+
+				mockBook.EXPECT().GetActiveSeatIDsTx(gomock.Any(), gomock.Any(), mockBookData.ID).Return(activeIDs, nil).Times(1)
+
+				mockSeat.EXPECT().RemoveSeatsTx(gomock.Any(), gomock.Any(), mockBookData.ID, seatIDs).Return(nil).Times(1)
+
+				mockOfferer.EXPECT().OfferSeatsTx(gomock.Any(), gomock.Any(), mockBookData.EventID).Return(nil).Times(1)
+
+				mockBook.EXPECT().RecalculateTotalTx(gomock.Any(), gomock.Any(), mockBookData.ID, gomock.Any(), gomock.Any()).Return(thb(200), nil).Times(1)
+
+				mockRefunder.EXPECT().RefundTx(gomock.Any(), gomock.Any(), mockBookData, thb(100), false).Return(money.Money{}, errs.ErrBookingNotRefundable).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotRefundable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, _, m := setupMocks(t)
+
+			// Mock FN
+			tc.mockFn(*m.book, *m.seat, *m.event, *m.refunder, *m.offerer, tc.seatIDs)
+			if tc.expectedErr == nil {
+				m.notifier.EXPECT().SeatsChanged(gomock.Any(), gomock.Any(), gomock.Any()).Times(1)
+			}
+
+			ctx := authcontext.SetUserID(context.Background(), int64(1))
+			result, err := uc.CancelSeats(ctx, "mock-uuid-1", tc.seatIDs)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedTotal, result.TotalAmount)
+				assert.Equal(t, tc.expectedRefund, result.RefundAmount)
+			}
+		})
+	}
+}
+
+func TestExpireBookings(t *testing.T) {
+	type testCase struct {
+		name   string
+		mockFn func(
+			tx database.TxManager,
+			mockBook bookingrepo.MockBookingRepository,
+			mockSeat seatrepo.MockSeatRepository,
+			mockOfferer bookingusecase.MockSeatOfferer,
+			mockNotifier bookingusecase.MockSeatNotifier,
+		)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "success",
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockOfferer bookingusecase.MockSeatOfferer, mockNotifier bookingusecase.MockSeatNotifier) {
+				mockBookings := []booking.Booking{
+					{ID: "mock-uuid-1", EventID: 10},
+					{ID: "mock-uuid-2", EventID: 10},
+					{ID: "mock-uuid-3", EventID: 20},
+				}
+				mockBook.EXPECT().GetExpiredBookingsTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(mockBookings, nil).Times(1)
+
+				for i, b := range mockBookings {
+					mockBook.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), b.ID, booking.StatusPending, booking.StatusFailed).Return(nil).Times(1)
+					mockSeat.EXPECT().CancelSeatsTx(gomock.Any(), gomock.Any(), b.ID).Return([]int64{int64(i + 1)}, nil).Times(1)
+				}
+
+				// One offer round per event
+				mockOfferer.EXPECT().OfferSeatsTx(gomock.Any(), gomock.Any(), int64(10)).Return(nil).Times(1)
+				mockOfferer.EXPECT().OfferSeatsTx(gomock.Any(), gomock.Any(), int64(20)).Return(nil).Times(1)
+
+				// One seat update per event after commit
+				mockNotifier.EXPECT().SeatsChanged(gomock.Any(), int64(10), []int64{1, 2}).Times(1)
+				mockNotifier.EXPECT().SeatsChanged(gomock.Any(), int64(20), []int64{3}).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "success nothing expired",
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockOfferer bookingusecase.MockSeatOfferer, mockNotifier bookingusecase.MockSeatNotifier) {
+				mockBook.EXPECT().GetExpiredBookingsTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "fail get expired",
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockOfferer bookingusecase.MockSeatOfferer, mockNotifier bookingusecase.MockSeatNotifier) {
+				mockBook.EXPECT().GetExpiredBookingsTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, ErrMockDBError).Times(1)
+			},
+			expectedErr: ErrMockDBError,
+		},
+		{
+			name: "fail booking",
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockOfferer bookingusecase.MockSeatOfferer, mockNotifier bookingusecase.MockSeatNotifier) {
+				mockBook.EXPECT().GetExpiredBookingsTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]booking.Booking{{ID: "mock-uuid-1", EventID: 10}}, nil).Times(1)
+
+				mockBook.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), "mock-uuid-1", booking.StatusPending, booking.StatusFailed).Return(ErrMockDBError).Times(1)
+			},
+			expectedErr: ErrMockDBError,
+		},
+		{
+			name: "fail offer seats",
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockOfferer bookingusecase.MockSeatOfferer, mockNotifier bookingusecase.MockSeatNotifier) {
+				mockBook.EXPECT().GetExpiredBookingsTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]booking.Booking{{ID: "mock-uuid-1", EventID: 10}}, nil).Times(1)
+
+				mockBook.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), "mock-uuid-1", booking.StatusPending, booking.StatusFailed).Return(nil).Times(1)
+
+				mockSeat.EXPECT().CancelSeatsTx(gomock.Any(), gomock.Any(), "mock-uuid-1").Return([]int64{11, 20}, nil).Times(1)
+
+				mockOfferer.EXPECT().OfferSeatsTx(gomock.Any(), gomock.Any(), int64(10)).Return(ErrMockDBError).Times(1)
+			},
+			expectedErr: ErrMockDBError,
+		},
+		{
+			name: "fail release seats",
+			mockFn: func(tx database.TxManager, mockBook bookingrepo.MockBookingRepository, mockSeat seatrepo.MockSeatRepository, mockOfferer bookingusecase.MockSeatOfferer, mockNotifier bookingusecase.MockSeatNotifier) {
+				mockBook.EXPECT().GetExpiredBookingsTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]booking.Booking{{ID: "mock-uuid-1", EventID: 10}}, nil).Times(1)
+
+				mockBook.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), "mock-uuid-1", booking.StatusPending, booking.StatusFailed).Return(nil).Times(1)
+
+				mockSeat.EXPECT().CancelSeatsTx(gomock.Any(), gomock.Any(), "mock-uuid-1").Return(nil, ErrMockDBError).Times(1)
+			},
+			expectedErr: ErrMockDBError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, mockTx, m := setupMocks(t)
+
+			// Mock FN
+			tc.mockFn(mockTx, *m.book, *m.seat, *m.offerer, *m.notifier)
+
+			err := uc.ExpireBookings(context.Background())
+
+			if tc.expectedErr != nil {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+type mocks struct {
+	book     *bookingrepo.MockBookingRepository
+	seat     *seatrepo.MockSeatRepository
+	event    *eventrepo.MockEventRepository
+	promo    *promorepo.MockPromoRepository
+	refunder *bookingusecase.MockRefunder
+	offerer  *bookingusecase.MockSeatOfferer
+	notifier *bookingusecase.MockSeatNotifier
+}
+
+func setup(t *testing.T) (bookingusecase.BookingUsecase, mockTx, bookingrepo.MockBookingRepository, seatrepo.MockSeatRepository) {
+	uc, mockTx, m := setupMocks(t)
+	return uc, mockTx, *m.book, *m.seat
+}
+
+func setupMocks(t *testing.T) (bookingusecase.BookingUsecase, mockTx, mocks) {
+	return setupStrategy(t, seat.LockPessimistic)
+}
+
+func setupStrategy(t *testing.T, strategy seat.LockStrategy) (bookingusecase.BookingUsecase, mockTx, mocks) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	loc, _ := time.LoadLocation("Asia/Bangkok")
+
+	mockTx := mockTx{}
+	m := mocks{
+		book:     bookingrepo.NewMockBookingRepository(ctrl),
+		seat:     seatrepo.NewMockSeatRepository(ctrl),
+		event:    eventrepo.NewMockEventRepository(ctrl),
+		promo:    promorepo.NewMockPromoRepository(ctrl),
+		refunder: bookingusecase.NewMockRefunder(ctrl),
+		offerer:  bookingusecase.NewMockSeatOfferer(ctrl),
+		notifier: bookingusecase.NewMockSeatNotifier(ctrl),
+	}
+	uc := bookingusecase.NewBookingUsecase(loc, 15*time.Minute, strategy, mockTx, m.book, m.seat, m.event, m.promo, m.refunder, m.offerer, m.notifier, testSigner)
 
-	return uc, mockTx, *mockBook, *mockSeat
+	return uc, mockTx, m
 }
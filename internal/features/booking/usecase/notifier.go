@@ -0,0 +1,9 @@
+package bookingusecase
+
+import "context"
+
+//go:generate mockgen -source=notifier.go -destination=notifier_mock.go -package=bookingusecase
+type SeatNotifier interface {
+	// SeatsChanged : tell live seat maps of the event that these seats changed status, call it after commit
+	SeatsChanged(ctx context.Context, eventID int64, seatIDs []int64)
+}
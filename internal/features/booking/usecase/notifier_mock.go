@@ -0,0 +1,47 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: notifier.go
+
+// Package bookingusecase is a generated GoMock package.
+package bookingusecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSeatNotifier is a mock of SeatNotifier interface.
+type MockSeatNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockSeatNotifierMockRecorder
+}
+
+// MockSeatNotifierMockRecorder is the mock recorder for MockSeatNotifier.
+type MockSeatNotifierMockRecorder struct {
+	mock *MockSeatNotifier
+}
+
+// NewMockSeatNotifier creates a new mock instance.
+func NewMockSeatNotifier(ctrl *gomock.Controller) *MockSeatNotifier {
+	mock := &MockSeatNotifier{ctrl: ctrl}
+	mock.recorder = &MockSeatNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSeatNotifier) EXPECT() *MockSeatNotifierMockRecorder {
+	return m.recorder
+}
+
+// SeatsChanged mocks base method.
+func (m *MockSeatNotifier) SeatsChanged(ctx context.Context, eventID int64, seatIDs []int64) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SeatsChanged", ctx, eventID, seatIDs)
+}
+
+// SeatsChanged indicates an expected call of SeatsChanged.
+func (mr *MockSeatNotifierMockRecorder) SeatsChanged(ctx, eventID, seatIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeatsChanged", reflect.TypeOf((*MockSeatNotifier)(nil).SeatsChanged), ctx, eventID, seatIDs)
+}
@@ -0,0 +1,12 @@
+package bookingusecase
+
+import (
+	"context"
+	"database/sql"
+)
+
+//go:generate mockgen -source=offerer.go -destination=offerer_mock.go -package=bookingusecase
+type SeatOfferer interface {
+	// OfferSeatsTx : hand seats that just became AVAILABLE to the event waitlist
+	OfferSeatsTx(ctx context.Context, tx *sql.Tx, eventID int64) error
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: offerer.go
+
+// Package bookingusecase is a generated GoMock package.
+package bookingusecase
+
+import (
+	context "context"
+	sql "database/sql"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSeatOfferer is a mock of SeatOfferer interface.
+type MockSeatOfferer struct {
+	ctrl     *gomock.Controller
+	recorder *MockSeatOffererMockRecorder
+}
+
+// MockSeatOffererMockRecorder is the mock recorder for MockSeatOfferer.
+type MockSeatOffererMockRecorder struct {
+	mock *MockSeatOfferer
+}
+
+// NewMockSeatOfferer creates a new mock instance.
+func NewMockSeatOfferer(ctrl *gomock.Controller) *MockSeatOfferer {
+	mock := &MockSeatOfferer{ctrl: ctrl}
+	mock.recorder = &MockSeatOffererMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSeatOfferer) EXPECT() *MockSeatOffererMockRecorder {
+	return m.recorder
+}
+
+// OfferSeatsTx mocks base method.
+func (m *MockSeatOfferer) OfferSeatsTx(ctx context.Context, tx *sql.Tx, eventID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OfferSeatsTx", ctx, tx, eventID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OfferSeatsTx indicates an expected call of OfferSeatsTx.
+func (mr *MockSeatOffererMockRecorder) OfferSeatsTx(ctx, tx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OfferSeatsTx", reflect.TypeOf((*MockSeatOfferer)(nil).OfferSeatsTx), ctx, tx, eventID)
+}
@@ -0,0 +1,17 @@
+package bookingusecase
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+)
+
+//go:generate mockgen -source=refunder.go -destination=refunder_mock.go -package=bookingusecase
+type Refunder interface {
+	// RefundTx : records a PENDING refund, returns the amount refunded after the event policy
+	RefundTx(ctx context.Context, tx *sql.Tx, bookData booking.Booking, amount money.Money, closePayment bool) (money.Money, error)
+	// SendRefunds : pays out the PENDING refunds of a booking, called after the transaction commits
+	SendRefunds(ctx context.Context, bookingID string) error
+}
@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: refunder.go
+
+// Package bookingusecase is a generated GoMock package.
+package bookingusecase
+
+import (
+	context "context"
+	sql "database/sql"
+	reflect "reflect"
+
+	booking "github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+	money "github.com/codepnw/stdlib-ticket-system/pkg/money"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRefunder is a mock of Refunder interface.
+type MockRefunder struct {
+	ctrl     *gomock.Controller
+	recorder *MockRefunderMockRecorder
+}
+
+// MockRefunderMockRecorder is the mock recorder for MockRefunder.
+type MockRefunderMockRecorder struct {
+	mock *MockRefunder
+}
+
+// NewMockRefunder creates a new mock instance.
+func NewMockRefunder(ctrl *gomock.Controller) *MockRefunder {
+	mock := &MockRefunder{ctrl: ctrl}
+	mock.recorder = &MockRefunderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRefunder) EXPECT() *MockRefunderMockRecorder {
+	return m.recorder
+}
+
+// RefundTx mocks base method.
+func (m *MockRefunder) RefundTx(ctx context.Context, tx *sql.Tx, bookData booking.Booking, amount money.Money, closePayment bool) (money.Money, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundTx", ctx, tx, bookData, amount, closePayment)
+	ret0, _ := ret[0].(money.Money)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefundTx indicates an expected call of RefundTx.
+func (mr *MockRefunderMockRecorder) RefundTx(ctx, tx, bookData, amount, closePayment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundTx", reflect.TypeOf((*MockRefunder)(nil).RefundTx), ctx, tx, bookData, amount, closePayment)
+}
+
+// SendRefunds mocks base method.
+func (m *MockRefunder) SendRefunds(ctx context.Context, bookingID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendRefunds", ctx, bookingID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SendRefunds indicates an expected call of SendRefunds.
+func (mr *MockRefunderMockRecorder) SendRefunds(ctx, bookingID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendRefunds", reflect.TypeOf((*MockRefunder)(nil).SendRefunds), ctx, bookingID)
+}
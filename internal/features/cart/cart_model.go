@@ -0,0 +1,20 @@
+package cart
+
+import (
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+)
+
+// Item : one seat held in a user's cart, every item of an event shares its ExpiresAt
+type Item struct {
+	ID         int64       `json:"id" db:"id"`
+	UserID     int64       `json:"user_id" db:"user_id"`
+	EventID    int64       `json:"event_id" db:"event_id"`
+	SeatID     int64       `json:"seat_id" db:"seat_id"`
+	SeatNumber string      `json:"seat_number" db:"-"`
+	Zone       string      `json:"zone" db:"-"`
+	Price      money.Money `json:"price" db:"-"`
+	ExpiresAt  time.Time   `json:"expires_at" db:"expires_at"`
+	CreatedAt  time.Time   `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,5 @@
+package carthandler
+
+type AddSeatsReq struct {
+	SeatIDs []int64 `json:"seat_ids" validate:"required,min=1,unique"`
+}
@@ -0,0 +1,107 @@
+package carthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	cartusecase "github.com/codepnw/stdlib-ticket-system/internal/features/cart/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/helper"
+	"github.com/codepnw/stdlib-ticket-system/pkg/utils"
+)
+
+type cartHandler struct {
+	uc cartusecase.CartUsecase
+}
+
+func NewCartHandler(uc cartusecase.CartUsecase) *cartHandler {
+	return &cartHandler{uc: uc}
+}
+
+func (h *cartHandler) AddSeats(w http.ResponseWriter, r *http.Request) {
+	eventID, err := helper.ParseInt64(r.PathValue("event_id"))
+	if err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req AddSeatsReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := utils.Validate(&req); err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.uc.AddSeats(r.Context(), eventID, req.SeatIDs)
+	if err != nil {
+		var unavailable *errs.SeatsUnavailableError
+		switch {
+		case errors.Is(err, errs.ErrEventNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, errs.ErrSeatNotInEvent):
+			helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.As(err, &unavailable):
+			helper.ErrorDataResponse(w, http.StatusConflict, err.Error(), map[string][]int64{"seat_ids": unavailable.SeatIDs})
+		case errors.Is(err, errs.ErrPurchaseLimitExceeded), errors.Is(err, errs.ErrSomeSeatNotAvailable),
+			errors.Is(err, errs.ErrCurrencyMismatch):
+			helper.ErrorResponse(w, http.StatusConflict, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	helper.SuccessResponse(w, http.StatusCreated, "seats added to cart", data)
+}
+
+func (h *cartHandler) GetCart(w http.ResponseWriter, r *http.Request) {
+	data, err := h.uc.GetCart(r.Context())
+	if err != nil {
+		helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	helper.SuccessResponse(w, http.StatusOK, "", data)
+}
+
+func (h *cartHandler) RemoveEvent(w http.ResponseWriter, r *http.Request) {
+	eventID, err := helper.ParseInt64(r.PathValue("event_id"))
+	if err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.uc.RemoveEvent(r.Context(), eventID); err != nil {
+		switch {
+		case errors.Is(err, errs.ErrCartEventNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	helper.SuccessResponse(w, http.StatusOK, "event removed from cart", nil)
+}
+
+func (h *cartHandler) Checkout(w http.ResponseWriter, r *http.Request) {
+	data, err := h.uc.Checkout(r.Context())
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrCartEmpty):
+			helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errs.ErrCartHoldExpired), errors.Is(err, errs.ErrPurchaseLimitExceeded),
+			errors.Is(err, errs.ErrZoneNotOnSale):
+			helper.ErrorResponse(w, http.StatusConflict, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	helper.SuccessResponse(w, http.StatusOK, "cart checked out", data)
+}
@@ -0,0 +1,194 @@
+package cartrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/cart"
+	"github.com/lib/pq"
+)
+
+//go:generate mockgen -source=cart_repo.go -destination=cart_repo_mock.go -package=cartrepo
+type CartRepository interface {
+	GetByUserID(ctx context.Context, userID int64) ([]cart.Item, error)
+
+	// Transaction
+	CountEventSeatsTx(ctx context.Context, tx *sql.Tx, userID, eventID int64) (int, error)
+	AddItemsTx(ctx context.Context, tx *sql.Tx, userID, eventID int64, seatIDs []int64, expiresAt time.Time) error
+	RemoveEventTx(ctx context.Context, tx *sql.Tx, userID, eventID int64) ([]int64, error)
+	GetForUpdateTx(ctx context.Context, tx *sql.Tx, userID int64) ([]cart.Item, error)
+	ClearTx(ctx context.Context, tx *sql.Tx, userID int64) error
+	DeleteExpiredTx(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]cart.Item, error)
+}
+
+type cartRepository struct {
+	db *sql.DB
+}
+
+func NewCartRepository(db *sql.DB) CartRepository {
+	return &cartRepository{db: db}
+}
+
+func (r *cartRepository) GetByUserID(ctx context.Context, userID int64) ([]cart.Item, error) {
+	query := `
+		SELECT c.id, c.user_id, c.event_id, c.seat_id, s.seat_number, s.zone, s.price, e.currency, c.expires_at, c.created_at
+		FROM cart_items c
+		JOIN seats s ON s.id = c.seat_id
+		JOIN events e ON e.id = c.event_id
+		WHERE c.user_id = $1
+		ORDER BY c.event_id ASC, c.seat_id ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []cart.Item
+	for rows.Next() {
+		var i cart.Item
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.EventID,
+			&i.SeatID,
+			&i.SeatNumber,
+			&i.Zone,
+			&i.Price.Amount,
+			&i.Price.Currency,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// CountEventSeatsTx : seats of the event already in the user's cart
+func (r *cartRepository) CountEventSeatsTx(ctx context.Context, tx *sql.Tx, userID, eventID int64) (int, error) {
+	query := `SELECT COUNT(*) FROM cart_items WHERE user_id = $1 AND event_id = $2`
+	var count int
+	if err := tx.QueryRowContext(ctx, query, userID, eventID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// AddItemsTx : put the seats in the cart and move the hold of the whole event to expiresAt
+func (r *cartRepository) AddItemsTx(ctx context.Context, tx *sql.Tx, userID, eventID int64, seatIDs []int64, expiresAt time.Time) error {
+	query := `
+		INSERT INTO cart_items (user_id, event_id, seat_id, expires_at)
+		SELECT $1, $2, UNNEST($3::BIGINT[]), $4
+	`
+	if _, err := tx.ExecContext(ctx, query, userID, eventID, pq.Array(seatIDs), expiresAt); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pq.ErrorCode("23505") {
+			return errs.ErrSomeSeatNotAvailable
+		}
+		return err
+	}
+
+	query = `UPDATE cart_items SET expires_at = $3 WHERE user_id = $1 AND event_id = $2`
+	_, err := tx.ExecContext(ctx, query, userID, eventID, expiresAt)
+	return err
+}
+
+// RemoveEventTx : drop every seat of the event from the cart, returns the seat IDs
+func (r *cartRepository) RemoveEventTx(ctx context.Context, tx *sql.Tx, userID, eventID int64) ([]int64, error) {
+	query := `DELETE FROM cart_items WHERE user_id = $1 AND event_id = $2 RETURNING seat_id`
+	rows, err := tx.QueryContext(ctx, query, userID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seatIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		seatIDs = append(seatIDs, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(seatIDs) == 0 {
+		return nil, errs.ErrCartEventNotFound
+	}
+	return seatIDs, nil
+}
+
+// GetForUpdateTx : lock the user's cart, so the expiry sweep skips it during checkout
+func (r *cartRepository) GetForUpdateTx(ctx context.Context, tx *sql.Tx, userID int64) ([]cart.Item, error) {
+	query := `
+		SELECT id, user_id, event_id, seat_id, expires_at, created_at
+		FROM cart_items
+		WHERE user_id = $1
+		ORDER BY event_id ASC, seat_id ASC
+		FOR UPDATE
+	`
+	return r.queryItemsTx(ctx, tx, query, userID)
+}
+
+func (r *cartRepository) ClearTx(ctx context.Context, tx *sql.Tx, userID int64) error {
+	query := `DELETE FROM cart_items WHERE user_id = $1`
+	_, err := tx.ExecContext(ctx, query, userID)
+	return err
+}
+
+// DeleteExpiredTx : drop up to limit items whose hold has passed, skip carts in checkout
+func (r *cartRepository) DeleteExpiredTx(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]cart.Item, error) {
+	query := `
+		WITH expired AS (
+			SELECT id FROM cart_items
+			WHERE expires_at <= $1
+			ORDER BY expires_at ASC, id ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		DELETE FROM cart_items c USING expired
+		WHERE c.id = expired.id
+		RETURNING c.id, c.user_id, c.event_id, c.seat_id, c.expires_at, c.created_at
+	`
+	return r.queryItemsTx(ctx, tx, query, now, limit)
+}
+
+func (r *cartRepository) queryItemsTx(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]cart.Item, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []cart.Item
+	for rows.Next() {
+		var i cart.Item
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.EventID,
+			&i.SeatID,
+			&i.ExpiresAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
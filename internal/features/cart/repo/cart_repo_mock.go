@@ -0,0 +1,141 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cart_repo.go
+
+// Package cartrepo is a generated GoMock package.
+package cartrepo
+
+import (
+	context "context"
+	sql "database/sql"
+	reflect "reflect"
+	time "time"
+
+	cart "github.com/codepnw/stdlib-ticket-system/internal/features/cart"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCartRepository is a mock of CartRepository interface.
+type MockCartRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCartRepositoryMockRecorder
+}
+
+// MockCartRepositoryMockRecorder is the mock recorder for MockCartRepository.
+type MockCartRepositoryMockRecorder struct {
+	mock *MockCartRepository
+}
+
+// NewMockCartRepository creates a new mock instance.
+func NewMockCartRepository(ctrl *gomock.Controller) *MockCartRepository {
+	mock := &MockCartRepository{ctrl: ctrl}
+	mock.recorder = &MockCartRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCartRepository) EXPECT() *MockCartRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddItemsTx mocks base method.
+func (m *MockCartRepository) AddItemsTx(ctx context.Context, tx *sql.Tx, userID int64, eventID int64, seatIDs []int64, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddItemsTx", ctx, tx, userID, eventID, seatIDs, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddItemsTx indicates an expected call of AddItemsTx.
+func (mr *MockCartRepositoryMockRecorder) AddItemsTx(ctx, tx, userID, eventID, seatIDs, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddItemsTx", reflect.TypeOf((*MockCartRepository)(nil).AddItemsTx), ctx, tx, userID, eventID, seatIDs, expiresAt)
+}
+
+// ClearTx mocks base method.
+func (m *MockCartRepository) ClearTx(ctx context.Context, tx *sql.Tx, userID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearTx", ctx, tx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearTx indicates an expected call of ClearTx.
+func (mr *MockCartRepositoryMockRecorder) ClearTx(ctx, tx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearTx", reflect.TypeOf((*MockCartRepository)(nil).ClearTx), ctx, tx, userID)
+}
+
+// CountEventSeatsTx mocks base method.
+func (m *MockCartRepository) CountEventSeatsTx(ctx context.Context, tx *sql.Tx, userID int64, eventID int64) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountEventSeatsTx", ctx, tx, userID, eventID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountEventSeatsTx indicates an expected call of CountEventSeatsTx.
+func (mr *MockCartRepositoryMockRecorder) CountEventSeatsTx(ctx, tx, userID, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountEventSeatsTx", reflect.TypeOf((*MockCartRepository)(nil).CountEventSeatsTx), ctx, tx, userID, eventID)
+}
+
+// DeleteExpiredTx mocks base method.
+func (m *MockCartRepository) DeleteExpiredTx(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]cart.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredTx", ctx, tx, now, limit)
+	ret0, _ := ret[0].([]cart.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredTx indicates an expected call of DeleteExpiredTx.
+func (mr *MockCartRepositoryMockRecorder) DeleteExpiredTx(ctx, tx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredTx", reflect.TypeOf((*MockCartRepository)(nil).DeleteExpiredTx), ctx, tx, now, limit)
+}
+
+// GetByUserID mocks base method.
+func (m *MockCartRepository) GetByUserID(ctx context.Context, userID int64) ([]cart.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID)
+	ret0, _ := ret[0].([]cart.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockCartRepositoryMockRecorder) GetByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockCartRepository)(nil).GetByUserID), ctx, userID)
+}
+
+// GetForUpdateTx mocks base method.
+func (m *MockCartRepository) GetForUpdateTx(ctx context.Context, tx *sql.Tx, userID int64) ([]cart.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdateTx", ctx, tx, userID)
+	ret0, _ := ret[0].([]cart.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdateTx indicates an expected call of GetForUpdateTx.
+func (mr *MockCartRepositoryMockRecorder) GetForUpdateTx(ctx, tx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdateTx", reflect.TypeOf((*MockCartRepository)(nil).GetForUpdateTx), ctx, tx, userID)
+}
+
+// RemoveEventTx mocks base method.
+func (m *MockCartRepository) RemoveEventTx(ctx context.Context, tx *sql.Tx, userID int64, eventID int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveEventTx", ctx, tx, userID, eventID)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveEventTx indicates an expected call of RemoveEventTx.
+func (mr *MockCartRepositoryMockRecorder) RemoveEventTx(ctx, tx, userID, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveEventTx", reflect.TypeOf((*MockCartRepository)(nil).RemoveEventTx), ctx, tx, userID, eventID)
+}
@@ -0,0 +1,417 @@
+package cartusecase
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/authcontext"
+	"github.com/codepnw/stdlib-ticket-system/internal/config"
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+	bookingrepo "github.com/codepnw/stdlib-ticket-system/internal/features/booking/repo"
+	bookingusecase "github.com/codepnw/stdlib-ticket-system/internal/features/booking/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/cart"
+	cartrepo "github.com/codepnw/stdlib-ticket-system/internal/features/cart/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	eventrepo "github.com/codepnw/stdlib-ticket-system/internal/features/event/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/seat"
+	seatrepo "github.com/codepnw/stdlib-ticket-system/internal/features/seat/repo"
+	"github.com/codepnw/stdlib-ticket-system/pkg/database"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+)
+
+// expireBatchSize : max cart items released per sweep
+const expireBatchSize = 500
+
+type CartUsecase interface {
+	AddSeats(ctx context.Context, eventID int64, seatIDs []int64) (displayCart, error)
+	GetCart(ctx context.Context) (displayCart, error)
+	RemoveEvent(ctx context.Context, eventID int64) error
+	Checkout(ctx context.Context) ([]displayCheckout, error)
+	ExpireHolds(ctx context.Context) error
+}
+
+type cartUsecase struct {
+	location    *time.Location
+	cartHold    time.Duration
+	bookingHold time.Duration
+	tx          database.TxManager
+	cartRepo    cartrepo.CartRepository
+	bookRepo    bookingrepo.BookingRepository
+	seatRepo    seatrepo.SeatRepository
+	eventRepo   eventrepo.EventRepository
+	offerer     bookingusecase.SeatOfferer
+	notifier    bookingusecase.SeatNotifier
+}
+
+func NewCartUsecase(location *time.Location, cartHold, bookingHold time.Duration, tx database.TxManager, cartRepo cartrepo.CartRepository, bookRepo bookingrepo.BookingRepository, seatRepo seatrepo.SeatRepository, eventRepo eventrepo.EventRepository, offerer bookingusecase.SeatOfferer, notifier bookingusecase.SeatNotifier) CartUsecase {
+	return &cartUsecase{
+		location:    location,
+		cartHold:    cartHold,
+		bookingHold: bookingHold,
+		tx:          tx,
+		cartRepo:    cartRepo,
+		bookRepo:    bookRepo,
+		seatRepo:    seatRepo,
+		eventRepo:   eventRepo,
+		offerer:     offerer,
+		notifier:    notifier,
+	}
+}
+
+type displayCartSeat struct {
+	SeatID     int64       `json:"seat_id"`
+	SeatNumber string      `json:"seat_number"`
+	Zone       string      `json:"zone"`
+	Price      money.Money `json:"price"`
+}
+
+type displayCartEvent struct {
+	EventID   int64             `json:"event_id"`
+	Seats     []displayCartSeat `json:"seats"`
+	Subtotal  money.Money       `json:"subtotal"`
+	ExpiresAt string            `json:"expires_at"`
+}
+
+type displayCart struct {
+	Events      []displayCartEvent `json:"events"`
+	TotalAmount money.Money        `json:"total_amount"`
+}
+
+// AddSeats : hold AVAILABLE seats of the event in the cart, the event's hold starts over.
+// Every event in a cart must be priced in the same currency so it has one total.
+func (u *cartUsecase) AddSeats(ctx context.Context, eventID int64, seatIDs []int64) (displayCart, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	userID := authcontext.GetUserID(ctx)
+	expiresAt := time.Now().Add(u.cartHold)
+
+	// 1. Check Event & Order Limit
+	eventData, err := u.eventRepo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return displayCart{}, err
+	}
+	if err := eventData.PurchaseLimit(len(seatIDs), 0); err != nil {
+		return displayCart{}, err
+	}
+	items, err := u.cartRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return displayCart{}, err
+	}
+	if len(items) > 0 && items[0].Price.Currency != eventData.Currency {
+		return displayCart{}, errs.ErrCurrencyMismatch
+	}
+
+	err = u.tx.WithRetryTx(ctx, func(tx *sql.Tx) error {
+		// 2. Check User Limit, seats in the cart become one order at checkout
+		if err := u.bookRepo.LockUserEventTx(ctx, tx, userID, eventID); err != nil {
+			return err
+		}
+		inCart, err := u.cartRepo.CountEventSeatsTx(ctx, tx, userID, eventID)
+		if err != nil {
+			return err
+		}
+		held, err := u.bookRepo.CountUserSeatsTx(ctx, tx, userID, eventID)
+		if err != nil {
+			return err
+		}
+		if err := eventData.PurchaseLimit(inCart+len(seatIDs), held); err != nil {
+			return err
+		}
+
+		// 3. Lock & Validate Seats
+		seats, err := u.seatRepo.GetSeatsForUpdateTx(ctx, tx, seatIDs)
+		if err != nil {
+			return err
+		}
+		if err := seat.CheckAvailable(eventID, seatIDs, seats); err != nil {
+			return err
+		}
+
+		// 4. Hold Seats in the cart
+		if err := u.seatRepo.UpdateSeatsStatusTx(ctx, tx, seatIDs, string(seat.StatusReserved)); err != nil {
+			return err
+		}
+		return u.cartRepo.AddItemsTx(ctx, tx, userID, eventID, seatIDs, expiresAt)
+	})
+	if err != nil {
+		return displayCart{}, err
+	}
+	u.notifier.SeatsChanged(ctx, eventID, seatIDs)
+
+	return u.getCart(ctx, userID)
+}
+
+func (u *cartUsecase) GetCart(ctx context.Context) (displayCart, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	return u.getCart(ctx, authcontext.GetUserID(ctx))
+}
+
+func (u *cartUsecase) getCart(ctx context.Context, userID int64) (displayCart, error) {
+	items, err := u.cartRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return displayCart{}, err
+	}
+
+	result := displayCart{Events: []displayCartEvent{}}
+	if len(items) > 0 {
+		result.TotalAmount = money.Zero(items[0].Price.Currency)
+	}
+	for _, group := range groupByEvent(items) {
+		event := displayCartEvent{
+			EventID:   group[0].EventID,
+			Seats:     make([]displayCartSeat, 0, len(group)),
+			Subtotal:  money.Zero(group[0].Price.Currency),
+			ExpiresAt: group[0].ExpiresAt.In(u.location).Format(time.DateTime),
+		}
+		for _, i := range group {
+			event.Seats = append(event.Seats, displayCartSeat{
+				SeatID:     i.SeatID,
+				SeatNumber: i.SeatNumber,
+				Zone:       i.Zone,
+				Price:      i.Price,
+			})
+			if event.Subtotal, err = event.Subtotal.Add(i.Price); err != nil {
+				return displayCart{}, err
+			}
+		}
+		result.Events = append(result.Events, event)
+		if result.TotalAmount, err = result.TotalAmount.Add(event.Subtotal); err != nil {
+			return displayCart{}, err
+		}
+	}
+	return result, nil
+}
+
+// RemoveEvent : drop the event from the cart and free its seats
+func (u *cartUsecase) RemoveEvent(ctx context.Context, eventID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	userID := authcontext.GetUserID(ctx)
+
+	var released []int64
+	err := u.tx.WithTx(ctx, func(tx *sql.Tx) error {
+		seatIDs, err := u.cartRepo.RemoveEventTx(ctx, tx, userID, eventID)
+		if err != nil {
+			return err
+		}
+		if err := u.seatRepo.UpdateSeatsStatusTx(ctx, tx, seatIDs, string(seat.StatusAvailable)); err != nil {
+			return err
+		}
+		released = seatIDs
+
+		// Freed Seats go to the waitlist first
+		return u.offerer.OfferSeatsTx(ctx, tx, eventID)
+	})
+	if err != nil {
+		return err
+	}
+	u.notifier.SeatsChanged(ctx, eventID, released)
+	return nil
+}
+
+type displayCheckout struct {
+	BookingID   string      `json:"booking_id"`
+	EventID     int64       `json:"event_id"`
+	SeatIDs     []int64     `json:"seat_ids"`
+	TotalAmount money.Money `json:"total_amount"`
+	Status      string      `json:"status"`
+	ExpiresAt   string      `json:"expires_at"`
+}
+
+// Checkout : turn the whole cart into one PENDING booking per event, all or nothing.
+// Cart seats sell at their zone price: the cart has no ticket types or promo codes,
+// a buyer who wants them books the event directly through POST /bookings
+func (u *cartUsecase) Checkout(ctx context.Context) ([]displayCheckout, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	userID := authcontext.GetUserID(ctx)
+
+	var result []displayCheckout
+	err := u.tx.WithRetryTx(ctx, func(tx *sql.Tx) error {
+		result = nil
+		now := time.Now()
+		expiresAt := now.Add(u.bookingHold)
+
+		// 1. Lock Cart, every hold must still be active
+		items, err := u.cartRepo.GetForUpdateTx(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return errs.ErrCartEmpty
+		}
+		for _, i := range items {
+			if !now.Before(i.ExpiresAt) {
+				return errs.ErrCartHoldExpired
+			}
+		}
+
+		for _, group := range groupByEvent(items) {
+			eventID := group[0].EventID
+			seatIDs := make([]int64, 0, len(group))
+			for _, i := range group {
+				seatIDs = append(seatIDs, i.SeatID)
+			}
+
+			// 2. Check Limits again, bookings made since adding count too
+			eventData, err := u.eventRepo.GetEventByID(ctx, eventID)
+			if err != nil {
+				return err
+			}
+			if err := u.bookRepo.LockUserEventTx(ctx, tx, userID, eventID); err != nil {
+				return err
+			}
+			held, err := u.bookRepo.CountUserSeatsTx(ctx, tx, userID, eventID)
+			if err != nil {
+				return err
+			}
+			if err := eventData.PurchaseLimit(len(seatIDs), held); err != nil {
+				return err
+			}
+
+			// 3. Seats stay RESERVED, now for the booking
+			seats, err := u.seatRepo.GetSeatsForUpdateTx(ctx, tx, seatIDs)
+			if err != nil {
+				return err
+			}
+			if len(seats) != len(seatIDs) {
+				return errs.ErrCartHoldExpired
+			}
+
+			// 4. Price Seats with the tier active at checkout
+			tiers, err := u.eventRepo.GetPriceTiers(ctx, eventID)
+			if err != nil {
+				return err
+			}
+			fees, err := u.eventRepo.GetFees(ctx, eventID)
+			if err != nil {
+				return err
+			}
+
+			totalAmount := money.Zero(eventData.Currency)
+			bookingItems := make([]booking.BookingItem, 0, len(seats))
+			for _, s := range seats {
+				if s.Status != seat.StatusReserved {
+					return errs.ErrCartHoldExpired
+				}
+				unitPrice, err := event.ZonePrice(tiers, s.Zone, s.Price, now)
+				if err != nil {
+					return err
+				}
+				if totalAmount, err = totalAmount.Add(unitPrice); err != nil {
+					return err
+				}
+				bookingItems = append(bookingItems, booking.BookingItem{SeatID: s.ID, UnitPrice: unitPrice})
+			}
+
+			// 5. Create Booking, priced with the fees and tax of the event
+			price, err := booking.Price(totalAmount, money.Zero(eventData.Currency), len(seats), fees, eventData.TaxPercent)
+			if err != nil {
+				return err
+			}
+			bookingID, err := u.bookRepo.CreateBookingTx(ctx, tx, booking.Booking{
+				UserID:         userID,
+				EventID:        eventID,
+				TotalAmount:    price.Total,
+				DiscountAmount: money.Zero(eventData.Currency),
+				Status:         booking.StatusPending,
+				ExpiresAt:      expiresAt,
+			})
+			if err != nil {
+				return err
+			}
+			if err := u.bookRepo.CreateBookingItemsTx(ctx, tx, bookingID, bookingItems); err != nil {
+				return err
+			}
+			if len(price.Lines) > 0 {
+				if err := u.bookRepo.CreateLinesTx(ctx, tx, bookingID, price.Lines); err != nil {
+					return err
+				}
+			}
+
+			result = append(result, displayCheckout{
+				BookingID:   bookingID,
+				EventID:     eventID,
+				SeatIDs:     seatIDs,
+				TotalAmount: price.Total,
+				Status:      string(booking.StatusPending),
+				ExpiresAt:   expiresAt.In(u.location).Format(time.DateTime),
+			})
+		}
+
+		// 6. Empty Cart
+		return u.cartRepo.ClearTx(ctx, tx, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ExpireHolds : release cart seats past their hold and offer them to the waitlists
+func (u *cartUsecase) ExpireHolds(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	var released map[int64][]int64
+	err := u.tx.WithRetryTx(ctx, func(tx *sql.Tx) error {
+		released = make(map[int64][]int64)
+
+		items, err := u.cartRepo.DeleteExpiredTx(ctx, tx, time.Now(), expireBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+
+		seatIDs := make([]int64, 0, len(items))
+		for _, i := range items {
+			seatIDs = append(seatIDs, i.SeatID)
+			released[i.EventID] = append(released[i.EventID], i.SeatID)
+		}
+		if err := u.seatRepo.UpdateSeatsStatusTx(ctx, tx, seatIDs, string(seat.StatusAvailable)); err != nil {
+			return err
+		}
+
+		for eventID := range released {
+			if err := u.offerer.OfferSeatsTx(ctx, tx, eventID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var expired int
+	for eventID, seatIDs := range released {
+		u.notifier.SeatsChanged(ctx, eventID, seatIDs)
+		expired += len(seatIDs)
+	}
+	if expired > 0 {
+		log.Printf("expired %d cart seats", expired)
+	}
+	return nil
+}
+
+// groupByEvent : split items sorted by event into one slice per event
+func groupByEvent(items []cart.Item) [][]cart.Item {
+	var groups [][]cart.Item
+	for i, item := range items {
+		if i == 0 || item.EventID != items[i-1].EventID {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], item)
+	}
+	return groups
+}
@@ -0,0 +1,321 @@
+package cartusecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/authcontext"
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+	bookingrepo "github.com/codepnw/stdlib-ticket-system/internal/features/booking/repo"
+	bookingusecase "github.com/codepnw/stdlib-ticket-system/internal/features/booking/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/cart"
+	cartrepo "github.com/codepnw/stdlib-ticket-system/internal/features/cart/repo"
+	cartusecase "github.com/codepnw/stdlib-ticket-system/internal/features/cart/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	eventrepo "github.com/codepnw/stdlib-ticket-system/internal/features/event/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/seat"
+	seatrepo "github.com/codepnw/stdlib-ticket-system/internal/features/seat/repo"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var ErrMockDBError = errors.New("db error")
+
+type mockTx struct{}
+
+func (m mockTx) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	return fn(nil)
+}
+
+func (m mockTx) WithRetryTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	return fn(nil)
+}
+
+type mocks struct {
+	cart     *cartrepo.MockCartRepository
+	book     *bookingrepo.MockBookingRepository
+	seat     *seatrepo.MockSeatRepository
+	event    *eventrepo.MockEventRepository
+	offerer  *bookingusecase.MockSeatOfferer
+	notifier *bookingusecase.MockSeatNotifier
+}
+
+func TestAddSeats(t *testing.T) {
+	seatIDs := []int64{11, 12}
+
+	type testCase struct {
+		name        string
+		mockFn      func(m mocks)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "success",
+			mockFn: func(m mocks) {
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10, Currency: "THB", MaxSeatsPerOrder: 4}, nil).Times(1)
+
+				m.cart.EXPECT().GetByUserID(gomock.Any(), int64(1)).Return(nil, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(nil).Times(1)
+
+				m.cart.EXPECT().CountEventSeatsTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(2, nil).Times(1)
+
+				m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(0, nil).Times(1)
+
+				mockSeats := []seat.Seat{
+					{ID: 11, EventID: 10, Price: money.New(10000, "THB"), Status: seat.StatusAvailable},
+					{ID: 12, EventID: 10, Price: money.New(10000, "THB"), Status: seat.StatusAvailable},
+				}
+				m.seat.EXPECT().GetSeatsForUpdateTx(gomock.Any(), gomock.Any(), seatIDs).Return(mockSeats, nil).Times(1)
+
+				m.seat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), seatIDs, string(seat.StatusReserved)).Return(nil).Times(1)
+
+				m.cart.EXPECT().AddItemsTx(gomock.Any(), gomock.Any(), int64(1), int64(10), seatIDs, gomock.Any()).Return(nil).Times(1)
+
+				m.notifier.EXPECT().SeatsChanged(gomock.Any(), int64(10), seatIDs).Times(1)
+
+				m.cart.EXPECT().GetByUserID(gomock.Any(), int64(1)).Return([]cart.Item{
+					{EventID: 10, SeatID: 11, Price: money.New(10000, "THB")},
+					{EventID: 10, SeatID: 12, Price: money.New(10000, "THB")},
+				}, nil).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "fail order limit with seats in cart",
+			mockFn: func(m mocks) {
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10, Currency: "THB", MaxSeatsPerOrder: 4}, nil).Times(1)
+
+				m.cart.EXPECT().GetByUserID(gomock.Any(), int64(1)).Return(nil, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(nil).Times(1)
+
+				m.cart.EXPECT().CountEventSeatsTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(3, nil).Times(1)
+
+				m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(0, nil).Times(1)
+			},
+			expectedErr: errs.ErrPurchaseLimitExceeded,
+		},
+		{
+			name: "fail seat of another event",
+			mockFn: func(m mocks) {
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10, Currency: "THB"}, nil).Times(1)
+
+				m.cart.EXPECT().GetByUserID(gomock.Any(), int64(1)).Return(nil, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(nil).Times(1)
+
+				m.cart.EXPECT().CountEventSeatsTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(0, nil).Times(1)
+
+				m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(0, nil).Times(1)
+
+				mockSeats := []seat.Seat{
+					{ID: 11, EventID: 10, Price: money.New(10000, "THB"), Status: seat.StatusAvailable},
+					{ID: 12, EventID: 99, Price: money.New(10000, "THB"), Status: seat.StatusAvailable},
+				}
+				m.seat.EXPECT().GetSeatsForUpdateTx(gomock.Any(), gomock.Any(), seatIDs).Return(mockSeats, nil).Times(1)
+			},
+			expectedErr: errs.ErrSeatNotInEvent,
+		},
+		{
+			name: "fail cart in another currency",
+			mockFn: func(m mocks) {
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10, Currency: "USD"}, nil).Times(1)
+
+				m.cart.EXPECT().GetByUserID(gomock.Any(), int64(1)).Return([]cart.Item{
+					{EventID: 20, SeatID: 31, Price: money.New(5000, "THB")},
+				}, nil).Times(1)
+			},
+			expectedErr: errs.ErrCurrencyMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, m := setup(t)
+
+			// Mock FN
+			tc.mockFn(m)
+
+			ctx := authcontext.SetUserID(context.Background(), int64(1))
+			result, err := uc.AddSeats(ctx, 10, seatIDs)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, money.New(20000, "THB"), result.TotalAmount)
+			}
+		})
+	}
+}
+
+func TestCheckout(t *testing.T) {
+	future := time.Now().Add(10 * time.Minute)
+
+	type testCase struct {
+		name        string
+		mockFn      func(m mocks)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "success two events",
+			mockFn: func(m mocks) {
+				m.cart.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), int64(1)).Return([]cart.Item{
+					{EventID: 10, SeatID: 11, ExpiresAt: future},
+					{EventID: 10, SeatID: 12, ExpiresAt: future},
+					{EventID: 20, SeatID: 31, ExpiresAt: future},
+				}, nil).Times(1)
+
+				for _, eventID := range []int64{10, 20} {
+					m.event.EXPECT().GetEventByID(gomock.Any(), eventID).Return(event.Event{ID: eventID, Currency: "THB"}, nil).Times(1)
+
+					m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), eventID).Return(nil).Times(1)
+
+					m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), eventID).Return(0, nil).Times(1)
+				}
+
+				m.seat.EXPECT().GetSeatsForUpdateTx(gomock.Any(), gomock.Any(), []int64{11, 12}).Return([]seat.Seat{
+					{ID: 11, EventID: 10, Price: money.New(10000, "THB"), Status: seat.StatusReserved},
+					{ID: 12, EventID: 10, Price: money.New(10000, "THB"), Status: seat.StatusReserved},
+				}, nil).Times(1)
+
+				m.event.EXPECT().GetPriceTiers(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
+
+				m.event.EXPECT().GetFees(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
+
+				m.seat.EXPECT().GetSeatsForUpdateTx(gomock.Any(), gomock.Any(), []int64{31}).Return([]seat.Seat{
+					{ID: 31, EventID: 20, Price: money.New(5000, "THB"), Status: seat.StatusReserved},
+				}, nil).Times(1)
+
+				m.event.EXPECT().GetPriceTiers(gomock.Any(), int64(20)).Return(nil, nil).Times(1)
+
+				m.event.EXPECT().GetFees(gomock.Any(), int64(20)).Return(nil, nil).Times(1)
+
+				m.book.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sql.Tx, input booking.Booking) (string, error) {
+						assert.Equal(t, booking.StatusPending, input.Status)
+						return "mock-uuid-1", nil
+					}).Times(2)
+
+				m.book.EXPECT().CreateBookingItemsTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+				m.cart.EXPECT().ClearTx(gomock.Any(), gomock.Any(), int64(1)).Return(nil).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "fail empty cart",
+			mockFn: func(m mocks) {
+				m.cart.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), int64(1)).Return(nil, nil).Times(1)
+			},
+			expectedErr: errs.ErrCartEmpty,
+		},
+		{
+			name: "fail hold expired",
+			mockFn: func(m mocks) {
+				m.cart.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), int64(1)).Return([]cart.Item{
+					{EventID: 10, SeatID: 11, ExpiresAt: future},
+					{EventID: 20, SeatID: 31, ExpiresAt: time.Now().Add(-time.Minute)},
+				}, nil).Times(1)
+			},
+			expectedErr: errs.ErrCartHoldExpired,
+		},
+		{
+			name: "fail second event rolls back the first",
+			mockFn: func(m mocks) {
+				m.cart.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), int64(1)).Return([]cart.Item{
+					{EventID: 10, SeatID: 11, ExpiresAt: future},
+					{EventID: 20, SeatID: 31, ExpiresAt: future},
+				}, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10, Currency: "THB"}, nil).Times(1)
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(nil).Times(1)
+				m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), int64(10)).Return(0, nil).Times(1)
+				m.seat.EXPECT().GetSeatsForUpdateTx(gomock.Any(), gomock.Any(), []int64{11}).Return([]seat.Seat{
+					{ID: 11, EventID: 10, Price: money.New(10000, "THB"), Status: seat.StatusReserved},
+				}, nil).Times(1)
+
+				m.event.EXPECT().GetPriceTiers(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
+
+				m.event.EXPECT().GetFees(gomock.Any(), int64(10)).Return(nil, nil).Times(1)
+				m.book.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).Return("mock-uuid-1", nil).Times(1)
+				m.book.EXPECT().CreateBookingItemsTx(gomock.Any(), gomock.Any(), "mock-uuid-1", gomock.Any()).Return(nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(20)).Return(event.Event{ID: 20, Currency: "THB", MaxSeatsPerUser: 2}, nil).Times(1)
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), int64(20)).Return(nil).Times(1)
+				m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(1), int64(20)).Return(2, nil).Times(1)
+			},
+			expectedErr: errs.ErrPurchaseLimitExceeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, m := setup(t)
+
+			// Mock FN
+			tc.mockFn(m)
+
+			ctx := authcontext.SetUserID(context.Background(), int64(1))
+			result, err := uc.Checkout(ctx)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				assert.Nil(t, result)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, result, 2)
+				assert.Equal(t, money.New(20000, "THB"), result[0].TotalAmount)
+				assert.Equal(t, []int64{31}, result[1].SeatIDs)
+			}
+		})
+	}
+}
+
+func TestExpireHolds(t *testing.T) {
+	// Setup
+	uc, m := setup(t)
+
+	m.cart.EXPECT().DeleteExpiredTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]cart.Item{
+		{EventID: 10, SeatID: 11},
+		{EventID: 10, SeatID: 12},
+	}, nil).Times(1)
+
+	m.seat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), []int64{11, 12}, string(seat.StatusAvailable)).Return(nil).Times(1)
+
+	m.offerer.EXPECT().OfferSeatsTx(gomock.Any(), gomock.Any(), int64(10)).Return(nil).Times(1)
+
+	m.notifier.EXPECT().SeatsChanged(gomock.Any(), int64(10), []int64{11, 12}).Times(1)
+
+	err := uc.ExpireHolds(context.Background())
+	assert.NoError(t, err)
+}
+
+func setup(t *testing.T) (cartusecase.CartUsecase, mocks) {
+	ctrl := gomock.NewController(t)
+
+	loc, _ := time.LoadLocation("Asia/Bangkok")
+
+	m := mocks{
+		cart:     cartrepo.NewMockCartRepository(ctrl),
+		book:     bookingrepo.NewMockBookingRepository(ctrl),
+		seat:     seatrepo.NewMockSeatRepository(ctrl),
+		event:    eventrepo.NewMockEventRepository(ctrl),
+		offerer:  bookingusecase.NewMockSeatOfferer(ctrl),
+		notifier: bookingusecase.NewMockSeatNotifier(ctrl),
+	}
+	uc := cartusecase.NewCartUsecase(loc, 15*time.Minute, 15*time.Minute, mockTx{}, m.cart, m.book, m.seat, m.event, m.offerer, m.notifier)
+
+	return uc, m
+}
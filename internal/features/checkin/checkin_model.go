@@ -0,0 +1,39 @@
+package checkin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+)
+
+// Item : an active booking_items row as seen at the gate
+type Item struct {
+	BookingID     string                `db:"booking_id"`
+	SeatID        int64                 `db:"seat_id"`
+	SeatNumber    string                `db:"seat_number"`
+	BookingStatus booking.BookingStatus `db:"status"`
+	CheckedInAt   *time.Time            `db:"checked_in_at"`
+	CheckedInGate string                `db:"checked_in_gate"`
+}
+
+type Stats struct {
+	EventID   int64 `db:"event_id"`
+	Sold      int   `db:"sold"`
+	CheckedIn int   `db:"checked_in"`
+}
+
+// AlreadyCheckedInError : a repeat scan, carries the first admission for door staff
+type AlreadyCheckedInError struct {
+	At   time.Time
+	Gate string
+}
+
+func (e *AlreadyCheckedInError) Error() string {
+	return fmt.Sprintf("%s at %s (gate %s)", errs.ErrAlreadyCheckedIn, e.At.Format(time.DateTime), e.Gate)
+}
+
+func (e *AlreadyCheckedInError) Unwrap() error {
+	return errs.ErrAlreadyCheckedIn
+}
@@ -0,0 +1,8 @@
+package checkinhandler
+
+type CheckInReq struct {
+	BookingID  string `json:"booking_id" validate:"required"`
+	SeatID     int64  `json:"seat_id" validate:"required_without=SeatNumber"`
+	SeatNumber string `json:"seat_number" validate:"required_without=SeatID"`
+	Gate       string `json:"gate" validate:"required,max=50"`
+}
@@ -0,0 +1,80 @@
+package checkinhandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	checkinusecase "github.com/codepnw/stdlib-ticket-system/internal/features/checkin/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/helper"
+	"github.com/codepnw/stdlib-ticket-system/pkg/utils"
+)
+
+type checkinHandler struct {
+	uc checkinusecase.CheckinUsecase
+}
+
+func NewCheckinHandler(uc checkinusecase.CheckinUsecase) *checkinHandler {
+	return &checkinHandler{uc: uc}
+}
+
+func (h *checkinHandler) CheckIn(w http.ResponseWriter, r *http.Request) {
+	eventID, err := helper.ParseInt64(r.PathValue("event_id"))
+	if err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req CheckInReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := utils.Validate(&req); err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.uc.CheckIn(r.Context(), eventID, checkinusecase.CheckInInput{
+		BookingID:  req.BookingID,
+		SeatID:     req.SeatID,
+		SeatNumber: req.SeatNumber,
+		Gate:       req.Gate,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrTicketNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, errs.ErrAlreadyCheckedIn), errors.Is(err, errs.ErrBookingNotPaid):
+			helper.ErrorResponse(w, http.StatusConflict, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	helper.SuccessResponse(w, http.StatusOK, "checked in", data)
+}
+
+func (h *checkinHandler) GetStats(w http.ResponseWriter, r *http.Request) {
+	eventID, err := helper.ParseInt64(r.PathValue("event_id"))
+	if err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.uc.GetStats(r.Context(), eventID)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrEventNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	helper.SuccessResponse(w, http.StatusOK, "", data)
+}
@@ -0,0 +1,108 @@
+package checkinrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/checkin"
+)
+
+//go:generate mockgen -source=checkin_repo.go -destination=checkin_repo_mock.go -package=checkinrepo
+type CheckinRepository interface {
+	GetStats(ctx context.Context, eventID int64) (checkin.Stats, error)
+
+	// Transaction
+	// GetItemForUpdateTx : find by seatID, or by seatNumber when seatID is 0
+	GetItemForUpdateTx(ctx context.Context, tx *sql.Tx, eventID int64, bookingID string, seatID int64, seatNumber string) (checkin.Item, error)
+	CheckInTx(ctx context.Context, tx *sql.Tx, bookingID string, seatID int64, gate string, staffID int64, at time.Time) error
+}
+
+type checkinRepository struct {
+	db *sql.DB
+}
+
+func NewCheckinRepository(db *sql.DB) CheckinRepository {
+	return &checkinRepository{db: db}
+}
+
+func (r *checkinRepository) GetStats(ctx context.Context, eventID int64) (checkin.Stats, error) {
+	query := `
+		SELECT
+			COUNT(*),
+			COUNT(*) FILTER (WHERE bi.checked_in_at IS NOT NULL)
+		FROM booking_items bi
+		JOIN bookings b ON b.id = bi.booking_id
+		WHERE b.event_id = $1 AND b.status = $2 AND bi.released_at IS NULL
+	`
+	stats := checkin.Stats{EventID: eventID}
+	err := r.db.QueryRowContext(ctx, query, eventID, booking.StatusPaid).Scan(
+		&stats.Sold,
+		&stats.CheckedIn,
+	)
+	if err != nil {
+		return checkin.Stats{}, err
+	}
+	return stats, nil
+}
+
+func (r *checkinRepository) GetItemForUpdateTx(ctx context.Context, tx *sql.Tx, eventID int64, bookingID string, seatID int64, seatNumber string) (checkin.Item, error) {
+	query := `
+		SELECT
+			bi.booking_id, bi.seat_id, s.seat_number, b.status,
+			bi.checked_in_at, COALESCE(bi.checked_in_gate, '')
+		FROM booking_items bi
+		JOIN bookings b ON b.id = bi.booking_id
+		JOIN seats s ON s.id = bi.seat_id
+		WHERE b.event_id = $1
+			AND bi.booking_id = $2
+			AND bi.released_at IS NULL
+			AND (($3::BIGINT > 0 AND bi.seat_id = $3) OR ($3::BIGINT = 0 AND s.seat_number = $4))
+		FOR UPDATE OF bi
+	`
+	var i checkin.Item
+	err := tx.QueryRowContext(ctx, query, eventID, bookingID, seatID, seatNumber).Scan(
+		&i.BookingID,
+		&i.SeatID,
+		&i.SeatNumber,
+		&i.BookingStatus,
+		&i.CheckedInAt,
+		&i.CheckedInGate,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return checkin.Item{}, errs.ErrTicketNotFound
+		}
+		return checkin.Item{}, err
+	}
+	return i, nil
+}
+
+func (r *checkinRepository) CheckInTx(ctx context.Context, tx *sql.Tx, bookingID string, seatID int64, gate string, staffID int64, at time.Time) error {
+	query := `
+		WITH delisted AS (
+			UPDATE resale_listings SET status = 'CANCELLED'
+			WHERE seller_booking_id = $4 AND seat_id = $5 AND status = 'LISTED'
+		)
+		UPDATE booking_items
+		SET checked_in_at = $1, checked_in_gate = $2, checked_in_by = $3
+		WHERE booking_id = $4 AND seat_id = $5
+			AND released_at IS NULL AND checked_in_at IS NULL
+	`
+	res, err := tx.ExecContext(ctx, query, at, gate, staffID, bookingID, seatID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return errs.ErrAlreadyCheckedIn
+	}
+	return nil
+}
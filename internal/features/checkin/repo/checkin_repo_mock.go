@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: checkin_repo.go
+
+// Package checkinrepo is a generated GoMock package.
+package checkinrepo
+
+import (
+	context "context"
+	sql "database/sql"
+	reflect "reflect"
+	time "time"
+
+	checkin "github.com/codepnw/stdlib-ticket-system/internal/features/checkin"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCheckinRepository is a mock of CheckinRepository interface.
+type MockCheckinRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCheckinRepositoryMockRecorder
+}
+
+// MockCheckinRepositoryMockRecorder is the mock recorder for MockCheckinRepository.
+type MockCheckinRepositoryMockRecorder struct {
+	mock *MockCheckinRepository
+}
+
+// NewMockCheckinRepository creates a new mock instance.
+func NewMockCheckinRepository(ctrl *gomock.Controller) *MockCheckinRepository {
+	mock := &MockCheckinRepository{ctrl: ctrl}
+	mock.recorder = &MockCheckinRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCheckinRepository) EXPECT() *MockCheckinRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CheckInTx mocks base method.
+func (m *MockCheckinRepository) CheckInTx(ctx context.Context, tx *sql.Tx, bookingID string, seatID int64, gate string, staffID int64, at time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckInTx", ctx, tx, bookingID, seatID, gate, staffID, at)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckInTx indicates an expected call of CheckInTx.
+func (mr *MockCheckinRepositoryMockRecorder) CheckInTx(ctx, tx, bookingID, seatID, gate, staffID, at interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckInTx", reflect.TypeOf((*MockCheckinRepository)(nil).CheckInTx), ctx, tx, bookingID, seatID, gate, staffID, at)
+}
+
+// GetItemForUpdateTx mocks base method.
+func (m *MockCheckinRepository) GetItemForUpdateTx(ctx context.Context, tx *sql.Tx, eventID int64, bookingID string, seatID int64, seatNumber string) (checkin.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemForUpdateTx", ctx, tx, eventID, bookingID, seatID, seatNumber)
+	ret0, _ := ret[0].(checkin.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemForUpdateTx indicates an expected call of GetItemForUpdateTx.
+func (mr *MockCheckinRepositoryMockRecorder) GetItemForUpdateTx(ctx, tx, eventID, bookingID, seatID, seatNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemForUpdateTx", reflect.TypeOf((*MockCheckinRepository)(nil).GetItemForUpdateTx), ctx, tx, eventID, bookingID, seatID, seatNumber)
+}
+
+// GetStats mocks base method.
+func (m *MockCheckinRepository) GetStats(ctx context.Context, eventID int64) (checkin.Stats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStats", ctx, eventID)
+	ret0, _ := ret[0].(checkin.Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStats indicates an expected call of GetStats.
+func (mr *MockCheckinRepositoryMockRecorder) GetStats(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStats", reflect.TypeOf((*MockCheckinRepository)(nil).GetStats), ctx, eventID)
+}
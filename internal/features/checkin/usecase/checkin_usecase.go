@@ -0,0 +1,123 @@
+package checkinusecase
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/authcontext"
+	"github.com/codepnw/stdlib-ticket-system/internal/config"
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/checkin"
+	checkinrepo "github.com/codepnw/stdlib-ticket-system/internal/features/checkin/repo"
+	eventrepo "github.com/codepnw/stdlib-ticket-system/internal/features/event/repo"
+	"github.com/codepnw/stdlib-ticket-system/pkg/database"
+)
+
+type CheckinUsecase interface {
+	CheckIn(ctx context.Context, eventID int64, input CheckInInput) (displayCheckIn, error)
+	GetStats(ctx context.Context, eventID int64) (displayStats, error)
+}
+
+type checkinUsecase struct {
+	location    *time.Location
+	tx          database.TxManager
+	checkinRepo checkinrepo.CheckinRepository
+	eventRepo   eventrepo.EventRepository
+}
+
+func NewCheckinUsecase(location *time.Location, tx database.TxManager, checkinRepo checkinrepo.CheckinRepository, eventRepo eventrepo.EventRepository) CheckinUsecase {
+	return &checkinUsecase{
+		location:    location,
+		tx:          tx,
+		checkinRepo: checkinRepo,
+		eventRepo:   eventRepo,
+	}
+}
+
+// CheckInInput : SeatID wins over SeatNumber when both are given
+type CheckInInput struct {
+	BookingID  string
+	SeatID     int64
+	SeatNumber string
+	Gate       string
+}
+
+type displayCheckIn struct {
+	BookingID   string `json:"booking_id"`
+	SeatID      int64  `json:"seat_id"`
+	SeatNumber  string `json:"seat_number"`
+	Gate        string `json:"gate"`
+	CheckedInAt string `json:"checked_in_at"`
+}
+
+func (u *checkinUsecase) CheckIn(ctx context.Context, eventID int64, input CheckInInput) (displayCheckIn, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	var item checkin.Item
+	now := time.Now()
+
+	err := u.tx.WithTx(ctx, func(tx *sql.Tx) error {
+		// 1. Lock Ticket
+		var err error
+		item, err = u.checkinRepo.GetItemForUpdateTx(ctx, tx, eventID, input.BookingID, input.SeatID, input.SeatNumber)
+		if err != nil {
+			return err
+		}
+		// 2. Only PAID bookings are admitted
+		if item.BookingStatus != booking.StatusPaid {
+			return errs.ErrBookingNotPaid
+		}
+		// 3. Double Entry
+		if item.CheckedInAt != nil {
+			return &checkin.AlreadyCheckedInError{
+				At:   item.CheckedInAt.In(u.location),
+				Gate: item.CheckedInGate,
+			}
+		}
+		// 4. Check In
+		return u.checkinRepo.CheckInTx(ctx, tx, item.BookingID, item.SeatID, input.Gate, authcontext.GetUserID(ctx), now)
+	})
+	if err != nil {
+		return displayCheckIn{}, err
+	}
+
+	return displayCheckIn{
+		BookingID:   item.BookingID,
+		SeatID:      item.SeatID,
+		SeatNumber:  item.SeatNumber,
+		Gate:        input.Gate,
+		CheckedInAt: now.In(u.location).Format(time.DateTime),
+	}, nil
+}
+
+type displayStats struct {
+	EventID   int64 `json:"event_id"`
+	Sold      int   `json:"sold"`
+	CheckedIn int   `json:"checked_in"`
+	Remaining int   `json:"remaining"`
+}
+
+func (u *checkinUsecase) GetStats(ctx context.Context, eventID int64) (displayStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	// 1. Check Event
+	if _, err := u.eventRepo.GetEventByID(ctx, eventID); err != nil {
+		return displayStats{}, err
+	}
+	// 2. Count
+	stats, err := u.checkinRepo.GetStats(ctx, eventID)
+	if err != nil {
+		return displayStats{}, err
+	}
+
+	return displayStats{
+		EventID:   stats.EventID,
+		Sold:      stats.Sold,
+		CheckedIn: stats.CheckedIn,
+		Remaining: stats.Sold - stats.CheckedIn,
+	}, nil
+}
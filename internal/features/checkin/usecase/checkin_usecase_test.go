@@ -0,0 +1,187 @@
+package checkinusecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/authcontext"
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/checkin"
+	checkinrepo "github.com/codepnw/stdlib-ticket-system/internal/features/checkin/repo"
+	checkinusecase "github.com/codepnw/stdlib-ticket-system/internal/features/checkin/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	eventrepo "github.com/codepnw/stdlib-ticket-system/internal/features/event/repo"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var ErrMockDBError = errors.New("db error")
+
+type mockTx struct{}
+
+func (m mockTx) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	return fn(nil)
+}
+
+func (m mockTx) WithRetryTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	return fn(nil)
+}
+
+type mocks struct {
+	checkin *checkinrepo.MockCheckinRepository
+	event   *eventrepo.MockEventRepository
+}
+
+func TestCheckIn(t *testing.T) {
+	input := checkinusecase.CheckInInput{BookingID: "mock-uuid-1", SeatNumber: "A1", Gate: "north"}
+
+	type testCase struct {
+		name        string
+		mockFn      func(m mocks)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "success by seat number",
+			mockFn: func(m mocks) {
+				mockItem := checkin.Item{BookingID: input.BookingID, SeatID: 11, SeatNumber: "A1", BookingStatus: booking.StatusPaid}
+				m.checkin.EXPECT().GetItemForUpdateTx(gomock.Any(), gomock.Any(), int64(10), input.BookingID, int64(0), "A1").Return(mockItem, nil).Times(1)
+
+				m.checkin.EXPECT().CheckInTx(gomock.Any(), gomock.Any(), input.BookingID, int64(11), "north", int64(99), gomock.Any()).Return(nil).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "fail ticket not found",
+			mockFn: func(m mocks) {
+				m.checkin.EXPECT().GetItemForUpdateTx(gomock.Any(), gomock.Any(), int64(10), input.BookingID, int64(0), "A1").Return(checkin.Item{}, errs.ErrTicketNotFound).Times(1)
+			},
+			expectedErr: errs.ErrTicketNotFound,
+		},
+		{
+			name: "fail booking not paid",
+			mockFn: func(m mocks) {
+				mockItem := checkin.Item{BookingID: input.BookingID, SeatID: 11, BookingStatus: booking.StatusPending}
+				m.checkin.EXPECT().GetItemForUpdateTx(gomock.Any(), gomock.Any(), int64(10), input.BookingID, int64(0), "A1").Return(mockItem, nil).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotPaid,
+		},
+		{
+			name: "fail already checked in",
+			mockFn: func(m mocks) {
+				first := time.Now().Add(-time.Hour)
+				mockItem := checkin.Item{BookingID: input.BookingID, SeatID: 11, BookingStatus: booking.StatusPaid, CheckedInAt: &first, CheckedInGate: "south"}
+				m.checkin.EXPECT().GetItemForUpdateTx(gomock.Any(), gomock.Any(), int64(10), input.BookingID, int64(0), "A1").Return(mockItem, nil).Times(1)
+			},
+			expectedErr: errs.ErrAlreadyCheckedIn,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, m := setup(t)
+
+			// Mock FN
+			tc.mockFn(m)
+
+			ctx := authcontext.SetUserID(context.Background(), int64(99))
+			_, err := uc.CheckIn(ctx, 10, input)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCheckInReportsFirstAdmission(t *testing.T) {
+	uc, m := setup(t)
+
+	first := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockItem := checkin.Item{BookingID: "mock-uuid-1", SeatID: 11, BookingStatus: booking.StatusPaid, CheckedInAt: &first, CheckedInGate: "south"}
+	m.checkin.EXPECT().GetItemForUpdateTx(gomock.Any(), gomock.Any(), int64(10), "mock-uuid-1", int64(11), "").Return(mockItem, nil).Times(1)
+
+	_, err := uc.CheckIn(context.Background(), 10, checkinusecase.CheckInInput{BookingID: "mock-uuid-1", SeatID: 11, Gate: "north"})
+
+	var already *checkin.AlreadyCheckedInError
+	assert.ErrorAs(t, err, &already)
+	assert.True(t, first.Equal(already.At))
+	assert.Equal(t, "south", already.Gate)
+}
+
+func TestGetStats(t *testing.T) {
+	type testCase struct {
+		name        string
+		mockFn      func(m mocks)
+		expected    int
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "success",
+			mockFn: func(m mocks) {
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10}, nil).Times(1)
+
+				m.checkin.EXPECT().GetStats(gomock.Any(), int64(10)).Return(checkin.Stats{EventID: 10, Sold: 50, CheckedIn: 20}, nil).Times(1)
+			},
+			expected:    30,
+			expectedErr: nil,
+		},
+		{
+			name: "fail event not found",
+			mockFn: func(m mocks) {
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{}, errs.ErrEventNotFound).Times(1)
+			},
+			expectedErr: errs.ErrEventNotFound,
+		},
+		{
+			name: "fail count",
+			mockFn: func(m mocks) {
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10}, nil).Times(1)
+
+				m.checkin.EXPECT().GetStats(gomock.Any(), int64(10)).Return(checkin.Stats{}, ErrMockDBError).Times(1)
+			},
+			expectedErr: ErrMockDBError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, m := setup(t)
+
+			// Mock FN
+			tc.mockFn(m)
+
+			result, err := uc.GetStats(context.Background(), 10)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, result.Remaining)
+			}
+		})
+	}
+}
+
+func setup(t *testing.T) (checkinusecase.CheckinUsecase, mocks) {
+	ctrl := gomock.NewController(t)
+
+	m := mocks{
+		checkin: checkinrepo.NewMockCheckinRepository(ctrl),
+		event:   eventrepo.NewMockEventRepository(ctrl),
+	}
+	uc := checkinusecase.NewCheckinUsecase(time.UTC, mockTx{}, m.checkin, m.event)
+
+	return uc, m
+}
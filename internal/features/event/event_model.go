@@ -2,26 +2,275 @@ package event
 
 import (
 	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
 )
 
 type Event struct {
-	ID        int64     `json:"id" db:"id"`
-	Name      string    `json:"name" db:"name"`
-	EventDate time.Time `json:"event_date" db:"event_date"`
-	IsActive  bool      `json:"is_active" db:"is_active"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+	ID               int64         `json:"id" db:"id"`
+	Name             string        `json:"name" db:"name"`
+	EventDate        time.Time     `json:"event_date" db:"event_date"`
+	IsActive         bool          `json:"is_active" db:"is_active"`
+	MaxSeatsPerOrder int           `json:"max_seats_per_order" db:"max_seats_per_order"`
+	MaxSeatsPerUser  int           `json:"max_seats_per_user" db:"max_seats_per_user"`
+	ResaleCapPercent int           `json:"resale_cap_percent" db:"resale_cap_percent"`
+	Currency         string        `json:"currency" db:"currency"`
+	TaxPercent       float64       `json:"tax_percent" db:"tax_percent"`
+	RefundPolicy     *RefundPolicy `json:"refund_policy,omitempty" db:"-"`
+	PriceTiers       []PriceTier   `json:"price_tiers,omitempty" db:"-"`
+	Fees             []Fee         `json:"fees,omitempty" db:"-"`
+	TicketTypes      []TicketType  `json:"ticket_types,omitempty" db:"-"`
+	CreatedAt        time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+// RefundPolicy : full refund until FullRefundDays before the event,
+// PartialRefundPercent until PartialRefundDays before, nothing after that
+type RefundPolicy struct {
+	FullRefundDays       int `json:"full_refund_days" db:"full_refund_days" validate:"gtefield=PartialRefundDays"`
+	PartialRefundDays    int `json:"partial_refund_days" db:"partial_refund_days" validate:"gte=0"`
+	PartialRefundPercent int `json:"partial_refund_percent" db:"partial_refund_percent" validate:"gte=0,lte=100"`
+}
+
+// PurchaseLimit : seats a user may still take in one order, 0 limits mean no cap
+func (e Event) PurchaseLimit(requested, alreadyHeld int) error {
+	if e.MaxSeatsPerOrder > 0 && requested > e.MaxSeatsPerOrder {
+		return errs.ErrPurchaseLimitExceeded
+	}
+	if e.MaxSeatsPerUser > 0 && alreadyHeld+requested > e.MaxSeatsPerUser {
+		return errs.ErrPurchaseLimitExceeded
+	}
+	return nil
+}
+
+// ResalePriceCap : highest resale price for a seat of faceValue, 0 cap means resale is off
+func (e Event) ResalePriceCap(faceValue money.Money) (money.Money, error) {
+	if e.ResaleCapPercent <= 0 {
+		return money.Money{}, errs.ErrResaleNotAllowed
+	}
+	return faceValue.PercentDown(float64(e.ResaleCapPercent)), nil
+}
+
+// RefundPercent : how much of the price comes back when cancelling at now
+func (p RefundPolicy) RefundPercent(eventDate, now time.Time) int {
+	left := eventDate.Sub(now)
+	day := 24 * time.Hour
+
+	switch {
+	case left >= time.Duration(p.FullRefundDays)*day:
+		return 100
+	case left >= time.Duration(p.PartialRefundDays)*day:
+		return p.PartialRefundPercent
+	default:
+		return 0
+	}
+}
+
+// PriceTier : price of a zone from StartsAt until EndsAt, a nil bound leaves that side open
+type PriceTier struct {
+	ID       int64       `json:"id" db:"id"`
+	EventID  int64       `json:"event_id" db:"event_id"`
+	Zone     string      `json:"zone" db:"zone"`
+	Name     string      `json:"name" db:"name"`
+	Price    money.Money `json:"price" db:"price"`
+	StartsAt *time.Time  `json:"starts_at" db:"starts_at"`
+	EndsAt   *time.Time  `json:"ends_at" db:"ends_at"`
+}
+
+// ActiveAt : at falls inside [StartsAt, EndsAt)
+func (t PriceTier) ActiveAt(at time.Time) bool {
+	if t.StartsAt != nil && at.Before(*t.StartsAt) {
+		return false
+	}
+	if t.EndsAt != nil && !at.Before(*t.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// ZonePrice : price of the zone's tier active at `at`, zones without tiers keep basePrice.
+// When windows overlap the tier that started last wins, so a sale can sit on top of a standard tier.
+func ZonePrice(tiers []PriceTier, zone string, basePrice money.Money, at time.Time) (money.Money, error) {
+	var scheduled bool
+	var active *PriceTier
+	for i, t := range tiers {
+		if t.Zone != zone {
+			continue
+		}
+		scheduled = true
+		if !t.ActiveAt(at) {
+			continue
+		}
+		if active == nil || startsAfter(t, *active) {
+			active = &tiers[i]
+		}
+	}
+
+	if !scheduled {
+		return basePrice, nil
+	}
+	if active == nil {
+		return money.Money{}, errs.ErrZoneNotOnSale
+	}
+	return active.Price, nil
+}
+
+func startsAfter(a, b PriceTier) bool {
+	if a.StartsAt == nil {
+		return false
+	}
+	return b.StartsAt == nil || a.StartsAt.After(*b.StartsAt)
+}
+
+type FeeType string
+
+const (
+	FeePerTicket FeeType = "PER_TICKET"
+	FeePerOrder  FeeType = "PER_ORDER"
+	FeePercent   FeeType = "PERCENT"
+)
+
+// Fee : charged on every booking of the event, e.g. a service fee per ticket or a facility fee per order.
+// Flat fees set Amount, PERCENT fees set Percent.
+type Fee struct {
+	ID      int64       `json:"id" db:"id"`
+	EventID int64       `json:"event_id" db:"event_id"`
+	Name    string      `json:"name" db:"name"`
+	Type    FeeType     `json:"fee_type" db:"fee_type"`
+	Amount  money.Money `json:"amount,omitzero" db:"amount"`
+	Percent float64     `json:"percent,omitempty" db:"percent"`
+}
+
+// Charge : the fee on an order of tickets seats worth net, percentages round to the nearest minor unit
+func (f Fee) Charge(tickets int, net money.Money) (money.Money, error) {
+	if f.Type == FeePercent {
+		return net.Percent(f.Percent), nil
+	}
+	if f.Amount.Currency != net.Currency {
+		return money.Money{}, errs.ErrCurrencyMismatch
+	}
+	if f.Type == FeePerTicket {
+		return money.New(f.Amount.Amount*int64(tickets), f.Amount.Currency), nil
+	}
+	return f.Amount, nil
+}
+
+// TicketType : e.g. adult, child or concession, priced per zone that offers it.
+// Quota caps its active tickets across the event, 0 means no limit.
+type TicketType struct {
+	ID          int64         `json:"id" db:"id"`
+	EventID     int64         `json:"event_id" db:"event_id"`
+	Name        string        `json:"name" db:"name"`
+	Eligibility string        `json:"eligibility,omitempty" db:"eligibility"`
+	Quota       int           `json:"quota" db:"quota"`
+	Prices      []TicketPrice `json:"prices" db:"-"`
+}
+
+// CheckQuota : requested more tickets fit the quota next to the sold ones
+func (t TicketType) CheckQuota(sold, requested int) error {
+	if t.Quota > 0 && sold+requested > t.Quota {
+		return errs.ErrTicketTypeSoldOut
+	}
+	return nil
+}
+
+type TicketPrice struct {
+	Zone  string      `json:"zone" db:"zone"`
+	Price money.Money `json:"price" db:"price"`
 }
 
+// PriceIn : the price of the type in zone, false when the zone does not offer it
+func (t TicketType) PriceIn(zone string) (money.Money, bool) {
+	for _, p := range t.Prices {
+		if p.Zone == zone {
+			return p.Price, true
+		}
+	}
+	return money.Money{}, false
+}
+
+// SeatZoneReq : a price zone, either one row of SeatsPerRow seats or a full layout of Sections, not both.
+// Tiers schedule the price over time, Price stays the listed seat price.
 type SeatZoneReq struct {
-	ZoneName    string  `json:"zone_name"`
-	SeatsPerRow int     `json:"seats_per_row"`
-	Price       float64 `json:"price"`
+	ZoneName    string           `json:"zone_name"`
+	SeatsPerRow int              `json:"seats_per_row" validate:"gte=0,excluded_with=Sections"`
+	Price       money.Money      `json:"price"`
+	Sections    []SeatSectionReq `json:"sections" validate:"dive"`
+	Tiers       []PriceTierReq   `json:"tiers" validate:"dive"`
+}
+
+// PriceTierReq : e.g. early bird until the on-sale date, standard after it, door from the event day
+type PriceTierReq struct {
+	Name     string      `json:"name" validate:"required,max=50"`
+	Price    money.Money `json:"price"`
+	StartsAt *time.Time  `json:"starts_at"`
+	EndsAt   *time.Time  `json:"ends_at"`
+}
+
+// Valid : the price is not negative and the window is not empty
+func (t PriceTierReq) Valid() bool {
+	if t.Price.Amount < 0 {
+		return false
+	}
+	return t.StartsAt == nil || t.EndsAt == nil || t.StartsAt.Before(*t.EndsAt)
+}
+
+// FeeReq : flat fees take Amount, PERCENT fees take Percent
+type FeeReq struct {
+	Name    string      `json:"name" validate:"required,max=50"`
+	Type    FeeType     `json:"fee_type" validate:"required,oneof=PER_TICKET PER_ORDER PERCENT"`
+	Amount  money.Money `json:"amount"`
+	Percent float64     `json:"percent"`
+}
+
+// Valid : exactly one of Amount and Percent is set, and it is positive
+func (f FeeReq) Valid() bool {
+	if f.Type == FeePercent {
+		return f.Percent > 0 && f.Percent <= 100 && f.Amount.IsZero()
+	}
+	return f.Amount.IsPositive() && f.Percent == 0
+}
+
+// TicketTypeReq : Prices lists the zones offering the type, Quota 0 means no limit
+type TicketTypeReq struct {
+	Name        string           `json:"name" validate:"required,max=50"`
+	Eligibility string           `json:"eligibility" validate:"max=255"`
+	Quota       int              `json:"quota" validate:"gte=0"`
+	Prices      []TicketPriceReq `json:"prices" validate:"required,min=1,dive"`
+}
+
+type TicketPriceReq struct {
+	Zone  string      `json:"zone" validate:"required,max=50"`
+	Price money.Money `json:"price"`
+}
+
+type SeatSectionReq struct {
+	Name string       `json:"name" validate:"required,max=10"`
+	Rows []SeatRowReq `json:"rows" validate:"required,min=1,dive"`
+}
+
+// SeatRowReq : seats run left to right from X, the stage is at the lowest Y.
+// AisleAfter lists the seat positions followed by an aisle.
+type SeatRowReq struct {
+	Label      string `json:"label" validate:"required,max=5"`
+	Seats      int    `json:"seats" validate:"gt=0"`
+	X          int    `json:"x" validate:"gte=0"`
+	Y          int    `json:"y" validate:"gte=0"`
+	AisleAfter []int  `json:"aisle_after" validate:"dive,gt=0"`
 }
 
 type CreateEventReq struct {
-	Name      string        `json:"name"`
-	EventDate time.Time     `json:"event_date"`
-	IsActive  bool          `json:"is_active"`
-	Zones     []SeatZoneReq `json:"zones"`
+	Name             string          `json:"name"`
+	EventDate        time.Time       `json:"event_date"`
+	IsActive         bool            `json:"is_active"`
+	MaxSeatsPerOrder int             `json:"max_seats_per_order" validate:"gte=0"`
+	MaxSeatsPerUser  int             `json:"max_seats_per_user" validate:"gte=0"`
+	ResaleCapPercent int             `json:"resale_cap_percent" validate:"gte=0"`
+	Currency         string          `json:"currency" validate:"required,iso4217"`
+	TaxPercent       float64         `json:"tax_percent" validate:"gte=0,lte=100"`
+	Zones            []SeatZoneReq   `json:"zones" validate:"dive"`
+	Fees             []FeeReq        `json:"fees" validate:"dive"`
+	TicketTypes      []TicketTypeReq `json:"ticket_types" validate:"dive"`
+	RefundPolicy     *RefundPolicy   `json:"refund_policy"`
 }
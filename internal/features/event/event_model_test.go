@@ -0,0 +1,114 @@
+package event_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZonePrice(t *testing.T) {
+	onSale := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	eventDay := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	flashSale := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	flashEnd := flashSale.Add(24 * time.Hour)
+
+	tiers := []event.PriceTier{
+		{Zone: "VIP", Name: "early bird", Price: money.New(8000, "THB"), EndsAt: &onSale},
+		{Zone: "VIP", Name: "standard", Price: money.New(10000, "THB"), StartsAt: &onSale, EndsAt: &eventDay},
+		{Zone: "VIP", Name: "flash sale", Price: money.New(7000, "THB"), StartsAt: &flashSale, EndsAt: &flashEnd},
+		{Zone: "A", Name: "presale", Price: money.New(4000, "THB"), EndsAt: &onSale},
+	}
+
+	type testCase struct {
+		name        string
+		zone        string
+		at          time.Time
+		expected    money.Money
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{name: "early bird", zone: "VIP", at: onSale.Add(-time.Hour), expected: money.New(8000, "THB")},
+		{name: "standard from the start", zone: "VIP", at: onSale, expected: money.New(10000, "THB")},
+		{name: "overlapping tier started last", zone: "VIP", at: flashSale.Add(time.Hour), expected: money.New(7000, "THB")},
+		{name: "zone without tiers keeps base price", zone: "B", at: onSale, expected: money.New(5000, "THB")},
+		{name: "fail no active tier", zone: "VIP", at: eventDay, expectedErr: errs.ErrZoneNotOnSale},
+		{name: "fail presale over", zone: "A", at: onSale, expectedErr: errs.ErrZoneNotOnSale},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			price, err := event.ZonePrice(tiers, tc.zone, money.New(5000, "THB"), tc.at)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, price)
+			}
+		})
+	}
+}
+
+func TestFeeCharge(t *testing.T) {
+	net := money.New(20000, "THB")
+
+	type testCase struct {
+		name        string
+		fee         event.Fee
+		expected    money.Money
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{name: "per ticket", fee: event.Fee{Type: event.FeePerTicket, Amount: money.New(2000, "THB")}, expected: money.New(6000, "THB")},
+		{name: "per order", fee: event.Fee{Type: event.FeePerOrder, Amount: money.New(1000, "THB")}, expected: money.New(1000, "THB")},
+		{name: "percent of net", fee: event.Fee{Type: event.FeePercent, Percent: 2.5}, expected: money.New(500, "THB")},
+		{name: "fail other currency", fee: event.Fee{Type: event.FeePerOrder, Amount: money.New(1000, "USD")}, expectedErr: errs.ErrCurrencyMismatch},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			charge, err := tc.fee.Charge(3, net)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, charge)
+			}
+		})
+	}
+}
+
+func TestTicketTypeCheckQuota(t *testing.T) {
+	type testCase struct {
+		name        string
+		quota       int
+		sold        int
+		requested   int
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{name: "no limit", quota: 0, sold: 500, requested: 2},
+		{name: "fills the quota", quota: 20, sold: 18, requested: 2},
+		{name: "fail over the quota", quota: 20, sold: 19, requested: 2, expectedErr: errs.ErrTicketTypeSoldOut},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := event.TicketType{Name: "concession", Quota: tc.quota}.CheckQuota(tc.sold, tc.requested)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
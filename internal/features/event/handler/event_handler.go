@@ -33,7 +33,13 @@ func (h *eventHandler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.uc.CreateEvent(r.Context(), req); err != nil {
-		helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		switch {
+		case errors.Is(err, errs.ErrInvalidPriceTier), errors.Is(err, errs.ErrInvalidFee), errors.Is(err, errs.ErrInvalidTicketType), errors.Is(err, errs.ErrCurrencyMismatch), errors.Is(err, errs.ErrInvalidMoney):
+			helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
 
@@ -7,12 +7,23 @@ import (
 
 	"github.com/codepnw/stdlib-ticket-system/internal/errs"
 	"github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	"github.com/lib/pq"
 )
 
+//go:generate mockgen -source=event_repo.go -destination=event_repo_mock.go -package=eventrepo
 type EventRepository interface {
 	CreateEventTx(ctx context.Context, tx *sql.Tx, input event.Event) (int64, error)
+	CreateRefundPolicyTx(ctx context.Context, tx *sql.Tx, eventID int64, policy event.RefundPolicy) error
 	GetEventByID(ctx context.Context, eventID int64) (event.Event, error)
 	GetAllEvents(ctx context.Context) ([]event.Event, error)
+	GetRefundPolicy(ctx context.Context, eventID int64) (event.RefundPolicy, error)
+	CreatePriceTiersTx(ctx context.Context, tx *sql.Tx, tiers []event.PriceTier) error
+	GetPriceTiers(ctx context.Context, eventID int64) ([]event.PriceTier, error)
+	CreateFeesTx(ctx context.Context, tx *sql.Tx, fees []event.Fee) error
+	GetFees(ctx context.Context, eventID int64) ([]event.Fee, error)
+	CreateTicketTypesTx(ctx context.Context, tx *sql.Tx, types []event.TicketType) error
+	GetTicketTypes(ctx context.Context, eventID int64) ([]event.TicketType, error)
+	LockTicketTypesTx(ctx context.Context, tx *sql.Tx, ticketTypeIDs []int64) error
 }
 
 type eventRepository struct {
@@ -25,8 +36,8 @@ func NewEventRepository(db *sql.DB) EventRepository {
 
 func (r *eventRepository) CreateEventTx(ctx context.Context, tx *sql.Tx, input event.Event) (int64, error) {
 	query := `
-		INSERT INTO events (name, event_date, is_active)
-		VALUES ($1, $2, $3) RETURNING id
+		INSERT INTO events (name, event_date, is_active, max_seats_per_order, max_seats_per_user, resale_cap_percent, currency, tax_percent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id
 	`
 	var eventID int64
 	err := tx.QueryRowContext(
@@ -35,6 +46,11 @@ func (r *eventRepository) CreateEventTx(ctx context.Context, tx *sql.Tx, input e
 		input.Name,
 		input.EventDate,
 		input.IsActive,
+		input.MaxSeatsPerOrder,
+		input.MaxSeatsPerUser,
+		input.ResaleCapPercent,
+		input.Currency,
+		input.TaxPercent,
 	).Scan(&eventID)
 	if err != nil {
 		return 0, err
@@ -44,18 +60,31 @@ func (r *eventRepository) CreateEventTx(ctx context.Context, tx *sql.Tx, input e
 
 func (r *eventRepository) GetEventByID(ctx context.Context, eventID int64) (event.Event, error) {
 	query := `
-		SELECT id, name, event_date, is_active, created_at, updated_at
-		FROM events WHERE id = $1 LIMIT 1
+		SELECT
+			e.id, e.name, e.event_date, e.is_active, e.max_seats_per_order, e.max_seats_per_user,
+			e.resale_cap_percent, e.currency, e.tax_percent, e.created_at, e.updated_at, p.full_refund_days, p.partial_refund_days, p.partial_refund_percent
+		FROM events e
+		LEFT JOIN event_refund_policies p ON p.event_id = e.id
+		WHERE e.id = $1 LIMIT 1
 	`
 	var e event.Event
-	
+	var fullDays, partialDays, partialPercent sql.NullInt64
+
 	err := r.db.QueryRowContext(ctx, query, eventID).Scan(
 		&e.ID,
 		&e.Name,
 		&e.EventDate,
 		&e.IsActive,
+		&e.MaxSeatsPerOrder,
+		&e.MaxSeatsPerUser,
+		&e.ResaleCapPercent,
+		&e.Currency,
+		&e.TaxPercent,
 		&e.CreatedAt,
 		&e.UpdatedAt,
+		&fullDays,
+		&partialDays,
+		&partialPercent,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -63,12 +92,20 @@ func (r *eventRepository) GetEventByID(ctx context.Context, eventID int64) (even
 		}
 		return event.Event{}, err
 	}
+
+	if fullDays.Valid {
+		e.RefundPolicy = &event.RefundPolicy{
+			FullRefundDays:       int(fullDays.Int64),
+			PartialRefundDays:    int(partialDays.Int64),
+			PartialRefundPercent: int(partialPercent.Int64),
+		}
+	}
 	return e, nil
 }
 
 func (r *eventRepository) GetAllEvents(ctx context.Context) ([]event.Event, error) {
 	query := `
-		SELECT id, name, event_date, is_active, created_at, updated_at 
+		SELECT id, name, event_date, is_active, max_seats_per_order, max_seats_per_user, resale_cap_percent, currency, tax_percent, created_at, updated_at
 		FROM events ORDER BY id DESC
 	`
 	rows, err := r.db.QueryContext(ctx, query)
@@ -76,7 +113,7 @@ func (r *eventRepository) GetAllEvents(ctx context.Context) ([]event.Event, erro
 		return nil, err
 	}
 	defer rows.Close()
-	
+
 	var events []event.Event
 	for rows.Next() {
 		var e event.Event
@@ -85,6 +122,11 @@ func (r *eventRepository) GetAllEvents(ctx context.Context) ([]event.Event, erro
 			&e.Name,
 			&e.EventDate,
 			&e.IsActive,
+			&e.MaxSeatsPerOrder,
+			&e.MaxSeatsPerUser,
+			&e.ResaleCapPercent,
+			&e.Currency,
+			&e.TaxPercent,
 			&e.CreatedAt,
 			&e.UpdatedAt,
 		); err != nil {
@@ -92,9 +134,236 @@ func (r *eventRepository) GetAllEvents(ctx context.Context) ([]event.Event, erro
 		}
 		events = append(events, e)
 	}
-	
+
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 	return events, nil
-}
\ No newline at end of file
+}
+
+func (r *eventRepository) CreateRefundPolicyTx(ctx context.Context, tx *sql.Tx, eventID int64, policy event.RefundPolicy) error {
+	query := `
+		INSERT INTO event_refund_policies (event_id, full_refund_days, partial_refund_days, partial_refund_percent)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := tx.ExecContext(
+		ctx,
+		query,
+		eventID,
+		policy.FullRefundDays,
+		policy.PartialRefundDays,
+		policy.PartialRefundPercent,
+	)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *eventRepository) GetRefundPolicy(ctx context.Context, eventID int64) (event.RefundPolicy, error) {
+	query := `
+		SELECT full_refund_days, partial_refund_days, partial_refund_percent
+		FROM event_refund_policies WHERE event_id = $1
+	`
+	var p event.RefundPolicy
+
+	err := r.db.QueryRowContext(ctx, query, eventID).Scan(
+		&p.FullRefundDays,
+		&p.PartialRefundDays,
+		&p.PartialRefundPercent,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return event.RefundPolicy{}, errs.ErrRefundPolicyNotFound
+		}
+		return event.RefundPolicy{}, err
+	}
+	return p, nil
+}
+
+func (r *eventRepository) CreatePriceTiersTx(ctx context.Context, tx *sql.Tx, tiers []event.PriceTier) error {
+	query := `
+		INSERT INTO zone_price_tiers (event_id, zone, name, price, starts_at, ends_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	for _, t := range tiers {
+		if _, err := tx.ExecContext(ctx, query, t.EventID, t.Zone, t.Name, t.Price.Amount, t.StartsAt, t.EndsAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetPriceTiers : every tier of the event, by zone then start
+func (r *eventRepository) GetPriceTiers(ctx context.Context, eventID int64) ([]event.PriceTier, error) {
+	query := `
+		SELECT t.id, t.event_id, t.zone, t.name, t.price, e.currency, t.starts_at, t.ends_at
+		FROM zone_price_tiers t
+		JOIN events e ON e.id = t.event_id
+		WHERE t.event_id = $1
+		ORDER BY t.zone ASC, t.starts_at ASC NULLS FIRST, t.id ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tiers []event.PriceTier
+	for rows.Next() {
+		var t event.PriceTier
+		if err := rows.Scan(
+			&t.ID,
+			&t.EventID,
+			&t.Zone,
+			&t.Name,
+			&t.Price.Amount,
+			&t.Price.Currency,
+			&t.StartsAt,
+			&t.EndsAt,
+		); err != nil {
+			return nil, err
+		}
+		tiers = append(tiers, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return tiers, nil
+}
+
+func (r *eventRepository) CreateFeesTx(ctx context.Context, tx *sql.Tx, fees []event.Fee) error {
+	query := `
+		INSERT INTO event_fees (event_id, name, fee_type, amount, percent)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	for _, f := range fees {
+		var amount sql.NullInt64
+		var percent sql.NullFloat64
+		if f.Type == event.FeePercent {
+			percent = sql.NullFloat64{Float64: f.Percent, Valid: true}
+		} else {
+			amount = sql.NullInt64{Int64: f.Amount.Amount, Valid: true}
+		}
+		if _, err := tx.ExecContext(ctx, query, f.EventID, f.Name, f.Type, amount, percent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetFees : every fee of the event in the order they were added
+func (r *eventRepository) GetFees(ctx context.Context, eventID int64) ([]event.Fee, error) {
+	query := `
+		SELECT f.id, f.event_id, f.name, f.fee_type, COALESCE(f.amount, 0), COALESCE(f.percent, 0), e.currency
+		FROM event_fees f
+		JOIN events e ON e.id = f.event_id
+		WHERE f.event_id = $1
+		ORDER BY f.id ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fees []event.Fee
+	for rows.Next() {
+		var f event.Fee
+		if err := rows.Scan(
+			&f.ID,
+			&f.EventID,
+			&f.Name,
+			&f.Type,
+			&f.Amount.Amount,
+			&f.Percent,
+			&f.Amount.Currency,
+		); err != nil {
+			return nil, err
+		}
+		fees = append(fees, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return fees, nil
+}
+
+func (r *eventRepository) CreateTicketTypesTx(ctx context.Context, tx *sql.Tx, types []event.TicketType) error {
+	typeQuery := `
+		INSERT INTO ticket_types (event_id, name, eligibility, quota)
+		VALUES ($1, $2, NULLIF($3, ''), $4) RETURNING id
+	`
+	priceQuery := `
+		INSERT INTO ticket_type_prices (ticket_type_id, zone, price)
+		VALUES ($1, $2, $3)
+	`
+	for _, t := range types {
+		var id int64
+		if err := tx.QueryRowContext(ctx, typeQuery, t.EventID, t.Name, t.Eligibility, t.Quota).Scan(&id); err != nil {
+			return err
+		}
+		for _, p := range t.Prices {
+			if _, err := tx.ExecContext(ctx, priceQuery, id, p.Zone, p.Price.Amount); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetTicketTypes : every ticket type of the event with the zones offering it
+func (r *eventRepository) GetTicketTypes(ctx context.Context, eventID int64) ([]event.TicketType, error) {
+	query := `
+		SELECT t.id, t.event_id, t.name, COALESCE(t.eligibility, ''), t.quota, p.zone, p.price, e.currency
+		FROM ticket_types t
+		JOIN ticket_type_prices p ON p.ticket_type_id = t.id
+		JOIN events e ON e.id = t.event_id
+		WHERE t.event_id = $1
+		ORDER BY t.id ASC, p.zone ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var types []event.TicketType
+	for rows.Next() {
+		var t event.TicketType
+		var p event.TicketPrice
+		if err := rows.Scan(
+			&t.ID,
+			&t.EventID,
+			&t.Name,
+			&t.Eligibility,
+			&t.Quota,
+			&p.Zone,
+			&p.Price.Amount,
+			&p.Price.Currency,
+		); err != nil {
+			return nil, err
+		}
+		// Rows of one type come together, a new ID starts the next type
+		if n := len(types); n > 0 && types[n-1].ID == t.ID {
+			types[n-1].Prices = append(types[n-1].Prices, p)
+			continue
+		}
+		t.Prices = []event.TicketPrice{p}
+		types = append(types, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return types, nil
+}
+
+// LockTicketTypesTx : lock the types in ascending ID order, orders against the same quota run one at a time
+func (r *eventRepository) LockTicketTypesTx(ctx context.Context, tx *sql.Tx, ticketTypeIDs []int64) error {
+	query := `SELECT id FROM ticket_types WHERE id = ANY($1) ORDER BY id FOR UPDATE`
+	_, err := tx.ExecContext(ctx, query, pq.Array(ticketTypeIDs))
+	return err
+}
@@ -0,0 +1,212 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: event_repo.go
+
+// Package eventrepo is a generated GoMock package.
+package eventrepo
+
+import (
+	context "context"
+	sql "database/sql"
+	reflect "reflect"
+
+	event "github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockEventRepository is a mock of EventRepository interface.
+type MockEventRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockEventRepositoryMockRecorder
+}
+
+// MockEventRepositoryMockRecorder is the mock recorder for MockEventRepository.
+type MockEventRepositoryMockRecorder struct {
+	mock *MockEventRepository
+}
+
+// NewMockEventRepository creates a new mock instance.
+func NewMockEventRepository(ctrl *gomock.Controller) *MockEventRepository {
+	mock := &MockEventRepository{ctrl: ctrl}
+	mock.recorder = &MockEventRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEventRepository) EXPECT() *MockEventRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateEventTx mocks base method.
+func (m *MockEventRepository) CreateEventTx(ctx context.Context, tx *sql.Tx, input event.Event) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEventTx", ctx, tx, input)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEventTx indicates an expected call of CreateEventTx.
+func (mr *MockEventRepositoryMockRecorder) CreateEventTx(ctx, tx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEventTx", reflect.TypeOf((*MockEventRepository)(nil).CreateEventTx), ctx, tx, input)
+}
+
+// CreateFeesTx mocks base method.
+func (m *MockEventRepository) CreateFeesTx(ctx context.Context, tx *sql.Tx, fees []event.Fee) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFeesTx", ctx, tx, fees)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateFeesTx indicates an expected call of CreateFeesTx.
+func (mr *MockEventRepositoryMockRecorder) CreateFeesTx(ctx, tx, fees interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFeesTx", reflect.TypeOf((*MockEventRepository)(nil).CreateFeesTx), ctx, tx, fees)
+}
+
+// CreatePriceTiersTx mocks base method.
+func (m *MockEventRepository) CreatePriceTiersTx(ctx context.Context, tx *sql.Tx, tiers []event.PriceTier) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePriceTiersTx", ctx, tx, tiers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePriceTiersTx indicates an expected call of CreatePriceTiersTx.
+func (mr *MockEventRepositoryMockRecorder) CreatePriceTiersTx(ctx, tx, tiers interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePriceTiersTx", reflect.TypeOf((*MockEventRepository)(nil).CreatePriceTiersTx), ctx, tx, tiers)
+}
+
+// CreateRefundPolicyTx mocks base method.
+func (m *MockEventRepository) CreateRefundPolicyTx(ctx context.Context, tx *sql.Tx, eventID int64, policy event.RefundPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefundPolicyTx", ctx, tx, eventID, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRefundPolicyTx indicates an expected call of CreateRefundPolicyTx.
+func (mr *MockEventRepositoryMockRecorder) CreateRefundPolicyTx(ctx, tx, eventID, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefundPolicyTx", reflect.TypeOf((*MockEventRepository)(nil).CreateRefundPolicyTx), ctx, tx, eventID, policy)
+}
+
+// CreateTicketTypesTx mocks base method.
+func (m *MockEventRepository) CreateTicketTypesTx(ctx context.Context, tx *sql.Tx, types []event.TicketType) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTicketTypesTx", ctx, tx, types)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTicketTypesTx indicates an expected call of CreateTicketTypesTx.
+func (mr *MockEventRepositoryMockRecorder) CreateTicketTypesTx(ctx, tx, types interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTicketTypesTx", reflect.TypeOf((*MockEventRepository)(nil).CreateTicketTypesTx), ctx, tx, types)
+}
+
+// GetAllEvents mocks base method.
+func (m *MockEventRepository) GetAllEvents(ctx context.Context) ([]event.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllEvents", ctx)
+	ret0, _ := ret[0].([]event.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllEvents indicates an expected call of GetAllEvents.
+func (mr *MockEventRepositoryMockRecorder) GetAllEvents(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllEvents", reflect.TypeOf((*MockEventRepository)(nil).GetAllEvents), ctx)
+}
+
+// GetEventByID mocks base method.
+func (m *MockEventRepository) GetEventByID(ctx context.Context, eventID int64) (event.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventByID", ctx, eventID)
+	ret0, _ := ret[0].(event.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventByID indicates an expected call of GetEventByID.
+func (mr *MockEventRepositoryMockRecorder) GetEventByID(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventByID", reflect.TypeOf((*MockEventRepository)(nil).GetEventByID), ctx, eventID)
+}
+
+// GetFees mocks base method.
+func (m *MockEventRepository) GetFees(ctx context.Context, eventID int64) ([]event.Fee, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFees", ctx, eventID)
+	ret0, _ := ret[0].([]event.Fee)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFees indicates an expected call of GetFees.
+func (mr *MockEventRepositoryMockRecorder) GetFees(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFees", reflect.TypeOf((*MockEventRepository)(nil).GetFees), ctx, eventID)
+}
+
+// GetPriceTiers mocks base method.
+func (m *MockEventRepository) GetPriceTiers(ctx context.Context, eventID int64) ([]event.PriceTier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPriceTiers", ctx, eventID)
+	ret0, _ := ret[0].([]event.PriceTier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPriceTiers indicates an expected call of GetPriceTiers.
+func (mr *MockEventRepositoryMockRecorder) GetPriceTiers(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceTiers", reflect.TypeOf((*MockEventRepository)(nil).GetPriceTiers), ctx, eventID)
+}
+
+// GetRefundPolicy mocks base method.
+func (m *MockEventRepository) GetRefundPolicy(ctx context.Context, eventID int64) (event.RefundPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefundPolicy", ctx, eventID)
+	ret0, _ := ret[0].(event.RefundPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefundPolicy indicates an expected call of GetRefundPolicy.
+func (mr *MockEventRepositoryMockRecorder) GetRefundPolicy(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefundPolicy", reflect.TypeOf((*MockEventRepository)(nil).GetRefundPolicy), ctx, eventID)
+}
+
+// GetTicketTypes mocks base method.
+func (m *MockEventRepository) GetTicketTypes(ctx context.Context, eventID int64) ([]event.TicketType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTicketTypes", ctx, eventID)
+	ret0, _ := ret[0].([]event.TicketType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTicketTypes indicates an expected call of GetTicketTypes.
+func (mr *MockEventRepositoryMockRecorder) GetTicketTypes(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTicketTypes", reflect.TypeOf((*MockEventRepository)(nil).GetTicketTypes), ctx, eventID)
+}
+
+// LockTicketTypesTx mocks base method.
+func (m *MockEventRepository) LockTicketTypesTx(ctx context.Context, tx *sql.Tx, ticketTypeIDs []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockTicketTypesTx", ctx, tx, ticketTypeIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockTicketTypesTx indicates an expected call of LockTicketTypesTx.
+func (mr *MockEventRepositoryMockRecorder) LockTicketTypesTx(ctx, tx, ticketTypeIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockTicketTypesTx", reflect.TypeOf((*MockEventRepository)(nil).LockTicketTypesTx), ctx, tx, ticketTypeIDs)
+}
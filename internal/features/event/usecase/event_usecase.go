@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/codepnw/stdlib-ticket-system/internal/config"
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
 	"github.com/codepnw/stdlib-ticket-system/internal/features/event"
 	eventrepo "github.com/codepnw/stdlib-ticket-system/internal/features/event/repo"
 	"github.com/codepnw/stdlib-ticket-system/internal/features/seat"
@@ -17,7 +18,7 @@ type EventUsecase interface {
 	CreateEvent(ctx context.Context, req event.CreateEventReq) error
 	GetEventByID(ctx context.Context, eventID int64) (event.Event, error)
 	GetAllEvents(ctx context.Context) ([]event.Event, error)
-	GetSeatsByEventID(ctx context.Context, eventID int64) ([]seat.Seat, error)
+	GetSeatsByEventID(ctx context.Context, eventID int64) ([]seat.Section, error)
 }
 
 type eventUsecase struct {
@@ -40,26 +41,53 @@ func (u *eventUsecase) CreateEvent(ctx context.Context, req event.CreateEventReq
 
 	err := u.tx.WithTx(ctx, func(tx *sql.Tx) error {
 		eventID, err := u.eventRepo.CreateEventTx(ctx, tx, event.Event{
-			Name:      req.Name,
-			EventDate: req.EventDate,
-			IsActive:  req.IsActive,
+			Name:             req.Name,
+			EventDate:        req.EventDate,
+			IsActive:         req.IsActive,
+			MaxSeatsPerOrder: req.MaxSeatsPerOrder,
+			MaxSeatsPerUser:  req.MaxSeatsPerUser,
+			ResaleCapPercent: req.ResaleCapPercent,
+			Currency:         req.Currency,
+			TaxPercent:       req.TaxPercent,
 		})
 		if err != nil {
 			return err
 		}
 
+		if req.RefundPolicy != nil {
+			if err := u.eventRepo.CreateRefundPolicyTx(ctx, tx, eventID, *req.RefundPolicy); err != nil {
+				return err
+			}
+		}
+
 		seats := make([]seat.Seat, 0)
+		var tiers []event.PriceTier
 
-		for _, zone := range req.Zones {
-			for i := 1; i <= zone.SeatsPerRow; i++ {
-				s := seat.Seat{
-					EventID:    eventID,
-					SeatNumber: fmt.Sprintf("%s%d", zone.ZoneName, i),
-					Price:      zone.Price,
-					Status:     seat.StatusAvailable,
-					Version:    1,
+		for i, zone := range req.Zones {
+			// Every price is in the event currency
+			if zone.Price.Currency != req.Currency {
+				return errs.ErrCurrencyMismatch
+			}
+			if zone.Price.Amount < 0 {
+				return errs.ErrInvalidMoney
+			}
+			seats = append(seats, zoneSeats(eventID, i, zone)...)
+
+			for _, t := range zone.Tiers {
+				if t.Price.Currency != req.Currency {
+					return errs.ErrCurrencyMismatch
 				}
-				seats = append(seats, s)
+				if !t.Valid() {
+					return errs.ErrInvalidPriceTier
+				}
+				tiers = append(tiers, event.PriceTier{
+					EventID:  eventID,
+					Zone:     zone.ZoneName,
+					Name:     t.Name,
+					Price:    t.Price,
+					StartsAt: t.StartsAt,
+					EndsAt:   t.EndsAt,
+				})
 			}
 		}
 
@@ -68,6 +96,44 @@ func (u *eventUsecase) CreateEvent(ctx context.Context, req event.CreateEventReq
 				return err
 			}
 		}
+
+		if len(tiers) > 0 {
+			if err := u.eventRepo.CreatePriceTiersTx(ctx, tx, tiers); err != nil {
+				return err
+			}
+		}
+
+		fees := make([]event.Fee, 0, len(req.Fees))
+		for _, f := range req.Fees {
+			if !f.Valid() {
+				return errs.ErrInvalidFee
+			}
+			if f.Type != event.FeePercent && f.Amount.Currency != req.Currency {
+				return errs.ErrCurrencyMismatch
+			}
+			fees = append(fees, event.Fee{
+				EventID: eventID,
+				Name:    f.Name,
+				Type:    f.Type,
+				Amount:  f.Amount,
+				Percent: f.Percent,
+			})
+		}
+		if len(fees) > 0 {
+			if err := u.eventRepo.CreateFeesTx(ctx, tx, fees); err != nil {
+				return err
+			}
+		}
+
+		types, err := ticketTypes(eventID, req)
+		if err != nil {
+			return err
+		}
+		if len(types) > 0 {
+			if err := u.eventRepo.CreateTicketTypesTx(ctx, tx, types); err != nil {
+				return err
+			}
+		}
 		return nil
 	})
 	return err
@@ -84,12 +150,128 @@ func (u *eventUsecase) GetEventByID(ctx context.Context, eventID int64) (event.E
 	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
 	defer cancel()
 
-	return u.eventRepo.GetEventByID(ctx, eventID)
+	e, err := u.eventRepo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return event.Event{}, err
+	}
+
+	e.PriceTiers, err = u.eventRepo.GetPriceTiers(ctx, eventID)
+	if err != nil {
+		return event.Event{}, err
+	}
+	e.Fees, err = u.eventRepo.GetFees(ctx, eventID)
+	if err != nil {
+		return event.Event{}, err
+	}
+	e.TicketTypes, err = u.eventRepo.GetTicketTypes(ctx, eventID)
+	if err != nil {
+		return event.Event{}, err
+	}
+	return e, nil
+}
+
+// ticketTypes : names are unique within the event, each price is for a zone of the event, once per zone
+func ticketTypes(eventID int64, req event.CreateEventReq) ([]event.TicketType, error) {
+	zones := make(map[string]bool, len(req.Zones))
+	for _, z := range req.Zones {
+		zones[z.ZoneName] = true
+	}
+
+	names := make(map[string]bool, len(req.TicketTypes))
+	types := make([]event.TicketType, 0, len(req.TicketTypes))
+	for _, t := range req.TicketTypes {
+		if names[t.Name] {
+			return nil, errs.ErrInvalidTicketType
+		}
+		names[t.Name] = true
+
+		priced := make(map[string]bool, len(t.Prices))
+		prices := make([]event.TicketPrice, 0, len(t.Prices))
+		for _, p := range t.Prices {
+			if !zones[p.Zone] || priced[p.Zone] {
+				return nil, errs.ErrInvalidTicketType
+			}
+			priced[p.Zone] = true
+
+			if p.Price.Currency != req.Currency {
+				return nil, errs.ErrCurrencyMismatch
+			}
+			if p.Price.Amount < 0 {
+				return nil, errs.ErrInvalidMoney
+			}
+			prices = append(prices, event.TicketPrice{Zone: p.Zone, Price: p.Price})
+		}
+
+		types = append(types, event.TicketType{
+			EventID:     eventID,
+			Name:        t.Name,
+			Eligibility: t.Eligibility,
+			Quota:       t.Quota,
+			Prices:      prices,
+		})
+	}
+	return types, nil
 }
 
-func (u *eventUsecase) GetSeatsByEventID(ctx context.Context, eventID int64) ([]seat.Seat, error) {
+func (u *eventUsecase) GetSeatsByEventID(ctx context.Context, eventID int64) ([]seat.Section, error) {
 	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
 	defer cancel()
 
-	return u.seatRepo.GetSeatsByEventID(ctx, eventID)
+	seats, err := u.seatRepo.GetSeatsByEventID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return seat.GroupMap(seats), nil
+}
+
+// zoneSeats : lay out the seats of one zone.
+// A zone without sections is the old single row, "A1".."An", placed on row index of the map.
+// Sections number their seats "<section>-<row><position>", an aisle adds one empty x step.
+func zoneSeats(eventID int64, index int, zone event.SeatZoneReq) []seat.Seat {
+	newSeat := func(number, section, row string, position, x, y int) seat.Seat {
+		return seat.Seat{
+			EventID:    eventID,
+			SeatNumber: number,
+			Zone:       zone.ZoneName,
+			Section:    section,
+			Row:        row,
+			Position:   position,
+			X:          x,
+			Y:          y,
+			Price:      zone.Price,
+			Status:     seat.StatusAvailable,
+			Version:    1,
+		}
+	}
+
+	var seats []seat.Seat
+
+	if len(zone.Sections) == 0 {
+		for i := 1; i <= zone.SeatsPerRow; i++ {
+			seats = append(seats, newSeat(fmt.Sprintf("%s%d", zone.ZoneName, i), zone.ZoneName, "1", i, i-1, index))
+		}
+		return seats
+	}
+
+	for _, section := range zone.Sections {
+		for _, row := range section.Rows {
+			aisleAfter := make(map[int]bool, len(row.AisleAfter))
+			for _, p := range row.AisleAfter {
+				aisleAfter[p] = true
+			}
+
+			x := row.X
+			for i := 1; i <= row.Seats; i++ {
+				s := newSeat(fmt.Sprintf("%s-%s%d", section.Name, row.Label, i), section.Name, row.Label, i, x, row.Y)
+				s.Aisle = aisleAfter[i] || aisleAfter[i-1]
+				seats = append(seats, s)
+
+				x++
+				if aisleAfter[i] {
+					x++
+				}
+			}
+		}
+	}
+	return seats
 }
@@ -0,0 +1,270 @@
+package eventusecase_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	eventrepo "github.com/codepnw/stdlib-ticket-system/internal/features/event/repo"
+	eventusecase "github.com/codepnw/stdlib-ticket-system/internal/features/event/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/seat"
+	seatrepo "github.com/codepnw/stdlib-ticket-system/internal/features/seat/repo"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockTx struct{}
+
+func (m mockTx) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	return fn(nil)
+}
+
+func (m mockTx) WithRetryTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	return fn(nil)
+}
+
+type mocks struct {
+	event *eventrepo.MockEventRepository
+	seat  *seatrepo.MockSeatRepository
+}
+
+func TestCreateEvent(t *testing.T) {
+	type testCase struct {
+		name     string
+		zones    []event.SeatZoneReq
+		expected []seat.Seat
+	}
+
+	testCases := []testCase{
+		{
+			name:  "single row zone",
+			zones: []event.SeatZoneReq{{ZoneName: "A", SeatsPerRow: 2, Price: money.New(10000, "THB")}},
+			expected: []seat.Seat{
+				{SeatNumber: "A1", Zone: "A", Section: "A", Row: "1", Position: 1, X: 0, Y: 0},
+				{SeatNumber: "A2", Zone: "A", Section: "A", Row: "1", Position: 2, X: 1, Y: 0},
+			},
+		},
+		{
+			name: "sections with an aisle",
+			zones: []event.SeatZoneReq{{
+				ZoneName: "VIP",
+				Price:    money.New(10000, "THB"),
+				Sections: []event.SeatSectionReq{{
+					Name: "L1",
+					Rows: []event.SeatRowReq{
+						{Label: "A", Seats: 3, X: 5, Y: 0, AisleAfter: []int{1}},
+						{Label: "B", Seats: 1, X: 5, Y: 1},
+					},
+				}},
+			}},
+			expected: []seat.Seat{
+				{SeatNumber: "L1-A1", Zone: "VIP", Section: "L1", Row: "A", Position: 1, X: 5, Y: 0, Aisle: true},
+				{SeatNumber: "L1-A2", Zone: "VIP", Section: "L1", Row: "A", Position: 2, X: 7, Y: 0, Aisle: true},
+				{SeatNumber: "L1-A3", Zone: "VIP", Section: "L1", Row: "A", Position: 3, X: 8, Y: 0},
+				{SeatNumber: "L1-B1", Zone: "VIP", Section: "L1", Row: "B", Position: 1, X: 5, Y: 1},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, m := setup(t)
+
+			for i := range tc.expected {
+				tc.expected[i].EventID = 10
+				tc.expected[i].Price = money.New(10000, "THB")
+				tc.expected[i].Status = seat.StatusAvailable
+				tc.expected[i].Version = 1
+			}
+
+			// Mock FN
+			m.event.EXPECT().CreateEventTx(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(10), nil).Times(1)
+
+			m.seat.EXPECT().CreateSeatBatchTx(gomock.Any(), gomock.Any(), tc.expected).Return(nil).Times(1)
+
+			err := uc.CreateEvent(context.Background(), event.CreateEventReq{Name: "concert", Currency: "THB", Zones: tc.zones})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCreateEventCurrencyMismatch(t *testing.T) {
+	uc, m := setup(t)
+
+	m.event.EXPECT().CreateEventTx(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(10), nil).Times(1)
+
+	err := uc.CreateEvent(context.Background(), event.CreateEventReq{
+		Name:     "concert",
+		Currency: "THB",
+		Zones:    []event.SeatZoneReq{{ZoneName: "A", SeatsPerRow: 2, Price: money.New(1000, "USD")}},
+	})
+	assert.ErrorIs(t, err, errs.ErrCurrencyMismatch)
+}
+
+func TestCreateEventFees(t *testing.T) {
+	type testCase struct {
+		name        string
+		fees        []event.FeeReq
+		mockFn      func(m mocks)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "success",
+			fees: []event.FeeReq{
+				{Name: "service", Type: event.FeePercent, Percent: 5},
+				{Name: "facility", Type: event.FeePerTicket, Amount: money.New(2000, "THB")},
+			},
+			mockFn: func(m mocks) {
+				m.event.EXPECT().CreateFeesTx(gomock.Any(), gomock.Any(), []event.Fee{
+					{EventID: 10, Name: "service", Type: event.FeePercent, Percent: 5},
+					{EventID: 10, Name: "facility", Type: event.FeePerTicket, Amount: money.New(2000, "THB")},
+				}).Return(nil).Times(1)
+			},
+		},
+		{
+			name:        "fail percent over 100",
+			fees:        []event.FeeReq{{Name: "service", Type: event.FeePercent, Percent: 120}},
+			mockFn:      func(m mocks) {},
+			expectedErr: errs.ErrInvalidFee,
+		},
+		{
+			name:        "fail flat fee without amount",
+			fees:        []event.FeeReq{{Name: "handling", Type: event.FeePerOrder}},
+			mockFn:      func(m mocks) {},
+			expectedErr: errs.ErrInvalidFee,
+		},
+		{
+			name:        "fail flat fee in other currency",
+			fees:        []event.FeeReq{{Name: "handling", Type: event.FeePerOrder, Amount: money.New(1000, "USD")}},
+			mockFn:      func(m mocks) {},
+			expectedErr: errs.ErrCurrencyMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, m := setup(t)
+
+			// Mock FN
+			m.event.EXPECT().CreateEventTx(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(10), nil).Times(1)
+			tc.mockFn(m)
+
+			err := uc.CreateEvent(context.Background(), event.CreateEventReq{
+				Name:       "concert",
+				Currency:   "THB",
+				TaxPercent: 7,
+				Fees:       tc.fees,
+			})
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCreateEventTicketTypes(t *testing.T) {
+	zones := []event.SeatZoneReq{
+		{ZoneName: "A", Price: money.New(10000, "THB")},
+		{ZoneName: "B", Price: money.New(6000, "THB")},
+	}
+
+	type testCase struct {
+		name        string
+		types       []event.TicketTypeReq
+		mockFn      func(m mocks)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "success",
+			types: []event.TicketTypeReq{{
+				Name:        "concession",
+				Eligibility: "students and seniors, ID checked at the door",
+				Quota:       20,
+				Prices: []event.TicketPriceReq{
+					{Zone: "A", Price: money.New(7000, "THB")},
+					{Zone: "B", Price: money.New(4000, "THB")},
+				},
+			}},
+			mockFn: func(m mocks) {
+				m.event.EXPECT().CreateTicketTypesTx(gomock.Any(), gomock.Any(), []event.TicketType{{
+					EventID:     10,
+					Name:        "concession",
+					Eligibility: "students and seniors, ID checked at the door",
+					Quota:       20,
+					Prices: []event.TicketPrice{
+						{Zone: "A", Price: money.New(7000, "THB")},
+						{Zone: "B", Price: money.New(4000, "THB")},
+					},
+				}}).Return(nil).Times(1)
+			},
+		},
+		{
+			name: "fail duplicate name",
+			types: []event.TicketTypeReq{
+				{Name: "child", Prices: []event.TicketPriceReq{{Zone: "A", Price: money.New(5000, "THB")}}},
+				{Name: "child", Prices: []event.TicketPriceReq{{Zone: "B", Price: money.New(3000, "THB")}}},
+			},
+			expectedErr: errs.ErrInvalidTicketType,
+		},
+		{
+			name:        "fail zone not in event",
+			types:       []event.TicketTypeReq{{Name: "child", Prices: []event.TicketPriceReq{{Zone: "VIP", Price: money.New(5000, "THB")}}}},
+			expectedErr: errs.ErrInvalidTicketType,
+		},
+		{
+			name:        "fail price in other currency",
+			types:       []event.TicketTypeReq{{Name: "child", Prices: []event.TicketPriceReq{{Zone: "A", Price: money.New(5000, "USD")}}}},
+			expectedErr: errs.ErrCurrencyMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, m := setup(t)
+
+			// Mock FN
+			m.event.EXPECT().CreateEventTx(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(10), nil).Times(1)
+			if tc.mockFn != nil {
+				tc.mockFn(m)
+			}
+
+			err := uc.CreateEvent(context.Background(), event.CreateEventReq{
+				Name:        "concert",
+				Currency:    "THB",
+				Zones:       zones,
+				TicketTypes: tc.types,
+			})
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func setup(t *testing.T) (eventusecase.EventUsecase, mocks) {
+	ctrl := gomock.NewController(t)
+
+	m := mocks{
+		event: eventrepo.NewMockEventRepository(ctrl),
+		seat:  seatrepo.NewMockSeatRepository(ctrl),
+	}
+	uc := eventusecase.NewEventUsecase(mockTx{}, m.event, m.seat)
+
+	return uc, m
+}
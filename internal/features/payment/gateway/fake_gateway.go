@@ -0,0 +1,138 @@
+package paymentgateway
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+)
+
+// Fake Tokens : drive the fake gateway outcome from the client token
+const (
+	FakeTokenSuccess     = "tok_success"
+	FakeTokenDecline     = "tok_decline"
+	FakeTokenCaptureFail = "tok_capture_fail"
+)
+
+type fakeGateway struct {
+	mu       sync.Mutex
+	auths    map[string]fakeAuth
+	captures map[string]fakeCapture
+	refunds  map[string]Refund
+	payouts  map[string]Payout
+}
+
+type fakeAuth struct {
+	amount      money.Money
+	captureFail bool
+}
+
+type fakeCapture struct {
+	amount   money.Money
+	refunded money.Money
+	refunds  int
+}
+
+// NewFakeGateway : deterministic in-process gateway for dev and tests
+func NewFakeGateway() PaymentGateway {
+	return &fakeGateway{
+		auths:    make(map[string]fakeAuth),
+		captures: make(map[string]fakeCapture),
+		refunds:  make(map[string]Refund),
+		payouts:  make(map[string]Payout),
+	}
+}
+
+func (g *fakeGateway) Name() string {
+	return "fake"
+}
+
+func (g *fakeGateway) Authorize(ctx context.Context, req AuthorizeReq) (Authorization, error) {
+	if !req.Amount.IsPositive() {
+		return Authorization{}, ErrInvalidAmount
+	}
+	if req.Token == FakeTokenDecline {
+		return Authorization{}, ErrDeclined
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	id := fmt.Sprintf("fake_auth_%s", req.Reference)
+	g.auths[id] = fakeAuth{
+		amount:      req.Amount,
+		captureFail: req.Token == FakeTokenCaptureFail,
+	}
+	return Authorization{ID: id, Amount: req.Amount}, nil
+}
+
+func (g *fakeGateway) Capture(ctx context.Context, authorizationID string, amount money.Money) (Capture, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	auth, ok := g.auths[authorizationID]
+	if !ok {
+		return Capture{}, ErrAuthorizationNotFound
+	}
+	if auth.captureFail {
+		return Capture{}, ErrCaptureFailed
+	}
+	if cmp, err := amount.Cmp(auth.amount); err != nil || !amount.IsPositive() || cmp > 0 {
+		return Capture{}, ErrInvalidAmount
+	}
+
+	id := fmt.Sprintf("fake_cap_%s", authorizationID)
+	g.captures[id] = fakeCapture{amount: amount, refunded: money.Zero(amount.Currency)}
+	delete(g.auths, authorizationID)
+
+	return Capture{ID: id, AuthorizationID: authorizationID, Amount: amount}, nil
+}
+
+func (g *fakeGateway) Refund(ctx context.Context, reference, captureID string, amount money.Money) (Refund, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if refund, ok := g.refunds[reference]; ok {
+		return refund, nil
+	}
+
+	capture, ok := g.captures[captureID]
+	if !ok {
+		return Refund{}, ErrCaptureNotFound
+	}
+	refunded, err := capture.refunded.Add(amount)
+	if err != nil || !amount.IsPositive() {
+		return Refund{}, ErrInvalidAmount
+	}
+	if cmp, _ := refunded.Cmp(capture.amount); cmp > 0 {
+		return Refund{}, ErrInvalidAmount
+	}
+
+	capture.refunded = refunded
+	capture.refunds++
+	g.captures[captureID] = capture
+
+	id := fmt.Sprintf("fake_ref_%s_%d", captureID, capture.refunds)
+	refund := Refund{ID: id, Reference: reference, CaptureID: captureID, Amount: amount}
+	g.refunds[reference] = refund
+	return refund, nil
+}
+
+func (g *fakeGateway) Payout(ctx context.Context, reference string, amount money.Money) (Payout, error) {
+	if !amount.IsPositive() {
+		return Payout{}, ErrInvalidAmount
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if payout, ok := g.payouts[reference]; ok {
+		return payout, nil
+	}
+
+	id := fmt.Sprintf("fake_payout_%s", reference)
+	payout := Payout{ID: id, Reference: reference, Amount: amount}
+	g.payouts[reference] = payout
+	return payout, nil
+}
@@ -0,0 +1,81 @@
+package paymentgateway
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+)
+
+var (
+	ErrDeclined              = errors.New("payment declined")
+	ErrCaptureFailed         = errors.New("payment capture failed")
+	ErrAuthorizationNotFound = errors.New("authorization not found")
+	ErrCaptureNotFound       = errors.New("capture not found")
+	ErrInvalidAmount         = errors.New("invalid payment amount")
+)
+
+// PaymentGateway : payment provider used to charge bookings
+type PaymentGateway interface {
+	Name() string
+	Authorize(ctx context.Context, req AuthorizeReq) (Authorization, error)
+	Capture(ctx context.Context, authorizationID string, amount money.Money) (Capture, error)
+	// Refund : give back part of a capture, reference identifies the refund so a retry refunds it once
+	Refund(ctx context.Context, reference, captureID string, amount money.Money) (Refund, error)
+	// Payout : send money to a seller, reference identifies the sale so a retry pays it once
+	Payout(ctx context.Context, reference string, amount money.Money) (Payout, error)
+}
+
+// NewGateway : pick the gateway by provider name from config
+func NewGateway(provider string) (PaymentGateway, error) {
+	switch provider {
+	case "fake":
+		return NewFakeGateway(), nil
+	default:
+		return nil, fmt.Errorf("unsupported payment provider: %q", provider)
+	}
+}
+
+// AttemptReference : base plus a random suffix, so two attempts on the same booking never share a charge
+func AttemptReference(base string) string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("read random reference: %v", err))
+	}
+	return base + "-" + hex.EncodeToString(b)
+}
+
+type AuthorizeReq struct {
+	// Reference : merchant reference, unique per attempt, see AttemptReference
+	Reference string
+	Amount    money.Money
+	// Token : card / wallet token from the client
+	Token string
+}
+
+type Authorization struct {
+	ID     string
+	Amount money.Money
+}
+
+type Capture struct {
+	ID              string
+	AuthorizationID string
+	Amount          money.Money
+}
+
+type Refund struct {
+	ID        string
+	Reference string
+	CaptureID string
+	Amount    money.Money
+}
+
+type Payout struct {
+	ID        string
+	Reference string
+	Amount    money.Money
+}
@@ -0,0 +1,5 @@
+package paymenthandler
+
+type PaymentReq struct {
+	PaymentToken string `json:"payment_token" validate:"required"`
+}
@@ -0,0 +1,54 @@
+package paymenthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	paymentusecase "github.com/codepnw/stdlib-ticket-system/internal/features/payment/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/helper"
+	"github.com/codepnw/stdlib-ticket-system/pkg/utils"
+)
+
+type paymentHandler struct {
+	uc paymentusecase.PaymentUsecase
+}
+
+func NewPaymentHandler(uc paymentusecase.PaymentUsecase) *paymentHandler {
+	return &paymentHandler{uc: uc}
+}
+
+func (h *paymentHandler) PayBooking(w http.ResponseWriter, r *http.Request) {
+	var req PaymentReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := utils.Validate(&req); err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.uc.PayBooking(r.Context(), r.PathValue("booking_id"), req.PaymentToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrBookingNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, errs.ErrBookingNotOwned):
+			helper.ErrorResponse(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, errs.ErrBookingNotPending), errors.Is(err, errs.ErrBookingExpired):
+			helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errs.ErrInvalidTransition):
+			helper.ErrorResponse(w, http.StatusConflict, err.Error())
+		case errors.Is(err, errs.ErrPaymentFailed):
+			helper.ErrorResponse(w, http.StatusPaymentRequired, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	helper.SuccessResponse(w, http.StatusOK, "booking paid", data)
+}
@@ -0,0 +1,46 @@
+package payment
+
+import (
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+)
+
+type PaymentStatus string
+
+const (
+	StatusCaptured PaymentStatus = "CAPTURED"
+	StatusFailed   PaymentStatus = "FAILED"
+	StatusRefunded PaymentStatus = "REFUNDED"
+)
+
+type RefundStatus string
+
+const (
+	RefundPending   RefundStatus = "PENDING"
+	RefundCompleted RefundStatus = "COMPLETED"
+)
+
+type Payment struct {
+	ID              int64         `json:"id" db:"id"`
+	BookingID       string        `json:"booking_id" db:"booking_id"`
+	Provider        string        `json:"provider" db:"provider"`
+	AuthorizationID string        `json:"authorization_id" db:"authorization_id"`
+	CaptureID       string        `json:"capture_id" db:"capture_id"`
+	Amount          money.Money   `json:"amount" db:"amount"`
+	Status          PaymentStatus `json:"status" db:"status"`
+	FailureReason   string        `json:"failure_reason" db:"failure_reason"`
+	CreatedAt       time.Time     `json:"created_at" db:"created_at"`
+}
+
+type Refund struct {
+	ID               int64        `json:"id" db:"id"`
+	BookingID        string       `json:"booking_id" db:"booking_id"`
+	PaymentID        int64        `json:"payment_id" db:"payment_id"`
+	Amount           money.Money  `json:"amount" db:"amount"`
+	Percent          int          `json:"percent" db:"percent"`
+	ProviderRefundID string       `json:"provider_refund_id" db:"provider_refund_id"`
+	Status           RefundStatus `json:"status" db:"status"`
+	CaptureID        string       `json:"capture_id" db:"capture_id"`
+	CreatedAt        time.Time    `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,208 @@
+package paymentrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/payment"
+)
+
+//go:generate mockgen -source=payment_repo.go -destination=payment_repo_mock.go -package=paymentrepo
+type PaymentRepository interface {
+	GetPendingRefunds(ctx context.Context, limit int) ([]payment.Refund, error)
+	GetPendingRefundsByBookingID(ctx context.Context, bookingID string) ([]payment.Refund, error)
+	CompleteRefund(ctx context.Context, refundID int64, providerRefundID string) error
+
+	// Transaction
+	CreatePaymentTx(ctx context.Context, tx *sql.Tx, input payment.Payment) (int64, error)
+	GetCapturedByBookingIDTx(ctx context.Context, tx *sql.Tx, bookingID string) (payment.Payment, error)
+	RefundPaymentTx(ctx context.Context, tx *sql.Tx, paymentID int64) error
+	CreateRefundTx(ctx context.Context, tx *sql.Tx, input payment.Refund) (int64, error)
+}
+
+type paymentRepository struct {
+	db *sql.DB
+}
+
+func NewPaymentRepository(db *sql.DB) PaymentRepository {
+	return &paymentRepository{db: db}
+}
+
+func (r *paymentRepository) CreatePaymentTx(ctx context.Context, tx *sql.Tx, input payment.Payment) (int64, error) {
+	query := `
+		INSERT INTO payments (booking_id, provider, authorization_id, capture_id, amount, status, failure_reason)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5, $6, NULLIF($7, ''))
+		RETURNING id
+	`
+	var id int64
+
+	err := tx.QueryRowContext(
+		ctx,
+		query,
+		input.BookingID,
+		input.Provider,
+		input.AuthorizationID,
+		input.CaptureID,
+		input.Amount.Amount,
+		input.Status,
+		input.FailureReason,
+	).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *paymentRepository) GetCapturedByBookingIDTx(ctx context.Context, tx *sql.Tx, bookingID string) (payment.Payment, error) {
+	query := `
+		SELECT p.id, p.booking_id, p.provider, p.authorization_id, p.capture_id, p.amount, b.currency, p.status, p.created_at
+		FROM payments p
+		JOIN bookings b ON b.id = p.booking_id
+		WHERE p.booking_id = $1 AND p.status = 'CAPTURED'
+		ORDER BY p.created_at DESC
+		LIMIT 1
+		FOR UPDATE OF p
+	`
+	var p payment.Payment
+
+	err := tx.QueryRowContext(ctx, query, bookingID).Scan(
+		&p.ID,
+		&p.BookingID,
+		&p.Provider,
+		&p.AuthorizationID,
+		&p.CaptureID,
+		&p.Amount.Amount,
+		&p.Amount.Currency,
+		&p.Status,
+		&p.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return payment.Payment{}, errs.ErrPaymentNotFound
+		}
+		return payment.Payment{}, err
+	}
+	return p, nil
+}
+
+func (r *paymentRepository) RefundPaymentTx(ctx context.Context, tx *sql.Tx, paymentID int64) error {
+	query := `UPDATE payments SET status = 'REFUNDED' WHERE id = $1`
+
+	res, err := tx.ExecContext(ctx, query, paymentID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errs.ErrPaymentNotFound
+	}
+	return nil
+}
+
+func (r *paymentRepository) CreateRefundTx(ctx context.Context, tx *sql.Tx, input payment.Refund) (int64, error) {
+	query := `
+		INSERT INTO refunds (booking_id, payment_id, amount, percent, provider_refund_id, status)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)
+		RETURNING id
+	`
+	var id int64
+
+	err := tx.QueryRowContext(
+		ctx,
+		query,
+		input.BookingID,
+		input.PaymentID,
+		input.Amount.Amount,
+		input.Percent,
+		input.ProviderRefundID,
+		input.Status,
+	).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+const pendingRefundColumns = `
+	r.id, r.booking_id, r.payment_id, r.amount, b.currency, r.percent, r.status, p.capture_id, r.created_at
+`
+
+// GetPendingRefunds : refunds not yet sent to the gateway, oldest first
+func (r *paymentRepository) GetPendingRefunds(ctx context.Context, limit int) ([]payment.Refund, error) {
+	query := `
+		SELECT ` + pendingRefundColumns + `
+		FROM refunds r
+		JOIN payments p ON p.id = r.payment_id
+		JOIN bookings b ON b.id = r.booking_id
+		WHERE r.status = 'PENDING'
+		ORDER BY r.created_at
+		LIMIT $1
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanRefunds(rows)
+}
+
+func (r *paymentRepository) GetPendingRefundsByBookingID(ctx context.Context, bookingID string) ([]payment.Refund, error) {
+	query := `
+		SELECT ` + pendingRefundColumns + `
+		FROM refunds r
+		JOIN payments p ON p.id = r.payment_id
+		JOIN bookings b ON b.id = r.booking_id
+		WHERE r.booking_id = $1 AND r.status = 'PENDING'
+		ORDER BY r.created_at
+	`
+	rows, err := r.db.QueryContext(ctx, query, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	return scanRefunds(rows)
+}
+
+// CompleteRefund : record the gateway refund, a refund that is already COMPLETED is left as it is
+func (r *paymentRepository) CompleteRefund(ctx context.Context, refundID int64, providerRefundID string) error {
+	query := `
+		UPDATE refunds SET status = 'COMPLETED', provider_refund_id = $2
+		WHERE id = $1 AND status = 'PENDING'
+	`
+	_, err := r.db.ExecContext(ctx, query, refundID, providerRefundID)
+	return err
+}
+
+func scanRefunds(rows *sql.Rows) ([]payment.Refund, error) {
+	defer rows.Close()
+
+	var refunds []payment.Refund
+	for rows.Next() {
+		var rf payment.Refund
+		err := rows.Scan(
+			&rf.ID,
+			&rf.BookingID,
+			&rf.PaymentID,
+			&rf.Amount.Amount,
+			&rf.Amount.Currency,
+			&rf.Percent,
+			&rf.Status,
+			&rf.CaptureID,
+			&rf.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		refunds = append(refunds, rf)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
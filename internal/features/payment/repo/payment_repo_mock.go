@@ -0,0 +1,140 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: payment_repo.go
+
+// Package paymentrepo is a generated GoMock package.
+package paymentrepo
+
+import (
+	context "context"
+	sql "database/sql"
+	reflect "reflect"
+
+	payment "github.com/codepnw/stdlib-ticket-system/internal/features/payment"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPaymentRepository is a mock of PaymentRepository interface.
+type MockPaymentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPaymentRepositoryMockRecorder
+}
+
+// MockPaymentRepositoryMockRecorder is the mock recorder for MockPaymentRepository.
+type MockPaymentRepositoryMockRecorder struct {
+	mock *MockPaymentRepository
+}
+
+// NewMockPaymentRepository creates a new mock instance.
+func NewMockPaymentRepository(ctrl *gomock.Controller) *MockPaymentRepository {
+	mock := &MockPaymentRepository{ctrl: ctrl}
+	mock.recorder = &MockPaymentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPaymentRepository) EXPECT() *MockPaymentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CompleteRefund mocks base method.
+func (m *MockPaymentRepository) CompleteRefund(ctx context.Context, refundID int64, providerRefundID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteRefund", ctx, refundID, providerRefundID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteRefund indicates an expected call of CompleteRefund.
+func (mr *MockPaymentRepositoryMockRecorder) CompleteRefund(ctx, refundID, providerRefundID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteRefund", reflect.TypeOf((*MockPaymentRepository)(nil).CompleteRefund), ctx, refundID, providerRefundID)
+}
+
+// CreatePaymentTx mocks base method.
+func (m *MockPaymentRepository) CreatePaymentTx(ctx context.Context, tx *sql.Tx, input payment.Payment) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePaymentTx", ctx, tx, input)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePaymentTx indicates an expected call of CreatePaymentTx.
+func (mr *MockPaymentRepositoryMockRecorder) CreatePaymentTx(ctx, tx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePaymentTx", reflect.TypeOf((*MockPaymentRepository)(nil).CreatePaymentTx), ctx, tx, input)
+}
+
+// CreateRefundTx mocks base method.
+func (m *MockPaymentRepository) CreateRefundTx(ctx context.Context, tx *sql.Tx, input payment.Refund) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefundTx", ctx, tx, input)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRefundTx indicates an expected call of CreateRefundTx.
+func (mr *MockPaymentRepositoryMockRecorder) CreateRefundTx(ctx, tx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefundTx", reflect.TypeOf((*MockPaymentRepository)(nil).CreateRefundTx), ctx, tx, input)
+}
+
+// GetCapturedByBookingIDTx mocks base method.
+func (m *MockPaymentRepository) GetCapturedByBookingIDTx(ctx context.Context, tx *sql.Tx, bookingID string) (payment.Payment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCapturedByBookingIDTx", ctx, tx, bookingID)
+	ret0, _ := ret[0].(payment.Payment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCapturedByBookingIDTx indicates an expected call of GetCapturedByBookingIDTx.
+func (mr *MockPaymentRepositoryMockRecorder) GetCapturedByBookingIDTx(ctx, tx, bookingID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCapturedByBookingIDTx", reflect.TypeOf((*MockPaymentRepository)(nil).GetCapturedByBookingIDTx), ctx, tx, bookingID)
+}
+
+// GetPendingRefunds mocks base method.
+func (m *MockPaymentRepository) GetPendingRefunds(ctx context.Context, limit int) ([]payment.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingRefunds", ctx, limit)
+	ret0, _ := ret[0].([]payment.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingRefunds indicates an expected call of GetPendingRefunds.
+func (mr *MockPaymentRepositoryMockRecorder) GetPendingRefunds(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingRefunds", reflect.TypeOf((*MockPaymentRepository)(nil).GetPendingRefunds), ctx, limit)
+}
+
+// GetPendingRefundsByBookingID mocks base method.
+func (m *MockPaymentRepository) GetPendingRefundsByBookingID(ctx context.Context, bookingID string) ([]payment.Refund, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingRefundsByBookingID", ctx, bookingID)
+	ret0, _ := ret[0].([]payment.Refund)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingRefundsByBookingID indicates an expected call of GetPendingRefundsByBookingID.
+func (mr *MockPaymentRepositoryMockRecorder) GetPendingRefundsByBookingID(ctx, bookingID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingRefundsByBookingID", reflect.TypeOf((*MockPaymentRepository)(nil).GetPendingRefundsByBookingID), ctx, bookingID)
+}
+
+// RefundPaymentTx mocks base method.
+func (m *MockPaymentRepository) RefundPaymentTx(ctx context.Context, tx *sql.Tx, paymentID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefundPaymentTx", ctx, tx, paymentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefundPaymentTx indicates an expected call of RefundPaymentTx.
+func (mr *MockPaymentRepositoryMockRecorder) RefundPaymentTx(ctx, tx, paymentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundPaymentTx", reflect.TypeOf((*MockPaymentRepository)(nil).RefundPaymentTx), ctx, tx, paymentID)
+}
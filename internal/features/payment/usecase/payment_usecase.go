@@ -0,0 +1,315 @@
+package paymentusecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/authcontext"
+	"github.com/codepnw/stdlib-ticket-system/internal/config"
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+	bookingrepo "github.com/codepnw/stdlib-ticket-system/internal/features/booking/repo"
+	bookingusecase "github.com/codepnw/stdlib-ticket-system/internal/features/booking/usecase"
+	eventrepo "github.com/codepnw/stdlib-ticket-system/internal/features/event/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/payment"
+	paymentgateway "github.com/codepnw/stdlib-ticket-system/internal/features/payment/gateway"
+	paymentrepo "github.com/codepnw/stdlib-ticket-system/internal/features/payment/repo"
+	seatrepo "github.com/codepnw/stdlib-ticket-system/internal/features/seat/repo"
+	"github.com/codepnw/stdlib-ticket-system/pkg/database"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+)
+
+type PaymentUsecase interface {
+	PayBooking(ctx context.Context, bookingID, token string) (displayPayment, error)
+	// RefundTx : record a PENDING refund of part of a paid booking inside the caller's transaction
+	RefundTx(ctx context.Context, tx *sql.Tx, bookData booking.Booking, amount money.Money, closePayment bool) (money.Money, error)
+	// SendRefunds : send the PENDING refunds of a booking once the caller has committed
+	SendRefunds(ctx context.Context, bookingID string) error
+	// SettleRefunds : send every PENDING refund, run by a background worker
+	SettleRefunds(ctx context.Context) error
+}
+
+// refundBatchSize : max refunds sent per sweep
+const refundBatchSize = 100
+
+type paymentUsecase struct {
+	tx          database.TxManager
+	gateway     paymentgateway.PaymentGateway
+	paymentRepo paymentrepo.PaymentRepository
+	bookRepo    bookingrepo.BookingRepository
+	seatRepo    seatrepo.SeatRepository
+	eventRepo   eventrepo.EventRepository
+	offerer     bookingusecase.SeatOfferer
+	notifier    bookingusecase.SeatNotifier
+}
+
+func NewPaymentUsecase(tx database.TxManager, gateway paymentgateway.PaymentGateway, paymentRepo paymentrepo.PaymentRepository, bookRepo bookingrepo.BookingRepository, seatRepo seatrepo.SeatRepository, eventRepo eventrepo.EventRepository, offerer bookingusecase.SeatOfferer, notifier bookingusecase.SeatNotifier) PaymentUsecase {
+	return &paymentUsecase{
+		tx:          tx,
+		gateway:     gateway,
+		paymentRepo: paymentRepo,
+		bookRepo:    bookRepo,
+		seatRepo:    seatRepo,
+		eventRepo:   eventRepo,
+		offerer:     offerer,
+		notifier:    notifier,
+	}
+}
+
+type displayPayment struct {
+	BookingID string      `json:"booking_id"`
+	Provider  string      `json:"provider"`
+	CaptureID string      `json:"capture_id"`
+	Amount    money.Money `json:"amount"`
+	Status    string      `json:"status"`
+}
+
+func (u *paymentUsecase) PayBooking(ctx context.Context, bookingID, token string) (displayPayment, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	userID := authcontext.GetUserID(ctx)
+
+	// 1. Check Booking ID
+	bookData, err := u.bookRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return displayPayment{}, err
+	}
+	// 2. Check Ownership
+	if userID != bookData.UserID {
+		return displayPayment{}, errs.ErrBookingNotOwned
+	}
+	// 3. Check Status
+	if err := booking.Transition(bookData.Status, booking.StatusPaid); err != nil {
+		return displayPayment{}, err
+	}
+	// 4. Check Hold Expiry
+	if !bookData.ExpiresAt.IsZero() && time.Now().After(bookData.ExpiresAt) {
+		return displayPayment{}, errs.ErrBookingExpired
+	}
+
+	// 5. Nothing to charge, a fully discounted or free booking is paid without the gateway
+	if !bookData.TotalAmount.IsPositive() {
+		return u.payFree(ctx, bookData)
+	}
+
+	// 6. Authorize & Capture
+	auth, err := u.gateway.Authorize(ctx, paymentgateway.AuthorizeReq{
+		Reference: paymentgateway.AttemptReference(bookData.ID),
+		Amount:    bookData.TotalAmount,
+		Token:     token,
+	})
+	if err != nil {
+		return displayPayment{}, u.failPayment(ctx, bookData, "", err)
+	}
+
+	capture, err := u.gateway.Capture(ctx, auth.ID, bookData.TotalAmount)
+	if err != nil {
+		return displayPayment{}, u.failPayment(ctx, bookData, auth.ID, err)
+	}
+
+	// 7. Mark Paid & Sell Seats
+	var sold []int64
+	err = u.tx.WithTx(ctx, func(tx *sql.Tx) error {
+		if err := u.bookRepo.UpdateStatusTx(ctx, tx, bookData.ID, booking.StatusPending, booking.StatusPaid); err != nil {
+			return err
+		}
+
+		var err error
+		sold, err = u.seatRepo.SellSeatsTx(ctx, tx, bookData.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = u.paymentRepo.CreatePaymentTx(ctx, tx, payment.Payment{
+			BookingID:       bookData.ID,
+			Provider:        u.gateway.Name(),
+			AuthorizationID: auth.ID,
+			CaptureID:       capture.ID,
+			Amount:          capture.Amount,
+			Status:          payment.StatusCaptured,
+		})
+		return err
+	})
+	if err != nil {
+		// Money was taken but the booking could not be paid, give it back
+		if _, rfErr := u.gateway.Refund(ctx, "reversal-"+capture.ID, capture.ID, capture.Amount); rfErr != nil {
+			log.Printf("refund capture %s failed: %v", capture.ID, rfErr)
+		}
+		return displayPayment{}, err
+	}
+	u.notifier.SeatsChanged(ctx, bookData.EventID, sold)
+
+	return displayPayment{
+		BookingID: bookData.ID,
+		Provider:  u.gateway.Name(),
+		CaptureID: capture.ID,
+		Amount:    capture.Amount,
+		Status:    string(booking.StatusPaid),
+	}, nil
+}
+
+// payFree : mark a zero total booking PAID and sell its seats, the gateway rejects a zero charge
+func (u *paymentUsecase) payFree(ctx context.Context, bookData booking.Booking) (displayPayment, error) {
+	var sold []int64
+	err := u.tx.WithTx(ctx, func(tx *sql.Tx) error {
+		if err := u.bookRepo.UpdateStatusTx(ctx, tx, bookData.ID, booking.StatusPending, booking.StatusPaid); err != nil {
+			return err
+		}
+
+		var err error
+		sold, err = u.seatRepo.SellSeatsTx(ctx, tx, bookData.ID)
+		return err
+	})
+	if err != nil {
+		return displayPayment{}, err
+	}
+	u.notifier.SeatsChanged(ctx, bookData.EventID, sold)
+
+	return displayPayment{
+		BookingID: bookData.ID,
+		Amount:    bookData.TotalAmount,
+		Status:    string(booking.StatusPaid),
+	}, nil
+}
+
+// failPayment : mark the booking FAILED, release its seats to the waitlist and record the attempt
+func (u *paymentUsecase) failPayment(ctx context.Context, bookData booking.Booking, authorizationID string, cause error) error {
+	var released []int64
+	err := u.tx.WithTx(ctx, func(tx *sql.Tx) error {
+		if err := u.bookRepo.UpdateStatusTx(ctx, tx, bookData.ID, booking.StatusPending, booking.StatusFailed); err != nil {
+			return err
+		}
+
+		var err error
+		released, err = u.seatRepo.CancelSeatsTx(ctx, tx, bookData.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := u.offerer.OfferSeatsTx(ctx, tx, bookData.EventID); err != nil {
+			return err
+		}
+
+		_, err = u.paymentRepo.CreatePaymentTx(ctx, tx, payment.Payment{
+			BookingID:       bookData.ID,
+			Provider:        u.gateway.Name(),
+			AuthorizationID: authorizationID,
+			Amount:          bookData.TotalAmount,
+			Status:          payment.StatusFailed,
+			FailureReason:   cause.Error(),
+		})
+		return err
+	})
+	if err != nil {
+		log.Printf("fail booking %s failed: %v", bookData.ID, err)
+		return err
+	}
+	u.notifier.SeatsChanged(ctx, bookData.EventID, released)
+	return fmt.Errorf("%w: %v", errs.ErrPaymentFailed, cause)
+}
+
+// RefundTx : apply the event refund policy to amount and record it as a PENDING refund.
+// The gateway is only called by SendRefunds after the caller commits, so a rolled back
+// booking change never gives money back. closePayment marks the payment REFUNDED when
+// the whole booking is cancelled
+func (u *paymentUsecase) RefundTx(ctx context.Context, tx *sql.Tx, bookData booking.Booking, amount money.Money, closePayment bool) (money.Money, error) {
+	// 1. Refund Percent from Policy
+	policy, err := u.eventRepo.GetRefundPolicy(ctx, bookData.EventID)
+	if err != nil {
+		if errors.Is(err, errs.ErrRefundPolicyNotFound) {
+			return money.Money{}, errs.ErrBookingNotRefundable
+		}
+		return money.Money{}, err
+	}
+
+	eventData, err := u.eventRepo.GetEventByID(ctx, bookData.EventID)
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	percent := policy.RefundPercent(eventData.EventDate, time.Now())
+	if percent == 0 {
+		return money.Money{}, errs.ErrBookingNotRefundable
+	}
+	refundAmount := amount.Percent(float64(percent))
+
+	// 2. Captured Payment
+	pay, err := u.paymentRepo.GetCapturedByBookingIDTx(ctx, tx, bookData.ID)
+	if err != nil {
+		if errors.Is(err, errs.ErrPaymentNotFound) {
+			return money.Money{}, errs.ErrBookingNotRefundable
+		}
+		return money.Money{}, err
+	}
+
+	if closePayment {
+		if err := u.paymentRepo.RefundPaymentTx(ctx, tx, pay.ID); err != nil {
+			return money.Money{}, err
+		}
+	}
+
+	// 3. Record the Refund, sent through the gateway after commit
+	_, err = u.paymentRepo.CreateRefundTx(ctx, tx, payment.Refund{
+		BookingID: bookData.ID,
+		PaymentID: pay.ID,
+		Amount:    refundAmount,
+		Percent:   percent,
+		Status:    payment.RefundPending,
+	})
+	if err != nil {
+		return money.Money{}, err
+	}
+	return refundAmount, nil
+}
+
+func (u *paymentUsecase) SendRefunds(ctx context.Context, bookingID string) error {
+	refunds, err := u.paymentRepo.GetPendingRefundsByBookingID(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+	for _, rf := range refunds {
+		if err := u.sendRefund(ctx, rf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (u *paymentUsecase) SettleRefunds(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	refunds, err := u.paymentRepo.GetPendingRefunds(ctx, refundBatchSize)
+	if err != nil {
+		return err
+	}
+
+	var sent int
+	for _, rf := range refunds {
+		if err := u.sendRefund(ctx, rf); err != nil {
+			log.Printf("refund %d for booking %s failed: %v", rf.ID, rf.BookingID, err)
+			continue
+		}
+		sent++
+	}
+
+	if sent > 0 {
+		log.Printf("sent %d pending refunds", sent)
+	}
+	return nil
+}
+
+// sendRefund : the refund row id makes the gateway refund it once, however often it is retried
+func (u *paymentUsecase) sendRefund(ctx context.Context, rf payment.Refund) error {
+	reference := fmt.Sprintf("refund-%d", rf.ID)
+	refund, err := u.gateway.Refund(ctx, reference, rf.CaptureID, rf.Amount)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errs.ErrRefundFailed, err)
+	}
+	return u.paymentRepo.CompleteRefund(ctx, rf.ID, refund.ID)
+}
@@ -0,0 +1,525 @@
+package paymentusecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/authcontext"
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+	bookingrepo "github.com/codepnw/stdlib-ticket-system/internal/features/booking/repo"
+	bookingusecase "github.com/codepnw/stdlib-ticket-system/internal/features/booking/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	eventrepo "github.com/codepnw/stdlib-ticket-system/internal/features/event/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/payment"
+	paymentgateway "github.com/codepnw/stdlib-ticket-system/internal/features/payment/gateway"
+	paymentrepo "github.com/codepnw/stdlib-ticket-system/internal/features/payment/repo"
+	paymentusecase "github.com/codepnw/stdlib-ticket-system/internal/features/payment/usecase"
+	seatrepo "github.com/codepnw/stdlib-ticket-system/internal/features/seat/repo"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var ErrMockDBError = errors.New("db error")
+
+type mockTx struct{}
+
+func (m mockTx) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	return fn(nil)
+}
+
+func (m mockTx) WithRetryTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	return fn(nil)
+}
+
+type mocks struct {
+	payment  *paymentrepo.MockPaymentRepository
+	book     *bookingrepo.MockBookingRepository
+	seat     *seatrepo.MockSeatRepository
+	event    *eventrepo.MockEventRepository
+	offerer  *bookingusecase.MockSeatOfferer
+	notifier *bookingusecase.MockSeatNotifier
+	gateway  paymentgateway.PaymentGateway
+}
+
+func TestPayBooking(t *testing.T) {
+	pendingBooking := booking.Booking{
+		ID:          "mock-uuid-1",
+		UserID:      1,
+		EventID:     10,
+		TotalAmount: money.New(30000, "THB"),
+		Status:      booking.StatusPending,
+		ExpiresAt:   time.Now().Add(10 * time.Minute),
+	}
+
+	type testCase struct {
+		name        string
+		token       string
+		mockFn      func(m mocks)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:  "success",
+			token: paymentgateway.FakeTokenSuccess,
+			mockFn: func(m mocks) {
+				m.book.EXPECT().GetByID(gomock.Any(), pendingBooking.ID).Return(pendingBooking, nil).Times(1)
+
+				m.book.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), pendingBooking.ID, booking.StatusPending, booking.StatusPaid).Return(nil).Times(1)
+
+				m.seat.EXPECT().SellSeatsTx(gomock.Any(), gomock.Any(), pendingBooking.ID).Return([]int64{11}, nil).Times(1)
+
+				m.payment.EXPECT().CreatePaymentTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sql.Tx, input payment.Payment) (int64, error) {
+						assert.Equal(t, payment.StatusCaptured, input.Status)
+						assert.Equal(t, pendingBooking.TotalAmount, input.Amount)
+						return 1, nil
+					}).Times(1)
+
+				m.notifier.EXPECT().SeatsChanged(gomock.Any(), pendingBooking.EventID, []int64{11}).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "success 100 percent code skips the gateway",
+			// A declining token proves the gateway is never called
+			token: paymentgateway.FakeTokenDecline,
+			mockFn: func(m mocks) {
+				data := pendingBooking
+				data.TotalAmount = money.Zero("THB")
+				data.DiscountAmount = money.New(30000, "THB")
+				m.book.EXPECT().GetByID(gomock.Any(), pendingBooking.ID).Return(data, nil).Times(1)
+
+				m.book.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), pendingBooking.ID, booking.StatusPending, booking.StatusPaid).Return(nil).Times(1)
+
+				m.seat.EXPECT().SellSeatsTx(gomock.Any(), gomock.Any(), pendingBooking.ID).Return([]int64{11}, nil).Times(1)
+
+				m.notifier.EXPECT().SeatsChanged(gomock.Any(), pendingBooking.EventID, []int64{11}).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "fail booking not found",
+			token: paymentgateway.FakeTokenSuccess,
+			mockFn: func(m mocks) {
+				m.book.EXPECT().GetByID(gomock.Any(), pendingBooking.ID).Return(booking.Booking{}, errs.ErrBookingNotFound).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotFound,
+		},
+		{
+			name:  "fail other user booking",
+			token: paymentgateway.FakeTokenSuccess,
+			mockFn: func(m mocks) {
+				data := pendingBooking
+				data.UserID = 2
+				m.book.EXPECT().GetByID(gomock.Any(), pendingBooking.ID).Return(data, nil).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotOwned,
+		},
+		{
+			name:  "fail booking already paid",
+			token: paymentgateway.FakeTokenSuccess,
+			mockFn: func(m mocks) {
+				data := pendingBooking
+				data.Status = booking.StatusPaid
+				m.book.EXPECT().GetByID(gomock.Any(), pendingBooking.ID).Return(data, nil).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotPending,
+		},
+		{
+			name:  "fail hold expired",
+			token: paymentgateway.FakeTokenSuccess,
+			mockFn: func(m mocks) {
+				data := pendingBooking
+				data.ExpiresAt = time.Now().Add(-time.Minute)
+				m.book.EXPECT().GetByID(gomock.Any(), pendingBooking.ID).Return(data, nil).Times(1)
+			},
+			expectedErr: errs.ErrBookingExpired,
+		},
+		{
+			name:  "fail declined",
+			token: paymentgateway.FakeTokenDecline,
+			mockFn: func(m mocks) {
+				m.book.EXPECT().GetByID(gomock.Any(), pendingBooking.ID).Return(pendingBooking, nil).Times(1)
+
+				m.book.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), pendingBooking.ID, booking.StatusPending, booking.StatusFailed).Return(nil).Times(1)
+
+				m.seat.EXPECT().CancelSeatsTx(gomock.Any(), gomock.Any(), pendingBooking.ID).Return([]int64{11}, nil).Times(1)
+
+				m.offerer.EXPECT().OfferSeatsTx(gomock.Any(), gomock.Any(), pendingBooking.EventID).Return(nil).Times(1)
+
+				m.notifier.EXPECT().SeatsChanged(gomock.Any(), pendingBooking.EventID, []int64{11}).Times(1)
+
+				m.payment.EXPECT().CreatePaymentTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sql.Tx, input payment.Payment) (int64, error) {
+						assert.Equal(t, payment.StatusFailed, input.Status)
+						return 1, nil
+					}).Times(1)
+			},
+			expectedErr: errs.ErrPaymentFailed,
+		},
+		{
+			name:  "fail capture",
+			token: paymentgateway.FakeTokenCaptureFail,
+			mockFn: func(m mocks) {
+				m.book.EXPECT().GetByID(gomock.Any(), pendingBooking.ID).Return(pendingBooking, nil).Times(1)
+
+				m.book.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), pendingBooking.ID, booking.StatusPending, booking.StatusFailed).Return(nil).Times(1)
+
+				m.seat.EXPECT().CancelSeatsTx(gomock.Any(), gomock.Any(), pendingBooking.ID).Return([]int64{11}, nil).Times(1)
+
+				m.offerer.EXPECT().OfferSeatsTx(gomock.Any(), gomock.Any(), pendingBooking.EventID).Return(nil).Times(1)
+
+				m.notifier.EXPECT().SeatsChanged(gomock.Any(), pendingBooking.EventID, []int64{11}).Times(1)
+
+				m.payment.EXPECT().CreatePaymentTx(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(1), nil).Times(1)
+			},
+			expectedErr: errs.ErrPaymentFailed,
+		},
+		{
+			name:  "fail booking paid concurrently",
+			token: paymentgateway.FakeTokenSuccess,
+			mockFn: func(m mocks) {
+				m.book.EXPECT().GetByID(gomock.Any(), pendingBooking.ID).Return(pendingBooking, nil).Times(1)
+
+				m.book.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), pendingBooking.ID, booking.StatusPending, booking.StatusPaid).Return(&booking.TransitionError{From: booking.StatusPaid, To: booking.StatusPaid}).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotPending,
+		},
+		{
+			name:  "fail sell seats",
+			token: paymentgateway.FakeTokenSuccess,
+			mockFn: func(m mocks) {
+				m.book.EXPECT().GetByID(gomock.Any(), pendingBooking.ID).Return(pendingBooking, nil).Times(1)
+
+				m.book.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), pendingBooking.ID, booking.StatusPending, booking.StatusPaid).Return(nil).Times(1)
+
+				m.seat.EXPECT().SellSeatsTx(gomock.Any(), gomock.Any(), pendingBooking.ID).Return(nil, ErrMockDBError).Times(1)
+			},
+			expectedErr: ErrMockDBError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, m := setup(t)
+
+			// Mock FN
+			tc.mockFn(m)
+
+			ctx := authcontext.SetUserID(context.Background(), int64(1))
+			_, err := uc.PayBooking(ctx, pendingBooking.ID, tc.token)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// recordingGateway : fake gateway that remembers every capture and which captures were refunded
+type recordingGateway struct {
+	paymentgateway.PaymentGateway
+	mu       sync.Mutex
+	captures []string
+	refunded []string
+}
+
+func (g *recordingGateway) Capture(ctx context.Context, authorizationID string, amount money.Money) (paymentgateway.Capture, error) {
+	capture, err := g.PaymentGateway.Capture(ctx, authorizationID, amount)
+	if err == nil {
+		g.mu.Lock()
+		g.captures = append(g.captures, capture.ID)
+		g.mu.Unlock()
+	}
+	return capture, err
+}
+
+func (g *recordingGateway) Refund(ctx context.Context, reference, captureID string, amount money.Money) (paymentgateway.Refund, error) {
+	g.mu.Lock()
+	g.refunded = append(g.refunded, captureID)
+	g.mu.Unlock()
+	return g.PaymentGateway.Refund(ctx, reference, captureID, amount)
+}
+
+func TestPayBookingConcurrent(t *testing.T) {
+	pendingBooking := booking.Booking{
+		ID:          "mock-uuid-1",
+		UserID:      1,
+		EventID:     10,
+		TotalAmount: money.New(30000, "THB"),
+		Status:      booking.StatusPending,
+		ExpiresAt:   time.Now().Add(10 * time.Minute),
+	}
+
+	_, m := setup(t)
+	gw := &recordingGateway{PaymentGateway: paymentgateway.NewFakeGateway()}
+	uc := paymentusecase.NewPaymentUsecase(mockTx{}, gw, m.payment, m.book, m.seat, m.event, m.offerer, m.notifier)
+
+	m.book.EXPECT().GetByID(gomock.Any(), pendingBooking.ID).Return(pendingBooking, nil).Times(2)
+
+	// Only the first attempt moves the booking out of PENDING
+	var claimed atomic.Int32
+	m.book.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), pendingBooking.ID, booking.StatusPending, booking.StatusPaid).DoAndReturn(
+		func(ctx context.Context, tx *sql.Tx, id string, from, to booking.BookingStatus) error {
+			if claimed.Add(1) > 1 {
+				return &booking.TransitionError{From: booking.StatusPaid, To: booking.StatusPaid}
+			}
+			return nil
+		}).Times(2)
+
+	m.seat.EXPECT().SellSeatsTx(gomock.Any(), gomock.Any(), pendingBooking.ID).Return([]int64{11}, nil).Times(1)
+
+	var saved string
+	m.payment.EXPECT().CreatePaymentTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, tx *sql.Tx, input payment.Payment) (int64, error) {
+			saved = input.CaptureID
+			return 1, nil
+		}).Times(1)
+
+	m.notifier.EXPECT().SeatsChanged(gomock.Any(), pendingBooking.EventID, []int64{11}).Times(1)
+
+	ctx := authcontext.SetUserID(context.Background(), int64(1))
+	errCh := make(chan error, 2)
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := uc.PayBooking(ctx, pendingBooking.ID, paymentgateway.FakeTokenSuccess)
+			errCh <- err
+		}()
+	}
+	wg.Wait()
+	close(errCh)
+
+	var failed int
+	for err := range errCh {
+		if err != nil {
+			assert.ErrorIs(t, err, errs.ErrBookingNotPending)
+			failed++
+		}
+	}
+	assert.Equal(t, 1, failed)
+
+	// Each attempt charges on its own capture, the loser refunds only its own
+	assert.Len(t, gw.captures, 2)
+	assert.NotEqual(t, gw.captures[0], gw.captures[1])
+	assert.Len(t, gw.refunded, 1)
+	assert.NotEqual(t, saved, gw.refunded[0])
+}
+
+func TestRefundTx(t *testing.T) {
+	paidBooking := booking.Booking{
+		ID:          "mock-uuid-1",
+		UserID:      1,
+		EventID:     10,
+		TotalAmount: money.New(30000, "THB"),
+		Status:      booking.StatusPaid,
+	}
+	policy := event.RefundPolicy{FullRefundDays: 7, PartialRefundDays: 2, PartialRefundPercent: 50}
+
+	type testCase struct {
+		name           string
+		eventDate      time.Time
+		closePayment   bool
+		mockFn         func(m mocks, eventDate time.Time, closePayment bool)
+		expectedAmount money.Money
+		expectedErr    error
+	}
+
+	// captured : pay through the fake gateway so refunds have a capture to hit
+	captured := func(m mocks) payment.Payment {
+		auth, err := m.gateway.Authorize(context.Background(), paymentgateway.AuthorizeReq{
+			Reference: paidBooking.ID,
+			Amount:    paidBooking.TotalAmount,
+			Token:     paymentgateway.FakeTokenSuccess,
+		})
+		assert.NoError(t, err)
+		capture, err := m.gateway.Capture(context.Background(), auth.ID, paidBooking.TotalAmount)
+		assert.NoError(t, err)
+		return payment.Payment{ID: 1, BookingID: paidBooking.ID, CaptureID: capture.ID, Amount: capture.Amount, Status: payment.StatusCaptured}
+	}
+
+	testCases := []testCase{
+		{
+			name:         "success full refund",
+			eventDate:    time.Now().Add(10 * 24 * time.Hour),
+			closePayment: true,
+			mockFn: func(m mocks, eventDate time.Time, closePayment bool) {
+				m.event.EXPECT().GetRefundPolicy(gomock.Any(), paidBooking.EventID).Return(policy, nil).Times(1)
+				m.event.EXPECT().GetEventByID(gomock.Any(), paidBooking.EventID).Return(event.Event{ID: paidBooking.EventID, EventDate: eventDate}, nil).Times(1)
+
+				pay := captured(m)
+				m.payment.EXPECT().GetCapturedByBookingIDTx(gomock.Any(), gomock.Any(), paidBooking.ID).Return(pay, nil).Times(1)
+				m.payment.EXPECT().RefundPaymentTx(gomock.Any(), gomock.Any(), pay.ID).Return(nil).Times(1)
+				m.payment.EXPECT().CreateRefundTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sql.Tx, input payment.Refund) (int64, error) {
+						assert.Equal(t, 100, input.Percent)
+						assert.Equal(t, money.New(30000, "THB"), input.Amount)
+						assert.Equal(t, payment.RefundPending, input.Status)
+						return 1, nil
+					}).Times(1)
+			},
+			expectedAmount: money.New(30000, "THB"),
+			expectedErr:    nil,
+		},
+		{
+			name:         "success partial refund keeps payment open",
+			eventDate:    time.Now().Add(3 * 24 * time.Hour),
+			closePayment: false,
+			mockFn: func(m mocks, eventDate time.Time, closePayment bool) {
+				m.event.EXPECT().GetRefundPolicy(gomock.Any(), paidBooking.EventID).Return(policy, nil).Times(1)
+				m.event.EXPECT().GetEventByID(gomock.Any(), paidBooking.EventID).Return(event.Event{ID: paidBooking.EventID, EventDate: eventDate}, nil).Times(1)
+
+				pay := captured(m)
+				m.payment.EXPECT().GetCapturedByBookingIDTx(gomock.Any(), gomock.Any(), paidBooking.ID).Return(pay, nil).Times(1)
+				m.payment.EXPECT().CreateRefundTx(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(1), nil).Times(1)
+			},
+			expectedAmount: money.New(15000, "THB"),
+			expectedErr:    nil,
+		},
+		{
+			name:      "fail too close to event",
+			eventDate: time.Now().Add(24 * time.Hour),
+			mockFn: func(m mocks, eventDate time.Time, closePayment bool) {
+				m.event.EXPECT().GetRefundPolicy(gomock.Any(), paidBooking.EventID).Return(policy, nil).Times(1)
+				m.event.EXPECT().GetEventByID(gomock.Any(), paidBooking.EventID).Return(event.Event{ID: paidBooking.EventID, EventDate: eventDate}, nil).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotRefundable,
+		},
+		{
+			name:      "fail no refund policy",
+			eventDate: time.Now().Add(10 * 24 * time.Hour),
+			mockFn: func(m mocks, eventDate time.Time, closePayment bool) {
+				m.event.EXPECT().GetRefundPolicy(gomock.Any(), paidBooking.EventID).Return(event.RefundPolicy{}, errs.ErrRefundPolicyNotFound).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotRefundable,
+		},
+		{
+			name:      "fail no captured payment",
+			eventDate: time.Now().Add(10 * 24 * time.Hour),
+			mockFn: func(m mocks, eventDate time.Time, closePayment bool) {
+				m.event.EXPECT().GetRefundPolicy(gomock.Any(), paidBooking.EventID).Return(policy, nil).Times(1)
+				m.event.EXPECT().GetEventByID(gomock.Any(), paidBooking.EventID).Return(event.Event{ID: paidBooking.EventID, EventDate: eventDate}, nil).Times(1)
+				m.payment.EXPECT().GetCapturedByBookingIDTx(gomock.Any(), gomock.Any(), paidBooking.ID).Return(payment.Payment{}, errs.ErrPaymentNotFound).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotRefundable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, m := setup(t)
+
+			// Mock FN
+			tc.mockFn(m, tc.eventDate, tc.closePayment)
+
+			amount, err := uc.RefundTx(context.Background(), nil, paidBooking, paidBooking.TotalAmount, tc.closePayment)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedAmount, amount)
+			}
+		})
+	}
+}
+
+func TestSendRefunds(t *testing.T) {
+	bookingID := "mock-uuid-1"
+
+	type testCase struct {
+		name        string
+		mockFn      func(m mocks)
+		expectedErr error
+	}
+
+	// pending : a PENDING refund against a capture on the fake gateway
+	pending := func(m mocks) payment.Refund {
+		amount := money.New(30000, "THB")
+		auth, err := m.gateway.Authorize(context.Background(), paymentgateway.AuthorizeReq{
+			Reference: bookingID,
+			Amount:    amount,
+			Token:     paymentgateway.FakeTokenSuccess,
+		})
+		assert.NoError(t, err)
+		capture, err := m.gateway.Capture(context.Background(), auth.ID, amount)
+		assert.NoError(t, err)
+		return payment.Refund{ID: 7, BookingID: bookingID, PaymentID: 1, Amount: amount, Status: payment.RefundPending, CaptureID: capture.ID}
+	}
+
+	testCases := []testCase{
+		{
+			name: "success",
+			mockFn: func(m mocks) {
+				rf := pending(m)
+				m.payment.EXPECT().GetPendingRefundsByBookingID(gomock.Any(), bookingID).Return([]payment.Refund{rf}, nil).Times(1)
+				m.payment.EXPECT().CompleteRefund(gomock.Any(), rf.ID, gomock.Any()).Return(nil).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "success nothing pending",
+			mockFn: func(m mocks) {
+				m.payment.EXPECT().GetPendingRefundsByBookingID(gomock.Any(), bookingID).Return(nil, nil).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "fail gateway refund stays pending",
+			mockFn: func(m mocks) {
+				rf := payment.Refund{ID: 7, BookingID: bookingID, Amount: money.New(30000, "THB"), Status: payment.RefundPending, CaptureID: "unknown"}
+				m.payment.EXPECT().GetPendingRefundsByBookingID(gomock.Any(), bookingID).Return([]payment.Refund{rf}, nil).Times(1)
+			},
+			expectedErr: errs.ErrRefundFailed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, m := setup(t)
+
+			// Mock FN
+			tc.mockFn(m)
+
+			err := uc.SendRefunds(context.Background(), bookingID)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func setup(t *testing.T) (paymentusecase.PaymentUsecase, mocks) {
+	ctrl := gomock.NewController(t)
+
+	m := mocks{
+		payment:  paymentrepo.NewMockPaymentRepository(ctrl),
+		book:     bookingrepo.NewMockBookingRepository(ctrl),
+		seat:     seatrepo.NewMockSeatRepository(ctrl),
+		event:    eventrepo.NewMockEventRepository(ctrl),
+		offerer:  bookingusecase.NewMockSeatOfferer(ctrl),
+		notifier: bookingusecase.NewMockSeatNotifier(ctrl),
+		gateway:  paymentgateway.NewFakeGateway(),
+	}
+	uc := paymentusecase.NewPaymentUsecase(mockTx{}, m.gateway, m.payment, m.book, m.seat, m.event, m.offerer, m.notifier)
+
+	return uc, m
+}
@@ -0,0 +1,60 @@
+package promohandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/promo"
+	promousecase "github.com/codepnw/stdlib-ticket-system/internal/features/promo/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/helper"
+	"github.com/codepnw/stdlib-ticket-system/pkg/utils"
+)
+
+type promoHandler struct {
+	uc promousecase.PromoUsecase
+}
+
+func NewPromoHandler(uc promousecase.PromoUsecase) *promoHandler {
+	return &promoHandler{uc: uc}
+}
+
+func (h *promoHandler) CreatePromo(w http.ResponseWriter, r *http.Request) {
+	var req promo.CreatePromoReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := utils.Validate(&req); err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.uc.CreatePromo(r.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrInvalidPromo):
+			helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errs.ErrEventNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, errs.ErrPromoCodeExists):
+			helper.ErrorResponse(w, http.StatusConflict, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	helper.SuccessResponse(w, http.StatusCreated, "promo created", data)
+}
+
+func (h *promoHandler) GetAllPromos(w http.ResponseWriter, r *http.Request) {
+	data, err := h.uc.GetAllPromos(r.Context())
+	if err != nil {
+		helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	helper.SuccessResponse(w, http.StatusOK, "", data)
+}
@@ -0,0 +1,131 @@
+package promo
+
+import (
+	"strings"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+)
+
+type DiscountType string
+
+const (
+	DiscountPercent DiscountType = "PERCENT"
+	DiscountFixed   DiscountType = "FIXED"
+)
+
+// Promo : a discount code, EventID and Zone narrow what it applies to, 0 caps mean no limit.
+// PERCENT codes set Percent, FIXED codes set FixedAmount.
+type Promo struct {
+	ID             int64        `json:"id" db:"id"`
+	Code           string       `json:"code" db:"code"`
+	DiscountType   DiscountType `json:"discount_type" db:"discount_type"`
+	Percent        float64      `json:"percent,omitempty" db:"percent"`
+	FixedAmount    *money.Money `json:"fixed_amount,omitempty" db:"fixed_amount"`
+	EventID        *int64       `json:"event_id,omitempty" db:"event_id"`
+	Zone           string       `json:"zone,omitempty" db:"zone"`
+	StartsAt       *time.Time   `json:"starts_at,omitempty" db:"starts_at"`
+	EndsAt         *time.Time   `json:"ends_at,omitempty" db:"ends_at"`
+	MaxUses        int          `json:"max_uses" db:"max_uses"`
+	MaxUsesPerUser int          `json:"max_uses_per_user" db:"max_uses_per_user"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+}
+
+// Redemption : one use of a code, it stops counting once the booking is no longer PENDING or PAID
+type Redemption struct {
+	PromoID   int64       `db:"promo_id"`
+	UserID    int64       `db:"user_id"`
+	BookingID string      `db:"booking_id"`
+	Discount  money.Money `db:"discount"`
+}
+
+// NormalizeCode : codes are matched case-insensitively, stored upper case
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Check : the code can be used on the event at `at`, the window is [StartsAt, EndsAt)
+func (p Promo) Check(eventID int64, at time.Time) error {
+	if p.EventID != nil && *p.EventID != eventID {
+		return errs.ErrPromoNotApplicable
+	}
+	if p.StartsAt != nil && at.Before(*p.StartsAt) {
+		return errs.ErrPromoNotActive
+	}
+	if p.EndsAt != nil && !at.Before(*p.EndsAt) {
+		return errs.ErrPromoNotActive
+	}
+	return nil
+}
+
+// CheckUsage : one more use stays within the caps, given the uses so far overall and by this user
+func (p Promo) CheckUsage(used, usedByUser int) error {
+	if p.MaxUses > 0 && used >= p.MaxUses {
+		return errs.ErrPromoUsedUp
+	}
+	if p.MaxUsesPerUser > 0 && usedByUser >= p.MaxUsesPerUser {
+		return errs.ErrPromoUserLimit
+	}
+	return nil
+}
+
+// AppliesToZone : codes without a zone cover every seat of their scope
+func (p Promo) AppliesToZone(zone string) bool {
+	return p.Zone == "" || p.Zone == zone
+}
+
+// Discount : money off eligible, the sum of the covered seat prices.
+// A fixed discount never goes below zero and percentages round down to the minor unit.
+// Fixed codes only apply to events priced in their currency.
+func (p Promo) Discount(eligible money.Money) (money.Money, error) {
+	if !eligible.IsPositive() {
+		return money.Money{}, errs.ErrPromoNotApplicable
+	}
+	if p.DiscountType == DiscountPercent {
+		return eligible.PercentDown(p.Percent), nil
+	}
+	if p.FixedAmount == nil {
+		return money.Money{}, errs.ErrPromoNotApplicable
+	}
+	cmp, err := p.FixedAmount.Cmp(eligible)
+	if err != nil {
+		return money.Money{}, errs.ErrPromoNotApplicable
+	}
+	if cmp > 0 {
+		return eligible, nil
+	}
+	return *p.FixedAmount, nil
+}
+
+// CreatePromoReq : Zone needs EventID, PERCENT codes take Percent and FIXED codes FixedAmount
+type CreatePromoReq struct {
+	Code           string       `json:"code" validate:"required,max=50"`
+	DiscountType   DiscountType `json:"discount_type" validate:"required,oneof=PERCENT FIXED"`
+	Percent        float64      `json:"percent" validate:"gte=0,lte=100"`
+	FixedAmount    *money.Money `json:"fixed_amount"`
+	EventID        *int64       `json:"event_id"`
+	Zone           string       `json:"zone" validate:"max=50"`
+	StartsAt       *time.Time   `json:"starts_at"`
+	EndsAt         *time.Time   `json:"ends_at"`
+	MaxUses        int          `json:"max_uses" validate:"gte=0"`
+	MaxUsesPerUser int          `json:"max_uses_per_user" validate:"gte=0"`
+}
+
+// Valid : the rules the tags cannot express
+func (r CreatePromoReq) Valid() bool {
+	switch r.DiscountType {
+	case DiscountPercent:
+		if r.Percent <= 0 || r.Percent > 100 || r.FixedAmount != nil {
+			return false
+		}
+	case DiscountFixed:
+		if r.FixedAmount == nil || !r.FixedAmount.IsPositive() || r.Percent != 0 {
+			return false
+		}
+	}
+	if r.Zone != "" && r.EventID == nil {
+		return false
+	}
+	return r.StartsAt == nil || r.EndsAt == nil || r.StartsAt.Before(*r.EndsAt)
+}
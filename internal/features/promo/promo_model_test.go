@@ -0,0 +1,82 @@
+package promo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/promo"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	eventID := int64(10)
+	starts := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	ends := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	code := promo.Promo{EventID: &eventID, StartsAt: &starts, EndsAt: &ends}
+
+	type testCase struct {
+		name        string
+		eventID     int64
+		at          time.Time
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{name: "success from the start", eventID: 10, at: starts},
+		{name: "fail other event", eventID: 11, at: starts, expectedErr: errs.ErrPromoNotApplicable},
+		{name: "fail not started", eventID: 10, at: starts.Add(-time.Second), expectedErr: errs.ErrPromoNotActive},
+		{name: "fail ended", eventID: 10, at: ends, expectedErr: errs.ErrPromoNotActive},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := code.Check(tc.eventID, tc.at)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDiscount(t *testing.T) {
+	fixed := func(amount int64, currency string) *money.Money {
+		m := money.New(amount, currency)
+		return &m
+	}
+
+	type testCase struct {
+		name        string
+		code        promo.Promo
+		eligible    money.Money
+		expected    money.Money
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{name: "percent", code: promo.Promo{DiscountType: promo.DiscountPercent, Percent: 15}, eligible: money.New(20000, "THB"), expected: money.New(3000, "THB")},
+		{name: "percent rounds down to the satang", code: promo.Promo{DiscountType: promo.DiscountPercent, Percent: 12.5}, eligible: money.New(9999, "THB"), expected: money.New(1249, "THB")},
+		{name: "fixed", code: promo.Promo{DiscountType: promo.DiscountFixed, FixedAmount: fixed(2000, "THB")}, eligible: money.New(15000, "THB"), expected: money.New(2000, "THB")},
+		{name: "fixed capped at the price", code: promo.Promo{DiscountType: promo.DiscountFixed, FixedAmount: fixed(20000, "THB")}, eligible: money.New(15000, "THB"), expected: money.New(15000, "THB")},
+		{name: "fail fixed in another currency", code: promo.Promo{DiscountType: promo.DiscountFixed, FixedAmount: fixed(2000, "USD")}, eligible: money.New(15000, "THB"), expectedErr: errs.ErrPromoNotApplicable},
+		{name: "fail nothing eligible", code: promo.Promo{DiscountType: promo.DiscountFixed, FixedAmount: fixed(2000, "THB")}, eligible: money.Zero("THB"), expectedErr: errs.ErrPromoNotApplicable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			discount, err := tc.code.Discount(tc.eligible)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, discount)
+			}
+		})
+	}
+}
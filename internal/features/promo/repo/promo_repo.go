@@ -0,0 +1,169 @@
+package promorepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/promo"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+	"github.com/lib/pq"
+)
+
+const promoColumns = `
+	id, code, discount_type, COALESCE(percent, 0), fixed_amount, currency, event_id, COALESCE(zone, ''),
+	starts_at, ends_at, max_uses, max_uses_per_user, created_at
+`
+
+//go:generate mockgen -source=promo_repo.go -destination=promo_repo_mock.go -package=promorepo
+type PromoRepository interface {
+	CreatePromo(ctx context.Context, input promo.Promo) (int64, error)
+	GetAll(ctx context.Context) ([]promo.Promo, error)
+
+	// Transaction
+	GetByCodeForUpdateTx(ctx context.Context, tx *sql.Tx, code string) (promo.Promo, error)
+	CountRedemptionsTx(ctx context.Context, tx *sql.Tx, promoID, userID int64) (used int, usedByUser int, err error)
+	RedeemTx(ctx context.Context, tx *sql.Tx, input promo.Redemption) error
+}
+
+type promoRepository struct {
+	db *sql.DB
+}
+
+func NewPromoRepository(db *sql.DB) PromoRepository {
+	return &promoRepository{db: db}
+}
+
+func (r *promoRepository) CreatePromo(ctx context.Context, input promo.Promo) (int64, error) {
+	query := `
+		INSERT INTO promo_codes (code, discount_type, percent, fixed_amount, currency, event_id, zone, starts_at, ends_at, max_uses, max_uses_per_user)
+		VALUES ($1, $2, NULLIF($3::DECIMAL, 0), $4, $5, $6, NULLIF($7, ''), $8, $9, $10, $11) RETURNING id
+	`
+	var fixedAmount sql.NullInt64
+	var currency sql.NullString
+	if input.FixedAmount != nil {
+		fixedAmount = sql.NullInt64{Int64: input.FixedAmount.Amount, Valid: true}
+		currency = sql.NullString{String: input.FixedAmount.Currency, Valid: true}
+	}
+
+	var id int64
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		input.Code,
+		input.DiscountType,
+		input.Percent,
+		fixedAmount,
+		currency,
+		input.EventID,
+		input.Zone,
+		input.StartsAt,
+		input.EndsAt,
+		input.MaxUses,
+		input.MaxUsesPerUser,
+	).Scan(&id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			switch pqErr.Code {
+			case pq.ErrorCode("23505"):
+				return 0, errs.ErrPromoCodeExists
+			case pq.ErrorCode("23503"):
+				return 0, errs.ErrEventNotFound
+			}
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *promoRepository) GetAll(ctx context.Context) ([]promo.Promo, error) {
+	query := `SELECT ` + promoColumns + ` FROM promo_codes ORDER BY id DESC`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var promos []promo.Promo
+	for rows.Next() {
+		p, err := scanPromo(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		promos = append(promos, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return promos, nil
+}
+
+// GetByCodeForUpdateTx : lock the code, redemptions of one code run one at a time until the transaction ends
+func (r *promoRepository) GetByCodeForUpdateTx(ctx context.Context, tx *sql.Tx, code string) (promo.Promo, error) {
+	query := `SELECT ` + promoColumns + ` FROM promo_codes WHERE code = $1 FOR UPDATE`
+	p, err := scanPromo(tx.QueryRowContext(ctx, query, code).Scan)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return promo.Promo{}, errs.ErrPromoNotFound
+		}
+		return promo.Promo{}, err
+	}
+	return p, nil
+}
+
+// CountRedemptionsTx : uses of the code overall and by the user, redemptions of
+// expired, failed or cancelled bookings are not counted so the use comes back
+func (r *promoRepository) CountRedemptionsTx(ctx context.Context, tx *sql.Tx, promoID, userID int64) (int, int, error) {
+	query := `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE r.user_id = $2)
+		FROM promo_redemptions r
+		JOIN bookings b ON b.id = r.booking_id
+		WHERE r.promo_id = $1 AND b.status IN ('PENDING', 'PAID')
+	`
+	var used, usedByUser int
+	if err := tx.QueryRowContext(ctx, query, promoID, userID).Scan(&used, &usedByUser); err != nil {
+		return 0, 0, err
+	}
+	return used, usedByUser, nil
+}
+
+func (r *promoRepository) RedeemTx(ctx context.Context, tx *sql.Tx, input promo.Redemption) error {
+	query := `
+		INSERT INTO promo_redemptions (promo_id, user_id, booking_id, discount)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := tx.ExecContext(ctx, query, input.PromoID, input.UserID, input.BookingID, input.Discount.Amount)
+	return err
+}
+
+func scanPromo(scan func(dest ...any) error) (promo.Promo, error) {
+	var p promo.Promo
+	var fixedAmount sql.NullInt64
+	var currency sql.NullString
+	err := scan(
+		&p.ID,
+		&p.Code,
+		&p.DiscountType,
+		&p.Percent,
+		&fixedAmount,
+		&currency,
+		&p.EventID,
+		&p.Zone,
+		&p.StartsAt,
+		&p.EndsAt,
+		&p.MaxUses,
+		&p.MaxUsesPerUser,
+		&p.CreatedAt,
+	)
+	if err != nil {
+		return promo.Promo{}, err
+	}
+	if fixedAmount.Valid {
+		fixed := money.New(fixedAmount.Int64, currency.String)
+		p.FixedAmount = &fixed
+	}
+	return p, nil
+}
@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: promo_repo.go
+
+// Package promorepo is a generated GoMock package.
+package promorepo
+
+import (
+	context "context"
+	sql "database/sql"
+	reflect "reflect"
+
+	promo "github.com/codepnw/stdlib-ticket-system/internal/features/promo"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPromoRepository is a mock of PromoRepository interface.
+type MockPromoRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPromoRepositoryMockRecorder
+}
+
+// MockPromoRepositoryMockRecorder is the mock recorder for MockPromoRepository.
+type MockPromoRepositoryMockRecorder struct {
+	mock *MockPromoRepository
+}
+
+// NewMockPromoRepository creates a new mock instance.
+func NewMockPromoRepository(ctrl *gomock.Controller) *MockPromoRepository {
+	mock := &MockPromoRepository{ctrl: ctrl}
+	mock.recorder = &MockPromoRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPromoRepository) EXPECT() *MockPromoRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountRedemptionsTx mocks base method.
+func (m *MockPromoRepository) CountRedemptionsTx(ctx context.Context, tx *sql.Tx, promoID int64, userID int64) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRedemptionsTx", ctx, tx, promoID, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CountRedemptionsTx indicates an expected call of CountRedemptionsTx.
+func (mr *MockPromoRepositoryMockRecorder) CountRedemptionsTx(ctx, tx, promoID, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRedemptionsTx", reflect.TypeOf((*MockPromoRepository)(nil).CountRedemptionsTx), ctx, tx, promoID, userID)
+}
+
+// CreatePromo mocks base method.
+func (m *MockPromoRepository) CreatePromo(ctx context.Context, input promo.Promo) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePromo", ctx, input)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePromo indicates an expected call of CreatePromo.
+func (mr *MockPromoRepositoryMockRecorder) CreatePromo(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePromo", reflect.TypeOf((*MockPromoRepository)(nil).CreatePromo), ctx, input)
+}
+
+// GetAll mocks base method.
+func (m *MockPromoRepository) GetAll(ctx context.Context) ([]promo.Promo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]promo.Promo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockPromoRepositoryMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockPromoRepository)(nil).GetAll), ctx)
+}
+
+// GetByCodeForUpdateTx mocks base method.
+func (m *MockPromoRepository) GetByCodeForUpdateTx(ctx context.Context, tx *sql.Tx, code string) (promo.Promo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByCodeForUpdateTx", ctx, tx, code)
+	ret0, _ := ret[0].(promo.Promo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByCodeForUpdateTx indicates an expected call of GetByCodeForUpdateTx.
+func (mr *MockPromoRepositoryMockRecorder) GetByCodeForUpdateTx(ctx, tx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCodeForUpdateTx", reflect.TypeOf((*MockPromoRepository)(nil).GetByCodeForUpdateTx), ctx, tx, code)
+}
+
+// RedeemTx mocks base method.
+func (m *MockPromoRepository) RedeemTx(ctx context.Context, tx *sql.Tx, input promo.Redemption) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RedeemTx", ctx, tx, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RedeemTx indicates an expected call of RedeemTx.
+func (mr *MockPromoRepositoryMockRecorder) RedeemTx(ctx, tx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeemTx", reflect.TypeOf((*MockPromoRepository)(nil).RedeemTx), ctx, tx, input)
+}
@@ -0,0 +1,62 @@
+package promousecase
+
+import (
+	"context"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/config"
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/promo"
+	promorepo "github.com/codepnw/stdlib-ticket-system/internal/features/promo/repo"
+)
+
+type PromoUsecase interface {
+	CreatePromo(ctx context.Context, req promo.CreatePromoReq) (promo.Promo, error)
+	GetAllPromos(ctx context.Context) ([]promo.Promo, error)
+}
+
+type promoUsecase struct {
+	promoRepo promorepo.PromoRepository
+}
+
+func NewPromoUsecase(promoRepo promorepo.PromoRepository) PromoUsecase {
+	return &promoUsecase{promoRepo: promoRepo}
+}
+
+func (u *promoUsecase) CreatePromo(ctx context.Context, req promo.CreatePromoReq) (promo.Promo, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	// 1. Check Rules
+	code := promo.NormalizeCode(req.Code)
+	if code == "" || !req.Valid() {
+		return promo.Promo{}, errs.ErrInvalidPromo
+	}
+
+	// 2. Create Promo
+	input := promo.Promo{
+		Code:           code,
+		DiscountType:   req.DiscountType,
+		Percent:        req.Percent,
+		FixedAmount:    req.FixedAmount,
+		EventID:        req.EventID,
+		Zone:           req.Zone,
+		StartsAt:       req.StartsAt,
+		EndsAt:         req.EndsAt,
+		MaxUses:        req.MaxUses,
+		MaxUsesPerUser: req.MaxUsesPerUser,
+	}
+	id, err := u.promoRepo.CreatePromo(ctx, input)
+	if err != nil {
+		return promo.Promo{}, err
+	}
+
+	input.ID = id
+	return input, nil
+}
+
+func (u *promoUsecase) GetAllPromos(ctx context.Context) ([]promo.Promo, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	return u.promoRepo.GetAll(ctx)
+}
@@ -0,0 +1,113 @@
+package promousecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/promo"
+	promorepo "github.com/codepnw/stdlib-ticket-system/internal/features/promo/repo"
+	promousecase "github.com/codepnw/stdlib-ticket-system/internal/features/promo/usecase"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+type mocks struct {
+	promo *promorepo.MockPromoRepository
+}
+
+func TestCreatePromo(t *testing.T) {
+	eventID := int64(10)
+	starts := time.Now()
+	ends := starts.Add(-time.Hour)
+	fiveBaht := money.New(500, "THB")
+
+	type testCase struct {
+		name        string
+		req         promo.CreatePromoReq
+		mockFn      func(m mocks)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "success code upper cased",
+			req:  promo.CreatePromoReq{Code: " summer10 ", DiscountType: promo.DiscountPercent, Percent: 10, EventID: &eventID, Zone: "VIP", MaxUses: 100},
+			mockFn: func(m mocks) {
+				m.promo.EXPECT().CreatePromo(gomock.Any(), promo.Promo{
+					Code:         "SUMMER10",
+					DiscountType: promo.DiscountPercent,
+					Percent:      10,
+					EventID:      &eventID,
+					Zone:         "VIP",
+					MaxUses:      100,
+				}).Return(int64(1), nil).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:        "fail percent above 100",
+			req:         promo.CreatePromoReq{Code: "HALF", DiscountType: promo.DiscountPercent, Percent: 150},
+			mockFn:      func(m mocks) {},
+			expectedErr: errs.ErrInvalidPromo,
+		},
+		{
+			name:        "fail percent code with a fixed amount",
+			req:         promo.CreatePromoReq{Code: "BOTH", DiscountType: promo.DiscountPercent, Percent: 10, FixedAmount: &fiveBaht},
+			mockFn:      func(m mocks) {},
+			expectedErr: errs.ErrInvalidPromo,
+		},
+		{
+			name:        "fail zone without event",
+			req:         promo.CreatePromoReq{Code: "VIP", DiscountType: promo.DiscountFixed, FixedAmount: &fiveBaht, Zone: "VIP"},
+			mockFn:      func(m mocks) {},
+			expectedErr: errs.ErrInvalidPromo,
+		},
+		{
+			name:        "fail empty window",
+			req:         promo.CreatePromoReq{Code: "SOON", DiscountType: promo.DiscountFixed, FixedAmount: &fiveBaht, StartsAt: &starts, EndsAt: &ends},
+			mockFn:      func(m mocks) {},
+			expectedErr: errs.ErrInvalidPromo,
+		},
+		{
+			name: "fail code exists",
+			req:  promo.CreatePromoReq{Code: "SUMMER10", DiscountType: promo.DiscountFixed, FixedAmount: &fiveBaht},
+			mockFn: func(m mocks) {
+				m.promo.EXPECT().CreatePromo(gomock.Any(), gomock.Any()).Return(int64(0), errs.ErrPromoCodeExists).Times(1)
+			},
+			expectedErr: errs.ErrPromoCodeExists,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uc, m := setup(t)
+
+			tc.mockFn(m)
+
+			result, err := uc.CreatePromo(context.Background(), tc.req)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), result.ID)
+				assert.Equal(t, "SUMMER10", result.Code)
+			}
+		})
+	}
+}
+
+func setup(t *testing.T) (promousecase.PromoUsecase, mocks) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks{
+		promo: promorepo.NewMockPromoRepository(ctrl),
+	}
+	uc := promousecase.NewPromoUsecase(m.promo)
+
+	return uc, m
+}
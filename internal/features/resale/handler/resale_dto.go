@@ -0,0 +1,12 @@
+package resalehandler
+
+import "github.com/codepnw/stdlib-ticket-system/pkg/money"
+
+type ListSeatReq struct {
+	SeatID int64       `json:"seat_id" validate:"required"`
+	Price  money.Money `json:"price"`
+}
+
+type BuyListingReq struct {
+	PaymentToken string `json:"payment_token" validate:"required"`
+}
@@ -0,0 +1,115 @@
+package resalehandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	resaleusecase "github.com/codepnw/stdlib-ticket-system/internal/features/resale/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/helper"
+	"github.com/codepnw/stdlib-ticket-system/pkg/utils"
+)
+
+type resaleHandler struct {
+	uc resaleusecase.ResaleUsecase
+}
+
+func NewResaleHandler(uc resaleusecase.ResaleUsecase) *resaleHandler {
+	return &resaleHandler{uc: uc}
+}
+
+func (h *resaleHandler) ListSeat(w http.ResponseWriter, r *http.Request) {
+	var req ListSeatReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := utils.Validate(&req); err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.uc.ListSeat(r.Context(), r.PathValue("booking_id"), req.SeatID, req.Price)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrBookingNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, errs.ErrBookingNotOwned):
+			helper.ErrorResponse(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, errs.ErrSeatNotInBooking), errors.Is(err, errs.ErrResalePriceTooHigh),
+			errors.Is(err, errs.ErrInvalidMoney), errors.Is(err, errs.ErrCurrencyMismatch):
+			helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errs.ErrBookingNotPaid), errors.Is(err, errs.ErrResaleNotAllowed), errors.Is(err, errs.ErrSeatAlreadyListed):
+			helper.ErrorResponse(w, http.StatusConflict, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	helper.SuccessResponse(w, http.StatusCreated, "seat listed", data)
+}
+
+func (h *resaleHandler) CancelListing(w http.ResponseWriter, r *http.Request) {
+	listingID, err := helper.ParseInt64(r.PathValue("listing_id"))
+	if err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.uc.CancelListing(r.Context(), listingID); err != nil {
+		switch {
+		case errors.Is(err, errs.ErrListingNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, errs.ErrListingNotOwned):
+			helper.ErrorResponse(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, errs.ErrListingUnavailable):
+			helper.ErrorResponse(w, http.StatusConflict, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	helper.SuccessResponse(w, http.StatusOK, "listing cancelled", nil)
+}
+
+func (h *resaleHandler) BuyListing(w http.ResponseWriter, r *http.Request) {
+	listingID, err := helper.ParseInt64(r.PathValue("listing_id"))
+	if err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req BuyListingReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := utils.Validate(&req); err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.uc.BuyListing(r.Context(), listingID, req.PaymentToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrListingNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, errs.ErrBuyOwnListing):
+			helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errs.ErrListingUnavailable), errors.Is(err, errs.ErrPurchaseLimitExceeded):
+			helper.ErrorResponse(w, http.StatusConflict, err.Error())
+		case errors.Is(err, errs.ErrPaymentFailed):
+			helper.ErrorResponse(w, http.StatusPaymentRequired, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	helper.SuccessResponse(w, http.StatusOK, "resale purchase completed", data)
+}
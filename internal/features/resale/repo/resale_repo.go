@@ -0,0 +1,210 @@
+package resalerepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/resale"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+	"github.com/lib/pq"
+)
+
+//go:generate mockgen -source=resale_repo.go -destination=resale_repo_mock.go -package=resalerepo
+type ResaleRepository interface {
+	GetByID(ctx context.Context, listingID int64) (resale.Listing, error)
+	CreateListing(ctx context.Context, input resale.Listing) (int64, error)
+	GetPendingPayouts(ctx context.Context, limit int) ([]resale.Listing, error)
+	MarkPaidOut(ctx context.Context, listingID int64, payoutID string) error
+
+	// Transaction
+	GetForUpdateTx(ctx context.Context, tx *sql.Tx, listingID int64) (resale.Listing, error)
+	CancelTx(ctx context.Context, tx *sql.Tx, listingID int64) error
+	MarkSoldTx(ctx context.Context, tx *sql.Tx, input resale.Listing) error
+}
+
+type resaleRepository struct {
+	db *sql.DB
+}
+
+func NewResaleRepository(db *sql.DB) ResaleRepository {
+	return &resaleRepository{db: db}
+}
+
+func (r *resaleRepository) GetByID(ctx context.Context, listingID int64) (resale.Listing, error) {
+	query := `
+		SELECT l.id, l.event_id, l.seat_id, l.seller_booking_id, l.seller_id, l.price, e.currency, l.status, l.created_at
+		FROM resale_listings l
+		JOIN events e ON e.id = l.event_id
+		WHERE l.id = $1
+	`
+	l, err := scanListing(r.db.QueryRowContext(ctx, query, listingID).Scan)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return resale.Listing{}, errs.ErrListingNotFound
+		}
+		return resale.Listing{}, err
+	}
+	return l, nil
+}
+
+func (r *resaleRepository) CreateListing(ctx context.Context, input resale.Listing) (int64, error) {
+	query := `
+		INSERT INTO resale_listings (event_id, seat_id, seller_booking_id, seller_id, price, status)
+		VALUES ($1, $2, $3, $4, $5, $6) RETURNING id
+	`
+	var id int64
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		input.EventID,
+		input.SeatID,
+		input.SellerBookingID,
+		input.SellerID,
+		input.Price.Amount,
+		input.Status,
+	).Scan(&id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pq.ErrorCode("23505") {
+			return 0, errs.ErrSeatAlreadyListed
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *resaleRepository) GetForUpdateTx(ctx context.Context, tx *sql.Tx, listingID int64) (resale.Listing, error) {
+	query := `
+		SELECT l.id, l.event_id, l.seat_id, l.seller_booking_id, l.seller_id, l.price, e.currency, l.status, l.created_at
+		FROM resale_listings l
+		JOIN events e ON e.id = l.event_id
+		WHERE l.id = $1
+		FOR UPDATE OF l
+	`
+	l, err := scanListing(tx.QueryRowContext(ctx, query, listingID).Scan)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return resale.Listing{}, errs.ErrListingNotFound
+		}
+		return resale.Listing{}, err
+	}
+	return l, nil
+}
+
+func (r *resaleRepository) CancelTx(ctx context.Context, tx *sql.Tx, listingID int64) error {
+	query := `UPDATE resale_listings SET status = 'CANCELLED' WHERE id = $1 AND status = 'LISTED'`
+	res, err := tx.ExecContext(ctx, query, listingID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errs.ErrListingUnavailable
+	}
+	return nil
+}
+
+// MarkSoldTx : close a LISTED listing with the buyer and a PENDING payout to the seller
+func (r *resaleRepository) MarkSoldTx(ctx context.Context, tx *sql.Tx, input resale.Listing) error {
+	query := `
+		UPDATE resale_listings SET
+			status = 'SOLD', buyer_id = $2, buyer_booking_id = $3, capture_id = $4,
+			fee = $5, payout_amount = $6, payout_status = 'PENDING', sold_at = NOW()
+		WHERE id = $1 AND status = 'LISTED'
+	`
+	res, err := tx.ExecContext(
+		ctx,
+		query,
+		input.ID,
+		input.BuyerID,
+		input.BuyerBookingID,
+		input.CaptureID,
+		input.Fee.Amount,
+		input.PayoutAmount.Amount,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errs.ErrListingUnavailable
+	}
+	return nil
+}
+
+// GetPendingPayouts : sold listings whose seller has not been paid yet, oldest sale first
+func (r *resaleRepository) GetPendingPayouts(ctx context.Context, limit int) ([]resale.Listing, error) {
+	query := `
+		SELECT l.id, l.event_id, l.seat_id, l.seller_booking_id, l.seller_id, l.price, e.currency, l.status, l.created_at, l.payout_amount
+		FROM resale_listings l
+		JOIN events e ON e.id = l.event_id
+		WHERE l.payout_status = 'PENDING'
+		ORDER BY l.sold_at
+		LIMIT $1
+	`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var listings []resale.Listing
+	for rows.Next() {
+		var payout int64
+		l, err := scanListing(func(dest ...any) error {
+			return rows.Scan(append(dest, &payout)...)
+		})
+		if err != nil {
+			return nil, err
+		}
+		l.PayoutAmount = money.New(payout, l.Price.Currency)
+		l.PayoutStatus = resale.PayoutPending
+		listings = append(listings, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return listings, nil
+}
+
+// MarkPaidOut : record the gateway payout, a listing that is already PAID is left as it is
+func (r *resaleRepository) MarkPaidOut(ctx context.Context, listingID int64, payoutID string) error {
+	query := `
+		UPDATE resale_listings SET payout_status = 'PAID', payout_id = $2
+		WHERE id = $1 AND payout_status = 'PENDING'
+	`
+	_, err := r.db.ExecContext(ctx, query, listingID, payoutID)
+	return err
+}
+
+func scanListing(scan func(dest ...any) error) (resale.Listing, error) {
+	var l resale.Listing
+	err := scan(
+		&l.ID,
+		&l.EventID,
+		&l.SeatID,
+		&l.SellerBookingID,
+		&l.SellerID,
+		&l.Price.Amount,
+		&l.Price.Currency,
+		&l.Status,
+		&l.CreatedAt,
+	)
+	if err != nil {
+		return resale.Listing{}, err
+	}
+	return l, nil
+}
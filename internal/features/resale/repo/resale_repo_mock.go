@@ -0,0 +1,139 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: resale_repo.go
+
+// Package resalerepo is a generated GoMock package.
+package resalerepo
+
+import (
+	context "context"
+	sql "database/sql"
+	reflect "reflect"
+
+	resale "github.com/codepnw/stdlib-ticket-system/internal/features/resale"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockResaleRepository is a mock of ResaleRepository interface.
+type MockResaleRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockResaleRepositoryMockRecorder
+}
+
+// MockResaleRepositoryMockRecorder is the mock recorder for MockResaleRepository.
+type MockResaleRepositoryMockRecorder struct {
+	mock *MockResaleRepository
+}
+
+// NewMockResaleRepository creates a new mock instance.
+func NewMockResaleRepository(ctrl *gomock.Controller) *MockResaleRepository {
+	mock := &MockResaleRepository{ctrl: ctrl}
+	mock.recorder = &MockResaleRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResaleRepository) EXPECT() *MockResaleRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CancelTx mocks base method.
+func (m *MockResaleRepository) CancelTx(ctx context.Context, tx *sql.Tx, listingID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelTx", ctx, tx, listingID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelTx indicates an expected call of CancelTx.
+func (mr *MockResaleRepositoryMockRecorder) CancelTx(ctx, tx, listingID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelTx", reflect.TypeOf((*MockResaleRepository)(nil).CancelTx), ctx, tx, listingID)
+}
+
+// CreateListing mocks base method.
+func (m *MockResaleRepository) CreateListing(ctx context.Context, input resale.Listing) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateListing", ctx, input)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateListing indicates an expected call of CreateListing.
+func (mr *MockResaleRepositoryMockRecorder) CreateListing(ctx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateListing", reflect.TypeOf((*MockResaleRepository)(nil).CreateListing), ctx, input)
+}
+
+// GetByID mocks base method.
+func (m *MockResaleRepository) GetByID(ctx context.Context, listingID int64) (resale.Listing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, listingID)
+	ret0, _ := ret[0].(resale.Listing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockResaleRepositoryMockRecorder) GetByID(ctx, listingID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockResaleRepository)(nil).GetByID), ctx, listingID)
+}
+
+// GetForUpdateTx mocks base method.
+func (m *MockResaleRepository) GetForUpdateTx(ctx context.Context, tx *sql.Tx, listingID int64) (resale.Listing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdateTx", ctx, tx, listingID)
+	ret0, _ := ret[0].(resale.Listing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdateTx indicates an expected call of GetForUpdateTx.
+func (mr *MockResaleRepositoryMockRecorder) GetForUpdateTx(ctx, tx, listingID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdateTx", reflect.TypeOf((*MockResaleRepository)(nil).GetForUpdateTx), ctx, tx, listingID)
+}
+
+// GetPendingPayouts mocks base method.
+func (m *MockResaleRepository) GetPendingPayouts(ctx context.Context, limit int) ([]resale.Listing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingPayouts", ctx, limit)
+	ret0, _ := ret[0].([]resale.Listing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingPayouts indicates an expected call of GetPendingPayouts.
+func (mr *MockResaleRepositoryMockRecorder) GetPendingPayouts(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingPayouts", reflect.TypeOf((*MockResaleRepository)(nil).GetPendingPayouts), ctx, limit)
+}
+
+// MarkPaidOut mocks base method.
+func (m *MockResaleRepository) MarkPaidOut(ctx context.Context, listingID int64, payoutID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPaidOut", ctx, listingID, payoutID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPaidOut indicates an expected call of MarkPaidOut.
+func (mr *MockResaleRepositoryMockRecorder) MarkPaidOut(ctx, listingID, payoutID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPaidOut", reflect.TypeOf((*MockResaleRepository)(nil).MarkPaidOut), ctx, listingID, payoutID)
+}
+
+// MarkSoldTx mocks base method.
+func (m *MockResaleRepository) MarkSoldTx(ctx context.Context, tx *sql.Tx, input resale.Listing) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSoldTx", ctx, tx, input)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkSoldTx indicates an expected call of MarkSoldTx.
+func (mr *MockResaleRepositoryMockRecorder) MarkSoldTx(ctx, tx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSoldTx", reflect.TypeOf((*MockResaleRepository)(nil).MarkSoldTx), ctx, tx, input)
+}
@@ -0,0 +1,41 @@
+package resale
+
+import (
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+)
+
+type ListingStatus string
+
+const (
+	StatusListed    ListingStatus = "LISTED"
+	StatusSold      ListingStatus = "SOLD"
+	StatusCancelled ListingStatus = "CANCELLED"
+)
+
+type PayoutStatus string
+
+const (
+	PayoutPending PayoutStatus = "PENDING"
+	PayoutPaid    PayoutStatus = "PAID"
+)
+
+type Listing struct {
+	ID              int64         `json:"id" db:"id"`
+	EventID         int64         `json:"event_id" db:"event_id"`
+	SeatID          int64         `json:"seat_id" db:"seat_id"`
+	SellerBookingID string        `json:"seller_booking_id" db:"seller_booking_id"`
+	SellerID        int64         `json:"seller_id" db:"seller_id"`
+	Price           money.Money   `json:"price" db:"price"`
+	Status          ListingStatus `json:"status" db:"status"`
+	BuyerID         int64         `json:"buyer_id" db:"buyer_id"`
+	BuyerBookingID  string        `json:"buyer_booking_id" db:"buyer_booking_id"`
+	CaptureID       string        `json:"capture_id" db:"capture_id"`
+	Fee             money.Money   `json:"fee" db:"fee"`
+	PayoutAmount    money.Money   `json:"payout_amount" db:"payout_amount"`
+	PayoutID        string        `json:"payout_id" db:"payout_id"`
+	PayoutStatus    PayoutStatus  `json:"payout_status" db:"payout_status"`
+	CreatedAt       time.Time     `json:"created_at" db:"created_at"`
+	SoldAt          time.Time     `json:"sold_at" db:"sold_at"`
+}
@@ -0,0 +1,353 @@
+package resaleusecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/authcontext"
+	"github.com/codepnw/stdlib-ticket-system/internal/config"
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+	bookingrepo "github.com/codepnw/stdlib-ticket-system/internal/features/booking/repo"
+	eventrepo "github.com/codepnw/stdlib-ticket-system/internal/features/event/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/payment"
+	paymentgateway "github.com/codepnw/stdlib-ticket-system/internal/features/payment/gateway"
+	paymentrepo "github.com/codepnw/stdlib-ticket-system/internal/features/payment/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/resale"
+	resalerepo "github.com/codepnw/stdlib-ticket-system/internal/features/resale/repo"
+	"github.com/codepnw/stdlib-ticket-system/pkg/database"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+)
+
+type ResaleUsecase interface {
+	ListSeat(ctx context.Context, bookingID string, seatID int64, price money.Money) (displayListing, error)
+	CancelListing(ctx context.Context, listingID int64) error
+	BuyListing(ctx context.Context, listingID int64, token string) (displayPurchase, error)
+	// SettlePayouts : send the PENDING seller payouts, run by a background worker
+	SettlePayouts(ctx context.Context) error
+}
+
+// payoutBatchSize : max seller payouts sent per sweep
+const payoutBatchSize = 100
+
+type resaleUsecase struct {
+	location    *time.Location
+	feePercent  int
+	tx          database.TxManager
+	gateway     paymentgateway.PaymentGateway
+	resaleRepo  resalerepo.ResaleRepository
+	paymentRepo paymentrepo.PaymentRepository
+	bookRepo    bookingrepo.BookingRepository
+	eventRepo   eventrepo.EventRepository
+}
+
+func NewResaleUsecase(location *time.Location, feePercent int, tx database.TxManager, gateway paymentgateway.PaymentGateway, resaleRepo resalerepo.ResaleRepository, paymentRepo paymentrepo.PaymentRepository, bookRepo bookingrepo.BookingRepository, eventRepo eventrepo.EventRepository) ResaleUsecase {
+	return &resaleUsecase{
+		location:    location,
+		feePercent:  feePercent,
+		tx:          tx,
+		gateway:     gateway,
+		resaleRepo:  resaleRepo,
+		paymentRepo: paymentRepo,
+		bookRepo:    bookRepo,
+		eventRepo:   eventRepo,
+	}
+}
+
+type displayListing struct {
+	ID        int64       `json:"id"`
+	EventID   int64       `json:"event_id"`
+	SeatID    int64       `json:"seat_id"`
+	Price     money.Money `json:"price"`
+	FaceValue money.Money `json:"face_value"`
+	Status    string      `json:"status"`
+}
+
+func (u *resaleUsecase) ListSeat(ctx context.Context, bookingID string, seatID int64, price money.Money) (displayListing, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	userID := authcontext.GetUserID(ctx)
+	if !price.IsPositive() {
+		return displayListing{}, errs.ErrInvalidMoney
+	}
+
+	// 1. Check Booking
+	bookData, err := u.bookRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return displayListing{}, err
+	}
+	if bookData.UserID != userID {
+		return displayListing{}, errs.ErrBookingNotOwned
+	}
+	if bookData.Status != booking.StatusPaid {
+		return displayListing{}, errs.ErrBookingNotPaid
+	}
+	// 2. Seat must be active and not yet used at the gate
+	items, err := u.bookRepo.GetItems(ctx, bookingID)
+	if err != nil {
+		return displayListing{}, err
+	}
+	var item *booking.BookingItemResponse
+	for i := range items {
+		if items[i].SeatID == seatID && items[i].ReleasedAt == nil && items[i].CheckedInAt == nil {
+			item = &items[i]
+			break
+		}
+	}
+	if item == nil {
+		return displayListing{}, errs.ErrSeatNotInBooking
+	}
+	// 3. Price Cap
+	eventData, err := u.eventRepo.GetEventByID(ctx, bookData.EventID)
+	if err != nil {
+		return displayListing{}, err
+	}
+	maxPrice, err := eventData.ResalePriceCap(item.UnitPrice)
+	if err != nil {
+		return displayListing{}, err
+	}
+	cmp, err := price.Cmp(maxPrice)
+	if err != nil {
+		return displayListing{}, err
+	}
+	if cmp > 0 {
+		return displayListing{}, errs.ErrResalePriceTooHigh
+	}
+
+	input := resale.Listing{
+		EventID:         bookData.EventID,
+		SeatID:          seatID,
+		SellerBookingID: bookingID,
+		SellerID:        userID,
+		Price:           price,
+		Status:          resale.StatusListed,
+	}
+	id, err := u.resaleRepo.CreateListing(ctx, input)
+	if err != nil {
+		return displayListing{}, err
+	}
+
+	return displayListing{
+		ID:        id,
+		EventID:   input.EventID,
+		SeatID:    input.SeatID,
+		Price:     input.Price,
+		FaceValue: item.UnitPrice,
+		Status:    string(input.Status),
+	}, nil
+}
+
+func (u *resaleUsecase) CancelListing(ctx context.Context, listingID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	listing, err := u.resaleRepo.GetByID(ctx, listingID)
+	if err != nil {
+		return err
+	}
+	if listing.SellerID != authcontext.GetUserID(ctx) {
+		return errs.ErrListingNotOwned
+	}
+
+	return u.tx.WithTx(ctx, func(tx *sql.Tx) error {
+		return u.resaleRepo.CancelTx(ctx, tx, listingID)
+	})
+}
+
+type displayPurchase struct {
+	ListingID int64       `json:"listing_id"`
+	BookingID string      `json:"booking_id"`
+	SeatID    int64       `json:"seat_id"`
+	Price     money.Money `json:"price"`
+	CaptureID string      `json:"capture_id"`
+	SoldAt    string      `json:"sold_at"`
+}
+
+// BuyListing : charge the buyer, move the seat to a new PAID booking and pay the seller.
+// The seller's ticket code names the old booking, so it stops matching at the gate,
+// and a seller left without seats ends TRANSFERRED.
+// The payout is sent after the sale commits, a failed one stays PENDING for SettlePayouts.
+func (u *resaleUsecase) BuyListing(ctx context.Context, listingID int64, token string) (displayPurchase, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	userID := authcontext.GetUserID(ctx)
+
+	// 1. Check Listing
+	listing, err := u.resaleRepo.GetByID(ctx, listingID)
+	if err != nil {
+		return displayPurchase{}, err
+	}
+	if listing.Status != resale.StatusListed {
+		return displayPurchase{}, errs.ErrListingUnavailable
+	}
+	if listing.SellerID == userID {
+		return displayPurchase{}, errs.ErrBuyOwnListing
+	}
+	eventData, err := u.eventRepo.GetEventByID(ctx, listing.EventID)
+	if err != nil {
+		return displayPurchase{}, err
+	}
+	fees, err := u.eventRepo.GetFees(ctx, listing.EventID)
+	if err != nil {
+		return displayPurchase{}, err
+	}
+
+	// 2. Authorize & Capture
+	auth, err := u.gateway.Authorize(ctx, paymentgateway.AuthorizeReq{
+		Reference: paymentgateway.AttemptReference(fmt.Sprintf("resale-%d", listing.ID)),
+		Amount:    listing.Price,
+		Token:     token,
+	})
+	if err != nil {
+		return displayPurchase{}, fmt.Errorf("%w: %v", errs.ErrPaymentFailed, err)
+	}
+	capture, err := u.gateway.Capture(ctx, auth.ID, listing.Price)
+	if err != nil {
+		return displayPurchase{}, fmt.Errorf("%w: %v", errs.ErrPaymentFailed, err)
+	}
+
+	var toBookingID string
+	err = u.tx.WithTx(ctx, func(tx *sql.Tx) error {
+		// 3. Lock Listing
+		listing, err = u.resaleRepo.GetForUpdateTx(ctx, tx, listingID)
+		if err != nil {
+			return err
+		}
+		if listing.Status != resale.StatusListed {
+			return errs.ErrListingUnavailable
+		}
+
+		// 4. Buyer Limit
+		if err := u.bookRepo.LockUserEventTx(ctx, tx, userID, listing.EventID); err != nil {
+			return err
+		}
+		held, err := u.bookRepo.CountUserSeatsTx(ctx, tx, userID, listing.EventID)
+		if err != nil {
+			return err
+		}
+		if err := eventData.PurchaseLimit(1, held); err != nil {
+			return err
+		}
+
+		// 5. New PAID Booking for the Buyer, priced at what was captured so a refund matches the charge
+		toBookingID, err = u.bookRepo.CreateBookingTx(ctx, tx, booking.Booking{
+			UserID:         userID,
+			EventID:        listing.EventID,
+			TotalAmount:    capture.Amount,
+			DiscountAmount: money.Zero(eventData.Currency),
+			Status:         booking.StatusPaid,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := u.paymentRepo.CreatePaymentTx(ctx, tx, payment.Payment{
+			BookingID:       toBookingID,
+			Provider:        u.gateway.Name(),
+			AuthorizationID: auth.ID,
+			CaptureID:       capture.ID,
+			Amount:          capture.Amount,
+			Status:          payment.StatusCaptured,
+		}); err != nil {
+			return err
+		}
+
+		// 6. Record the Sale with a PENDING Payout, before the move closes the seat's open listings
+		fee := listing.Price.Percent(float64(u.feePercent))
+		payoutAmount, err := listing.Price.Sub(fee)
+		if err != nil {
+			return err
+		}
+
+		listing.BuyerID = userID
+		listing.BuyerBookingID = toBookingID
+		listing.CaptureID = capture.ID
+		listing.Fee = fee
+		listing.PayoutAmount = payoutAmount
+		listing.PayoutStatus = resale.PayoutPending
+		if err := u.resaleRepo.MarkSoldTx(ctx, tx, listing); err != nil {
+			return err
+		}
+
+		// 7. Move Seat
+		if err := u.bookRepo.MoveItemsTx(ctx, tx, listing.SellerBookingID, toBookingID, []int64{listing.SeatID}); err != nil {
+			if errors.Is(err, errs.ErrTransferSeatsUnavailable) {
+				return errs.ErrListingUnavailable
+			}
+			return err
+		}
+		if _, err := u.bookRepo.RecalculateTotalTx(ctx, tx, listing.SellerBookingID, fees, eventData.TaxPercent); err != nil {
+			return err
+		}
+
+		// 8. Close the seller's booking once its last seat is sold
+		remaining, err := u.bookRepo.GetActiveSeatIDsTx(ctx, tx, listing.SellerBookingID)
+		if err != nil {
+			return err
+		}
+		if len(remaining) == 0 {
+			return u.bookRepo.UpdateStatusTx(ctx, tx, listing.SellerBookingID, booking.StatusPaid, booking.StatusTransferred)
+		}
+		return nil
+	})
+	if err != nil {
+		// Money was taken but the seat could not be moved, give it back
+		if _, rfErr := u.gateway.Refund(ctx, "reversal-"+capture.ID, capture.ID, capture.Amount); rfErr != nil {
+			log.Printf("refund capture %s failed: %v", capture.ID, rfErr)
+		}
+		return displayPurchase{}, err
+	}
+
+	// 9. Pay the Seller
+	if err := u.payout(ctx, listing); err != nil {
+		log.Printf("payout for listing %d failed, retrying later: %v", listing.ID, err)
+	}
+
+	return displayPurchase{
+		ListingID: listing.ID,
+		BookingID: toBookingID,
+		SeatID:    listing.SeatID,
+		Price:     capture.Amount,
+		CaptureID: capture.ID,
+		SoldAt:    time.Now().In(u.location).Format(time.DateTime),
+	}, nil
+}
+
+func (u *resaleUsecase) SettlePayouts(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	listings, err := u.resaleRepo.GetPendingPayouts(ctx, payoutBatchSize)
+	if err != nil {
+		return err
+	}
+
+	var paid int
+	for _, l := range listings {
+		if err := u.payout(ctx, l); err != nil {
+			log.Printf("payout for listing %d failed: %v", l.ID, err)
+			continue
+		}
+		paid++
+	}
+
+	if paid > 0 {
+		log.Printf("paid out %d resale listings", paid)
+	}
+	return nil
+}
+
+// payout : the listing reference makes the gateway pay a sale once, however often it is retried
+func (u *resaleUsecase) payout(ctx context.Context, listing resale.Listing) error {
+	reference := fmt.Sprintf("resale-%d", listing.ID)
+	payout, err := u.gateway.Payout(ctx, reference, listing.PayoutAmount)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errs.ErrPayoutFailed, err)
+	}
+	return u.resaleRepo.MarkPaidOut(ctx, listing.ID, payout.ID)
+}
@@ -0,0 +1,390 @@
+package resaleusecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/authcontext"
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+	bookingrepo "github.com/codepnw/stdlib-ticket-system/internal/features/booking/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	eventrepo "github.com/codepnw/stdlib-ticket-system/internal/features/event/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/payment"
+	paymentgateway "github.com/codepnw/stdlib-ticket-system/internal/features/payment/gateway"
+	paymentrepo "github.com/codepnw/stdlib-ticket-system/internal/features/payment/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/resale"
+	resalerepo "github.com/codepnw/stdlib-ticket-system/internal/features/resale/repo"
+	resaleusecase "github.com/codepnw/stdlib-ticket-system/internal/features/resale/usecase"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var ErrMockDBError = errors.New("db error")
+
+type mockTx struct{}
+
+func (m mockTx) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	return fn(nil)
+}
+
+func (m mockTx) WithRetryTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	return fn(nil)
+}
+
+type mocks struct {
+	resale  *resalerepo.MockResaleRepository
+	payment *paymentrepo.MockPaymentRepository
+	book    *bookingrepo.MockBookingRepository
+	event   *eventrepo.MockEventRepository
+}
+
+func TestListSeat(t *testing.T) {
+	paid := booking.Booking{ID: "mock-uuid-1", UserID: 1, EventID: 10, Status: booking.StatusPaid}
+	items := []booking.BookingItemResponse{{SeatID: 11, UnitPrice: money.New(10000, "THB")}}
+
+	type testCase struct {
+		name        string
+		price       money.Money
+		mockFn      func(m mocks)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:  "success at cap",
+			price: money.New(11000, "THB"),
+			mockFn: func(m mocks) {
+				m.book.EXPECT().GetByID(gomock.Any(), paid.ID).Return(paid, nil).Times(1)
+
+				m.book.EXPECT().GetItems(gomock.Any(), paid.ID).Return(items, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), paid.EventID).Return(event.Event{ID: 10, Currency: "THB", ResaleCapPercent: 110}, nil).Times(1)
+
+				m.resale.EXPECT().CreateListing(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, input resale.Listing) (int64, error) {
+						assert.Equal(t, resale.StatusListed, input.Status)
+						assert.Equal(t, money.New(11000, "THB"), input.Price)
+						return 1, nil
+					}).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "fail above cap",
+			price: money.New(11001, "THB"),
+			mockFn: func(m mocks) {
+				m.book.EXPECT().GetByID(gomock.Any(), paid.ID).Return(paid, nil).Times(1)
+
+				m.book.EXPECT().GetItems(gomock.Any(), paid.ID).Return(items, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), paid.EventID).Return(event.Event{ID: 10, Currency: "THB", ResaleCapPercent: 110}, nil).Times(1)
+			},
+			expectedErr: errs.ErrResalePriceTooHigh,
+		},
+		{
+			name:  "fail price in another currency",
+			price: money.New(9000, "USD"),
+			mockFn: func(m mocks) {
+				m.book.EXPECT().GetByID(gomock.Any(), paid.ID).Return(paid, nil).Times(1)
+
+				m.book.EXPECT().GetItems(gomock.Any(), paid.ID).Return(items, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), paid.EventID).Return(event.Event{ID: 10, Currency: "THB", ResaleCapPercent: 110}, nil).Times(1)
+			},
+			expectedErr: errs.ErrCurrencyMismatch,
+		},
+		{
+			name:        "fail price not positive",
+			price:       money.Zero("THB"),
+			mockFn:      func(m mocks) {},
+			expectedErr: errs.ErrInvalidMoney,
+		},
+		{
+			name:  "fail resale off",
+			price: money.New(9000, "THB"),
+			mockFn: func(m mocks) {
+				m.book.EXPECT().GetByID(gomock.Any(), paid.ID).Return(paid, nil).Times(1)
+
+				m.book.EXPECT().GetItems(gomock.Any(), paid.ID).Return(items, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), paid.EventID).Return(event.Event{ID: 10, Currency: "THB"}, nil).Times(1)
+			},
+			expectedErr: errs.ErrResaleNotAllowed,
+		},
+		{
+			name:  "fail seat checked in",
+			price: money.New(9000, "THB"),
+			mockFn: func(m mocks) {
+				m.book.EXPECT().GetByID(gomock.Any(), paid.ID).Return(paid, nil).Times(1)
+
+				checkedIn := time.Now()
+				m.book.EXPECT().GetItems(gomock.Any(), paid.ID).Return([]booking.BookingItemResponse{{SeatID: 11, UnitPrice: money.New(10000, "THB"), CheckedInAt: &checkedIn}}, nil).Times(1)
+			},
+			expectedErr: errs.ErrSeatNotInBooking,
+		},
+		{
+			name:  "fail booking not paid",
+			price: money.New(9000, "THB"),
+			mockFn: func(m mocks) {
+				data := paid
+				data.Status = booking.StatusPending
+				m.book.EXPECT().GetByID(gomock.Any(), paid.ID).Return(data, nil).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotPaid,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, m := setup(t)
+
+			// Mock FN
+			tc.mockFn(m)
+
+			ctx := authcontext.SetUserID(context.Background(), int64(1))
+			_, err := uc.ListSeat(ctx, paid.ID, 11, tc.price)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBuyListing(t *testing.T) {
+	listed := resale.Listing{
+		ID:              1,
+		EventID:         10,
+		SeatID:          11,
+		SellerBookingID: "mock-uuid-1",
+		SellerID:        1,
+		Price:           money.New(11000, "THB"),
+		Status:          resale.StatusListed,
+	}
+
+	type testCase struct {
+		name        string
+		token       string
+		mockFn      func(m mocks)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:  "success",
+			token: paymentgateway.FakeTokenSuccess,
+			mockFn: func(m mocks) {
+				m.resale.EXPECT().GetByID(gomock.Any(), listed.ID).Return(listed, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), listed.EventID).Return(event.Event{ID: 10, Currency: "THB"}, nil).Times(1)
+
+				m.event.EXPECT().GetFees(gomock.Any(), listed.EventID).Return(nil, nil).Times(1)
+
+				m.resale.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), listed.ID).Return(listed, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(2), listed.EventID).Return(nil).Times(1)
+
+				m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(2), listed.EventID).Return(0, nil).Times(1)
+
+				m.book.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sql.Tx, input booking.Booking) (string, error) {
+						assert.Equal(t, listed.Price, input.TotalAmount)
+						return "mock-uuid-2", nil
+					}).Times(1)
+
+				m.payment.EXPECT().CreatePaymentTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sql.Tx, input payment.Payment) (int64, error) {
+						assert.Equal(t, "mock-uuid-2", input.BookingID)
+						assert.Equal(t, payment.StatusCaptured, input.Status)
+						assert.Equal(t, listed.Price, input.Amount)
+						return 1, nil
+					}).Times(1)
+
+				m.book.EXPECT().MoveItemsTx(gomock.Any(), gomock.Any(), "mock-uuid-1", "mock-uuid-2", []int64{11}).Return(nil).Times(1)
+
+				m.book.EXPECT().RecalculateTotalTx(gomock.Any(), gomock.Any(), "mock-uuid-1", gomock.Any(), gomock.Any()).Return(money.Zero("THB"), nil).Times(1)
+
+				m.book.EXPECT().GetActiveSeatIDsTx(gomock.Any(), gomock.Any(), "mock-uuid-1").Return(nil, nil).Times(1)
+
+				m.book.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), "mock-uuid-1", booking.StatusPaid, booking.StatusTransferred).Return(nil).Times(1)
+
+				m.resale.EXPECT().MarkSoldTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sql.Tx, input resale.Listing) error {
+						assert.Equal(t, int64(2), input.BuyerID)
+						assert.Equal(t, "mock-uuid-2", input.BuyerBookingID)
+						assert.Equal(t, money.New(1100, "THB"), input.Fee)
+						assert.Equal(t, money.New(9900, "THB"), input.PayoutAmount)
+						assert.Equal(t, resale.PayoutPending, input.PayoutStatus)
+						return nil
+					}).Times(1)
+
+				m.resale.EXPECT().MarkPaidOut(gomock.Any(), listed.ID, "fake_payout_resale-1").Return(nil).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:  "fail own listing",
+			token: paymentgateway.FakeTokenSuccess,
+			mockFn: func(m mocks) {
+				data := listed
+				data.SellerID = 2
+				m.resale.EXPECT().GetByID(gomock.Any(), listed.ID).Return(data, nil).Times(1)
+			},
+			expectedErr: errs.ErrBuyOwnListing,
+		},
+		{
+			name:  "fail already sold",
+			token: paymentgateway.FakeTokenSuccess,
+			mockFn: func(m mocks) {
+				data := listed
+				data.Status = resale.StatusSold
+				m.resale.EXPECT().GetByID(gomock.Any(), listed.ID).Return(data, nil).Times(1)
+			},
+			expectedErr: errs.ErrListingUnavailable,
+		},
+		{
+			name:  "fail payment declined",
+			token: paymentgateway.FakeTokenDecline,
+			mockFn: func(m mocks) {
+				m.resale.EXPECT().GetByID(gomock.Any(), listed.ID).Return(listed, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), listed.EventID).Return(event.Event{ID: 10, Currency: "THB"}, nil).Times(1)
+
+				m.event.EXPECT().GetFees(gomock.Any(), listed.EventID).Return(nil, nil).Times(1)
+			},
+			expectedErr: errs.ErrPaymentFailed,
+		},
+		{
+			name:  "fail seat left seller booking",
+			token: paymentgateway.FakeTokenSuccess,
+			mockFn: func(m mocks) {
+				m.resale.EXPECT().GetByID(gomock.Any(), listed.ID).Return(listed, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), listed.EventID).Return(event.Event{ID: 10, Currency: "THB"}, nil).Times(1)
+
+				m.event.EXPECT().GetFees(gomock.Any(), listed.EventID).Return(nil, nil).Times(1)
+
+				m.resale.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), listed.ID).Return(listed, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(2), listed.EventID).Return(nil).Times(1)
+
+				m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(2), listed.EventID).Return(0, nil).Times(1)
+
+				m.book.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).Return("mock-uuid-2", nil).Times(1)
+
+				m.payment.EXPECT().CreatePaymentTx(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(1), nil).Times(1)
+
+				m.resale.EXPECT().MarkSoldTx(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+				m.book.EXPECT().MoveItemsTx(gomock.Any(), gomock.Any(), "mock-uuid-1", "mock-uuid-2", []int64{11}).Return(errs.ErrTransferSeatsUnavailable).Times(1)
+			},
+			expectedErr: errs.ErrListingUnavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, m := setup(t)
+
+			// Mock FN
+			tc.mockFn(m)
+
+			ctx := authcontext.SetUserID(context.Background(), int64(2))
+			_, err := uc.BuyListing(ctx, listed.ID, tc.token)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSettlePayouts(t *testing.T) {
+	pending := resale.Listing{
+		ID:           1,
+		EventID:      10,
+		Price:        money.New(11000, "THB"),
+		PayoutAmount: money.New(9900, "THB"),
+		Status:       resale.StatusSold,
+		PayoutStatus: resale.PayoutPending,
+	}
+
+	type testCase struct {
+		name        string
+		mockFn      func(m mocks)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "success",
+			mockFn: func(m mocks) {
+				m.resale.EXPECT().GetPendingPayouts(gomock.Any(), gomock.Any()).Return([]resale.Listing{pending}, nil).Times(1)
+
+				m.resale.EXPECT().MarkPaidOut(gomock.Any(), pending.ID, "fake_payout_resale-1").Return(nil).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "failed payout stays pending",
+			mockFn: func(m mocks) {
+				data := pending
+				data.PayoutAmount = money.Zero("THB")
+				m.resale.EXPECT().GetPendingPayouts(gomock.Any(), gomock.Any()).Return([]resale.Listing{data}, nil).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "fail get pending payouts",
+			mockFn: func(m mocks) {
+				m.resale.EXPECT().GetPendingPayouts(gomock.Any(), gomock.Any()).Return(nil, ErrMockDBError).Times(1)
+			},
+			expectedErr: ErrMockDBError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, m := setup(t)
+
+			// Mock FN
+			tc.mockFn(m)
+
+			err := uc.SettlePayouts(context.Background())
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func setup(t *testing.T) (resaleusecase.ResaleUsecase, mocks) {
+	ctrl := gomock.NewController(t)
+
+	loc, _ := time.LoadLocation("Asia/Bangkok")
+
+	m := mocks{
+		resale:  resalerepo.NewMockResaleRepository(ctrl),
+		payment: paymentrepo.NewMockPaymentRepository(ctrl),
+		book:    bookingrepo.NewMockBookingRepository(ctrl),
+		event:   eventrepo.NewMockEventRepository(ctrl),
+	}
+	uc := resaleusecase.NewResaleUsecase(loc, 10, mockTx{}, paymentgateway.NewFakeGateway(), m.resale, m.payment, m.book, m.event)
+
+	return uc, m
+}
@@ -8,18 +8,28 @@ import (
 
 	"github.com/codepnw/stdlib-ticket-system/internal/errs"
 	"github.com/codepnw/stdlib-ticket-system/internal/features/seat"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
 	"github.com/lib/pq"
 )
 
 //go:generate mockgen -source=seat_repo.go -destination=seat_repo_mock.go -package=seatrepo
 type SeatRepository interface {
 	GetSeatsByEventID(ctx context.Context, eventID int64) ([]seat.Seat, error)
+	CountAvailableSeats(ctx context.Context, eventID int64) (int, error)
+	GetSeatStatuses(ctx context.Context, eventID int64, seatIDs []int64) ([]seat.Seat, error)
 
 	// Transaction
 	CreateSeatBatchTx(ctx context.Context, tx *sql.Tx, seats []seat.Seat) error
 	GetSeatsForUpdateTx(ctx context.Context, tx *sql.Tx, seatIDs []int64) ([]seat.Seat, error)
+	GetSeatsTx(ctx context.Context, tx *sql.Tx, seatIDs []int64) ([]seat.Seat, error)
+	GetSeatsSkipLockedTx(ctx context.Context, tx *sql.Tx, seatIDs []int64) ([]seat.Seat, error)
+	GetZoneSeatsForUpdateTx(ctx context.Context, tx *sql.Tx, eventID int64, zone string) ([]seat.Seat, error)
 	UpdateSeatsStatusTx(ctx context.Context, tx *sql.Tx, seatIDs []int64, status string) error
-	CancelSeatsTx(ctx context.Context, tx *sql.Tx, bookingID string) error
+	ReserveSeatsVersionedTx(ctx context.Context, tx *sql.Tx, seats []seat.Seat) error
+	GetAvailableSeatIDsTx(ctx context.Context, tx *sql.Tx, eventID int64, limit int) ([]int64, error)
+	CancelSeatsTx(ctx context.Context, tx *sql.Tx, bookingID string) ([]int64, error)
+	RemoveSeatsTx(ctx context.Context, tx *sql.Tx, bookingID string, seatIDs []int64) error
+	SellSeatsTx(ctx context.Context, tx *sql.Tx, bookingID string) ([]int64, error)
 }
 
 type seatRepository struct {
@@ -30,23 +40,47 @@ func NewSeatRepository(db *sql.DB) SeatRepository {
 	return &seatRepository{db: db}
 }
 
+const (
+	// seatColumns : values CreateSeatBatchTx inserts per seat
+	seatColumns = 12
+	// seatBatchSize : seats per INSERT, keeps a statement under the 65535 parameter limit
+	seatBatchSize = 1000
+)
+
 func (r *seatRepository) CreateSeatBatchTx(ctx context.Context, tx *sql.Tx, seats []seat.Seat) error {
-	if len(seats) == 0 {
-		return nil
+	for start := 0; start < len(seats); start += seatBatchSize {
+		end := min(start+seatBatchSize, len(seats))
+		if err := r.insertSeatsTx(ctx, tx, seats[start:end]); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
+func (r *seatRepository) insertSeatsTx(ctx context.Context, tx *sql.Tx, seats []seat.Seat) error {
 	valStrs := make([]string, 0, len(seats))
-	valArgs := make([]any, 0, len(seats)*5)
+	valArgs := make([]any, 0, len(seats)*seatColumns)
 
 	for i, seat := range seats {
-		n := i * 5
-		placeholders := fmt.Sprintf("($%d, $%d, $%d, $%d,$%d)", n+1, n+2, n+3, n+4, n+5)
+		n := i * seatColumns
+		placeholders := make([]string, seatColumns)
+		for c := range placeholders {
+			placeholders[c] = fmt.Sprintf("$%d", n+c+1)
+		}
 
-		valStrs = append(valStrs, placeholders)
-		valArgs = append(valArgs, seat.EventID, seat.SeatNumber, seat.Price, seat.Status, seat.Version)
+		valStrs = append(valStrs, "("+strings.Join(placeholders, ", ")+")")
+		valArgs = append(valArgs,
+			seat.EventID, seat.SeatNumber, seat.Zone, seat.Price.Amount, seat.Status, seat.Version,
+			seat.Section, seat.Row, seat.Position, seat.X, seat.Y, seat.Aisle,
+		)
 	}
 
-	query := "INSERT INTO seats (event_id, seat_number, price, status, version) VALUES %s"
+	query := `
+		INSERT INTO seats (
+			event_id, seat_number, zone, price, status, version,
+			section, row_label, position, pos_x, pos_y, is_aisle
+		) VALUES %s
+	`
 	query = fmt.Sprintf(query, strings.Join(valStrs, ","))
 
 	_, err := tx.ExecContext(ctx, query, valArgs...)
@@ -58,8 +92,13 @@ func (r *seatRepository) CreateSeatBatchTx(ctx context.Context, tx *sql.Tx, seat
 
 func (r *seatRepository) GetSeatsByEventID(ctx context.Context, eventID int64) ([]seat.Seat, error) {
 	query := `
-		SELECT id, event_id, seat_number, price, status, version
-		FROM seats WHERE event_id = $1 ORDER BY id ASC
+		SELECT s.id, s.event_id, s.seat_number, COALESCE(s.zone, ''), s.price, e.currency, s.status, s.version,
+			s.section, s.row_label, s.position, s.pos_x, s.pos_y, s.is_aisle,
+			rl.id, rl.price
+		FROM seats s
+		JOIN events e ON e.id = s.event_id
+		LEFT JOIN resale_listings rl ON rl.seat_id = s.id AND rl.status = 'LISTED'
+		WHERE s.event_id = $1 ORDER BY s.id ASC
 	`
 	rows, err := r.db.QueryContext(ctx, query, eventID)
 	if err != nil {
@@ -70,16 +109,31 @@ func (r *seatRepository) GetSeatsByEventID(ctx context.Context, eventID int64) (
 	var seats []seat.Seat
 	for rows.Next() {
 		var s seat.Seat
+		var listingID sql.NullInt64
+		var resalePrice sql.NullInt64
 		if err := rows.Scan(
 			&s.ID,
 			&s.EventID,
 			&s.SeatNumber,
-			&s.Price,
+			&s.Zone,
+			&s.Price.Amount,
+			&s.Price.Currency,
 			&s.Status,
 			&s.Version,
+			&s.Section,
+			&s.Row,
+			&s.Position,
+			&s.X,
+			&s.Y,
+			&s.Aisle,
+			&listingID,
+			&resalePrice,
 		); err != nil {
 			return nil, err
 		}
+		if listingID.Valid {
+			s.Resale = &seat.ResaleOffer{ListingID: listingID.Int64, Price: money.New(resalePrice.Int64, s.Price.Currency)}
+		}
 		seats = append(seats, s)
 	}
 
@@ -89,20 +143,169 @@ func (r *seatRepository) GetSeatsByEventID(ctx context.Context, eventID int64) (
 	return seats, nil
 }
 
+func (r *seatRepository) CountAvailableSeats(ctx context.Context, eventID int64) (int, error) {
+	query := `SELECT COUNT(*) FROM seats WHERE event_id = $1 AND status = 'AVAILABLE'`
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, eventID).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetSeatStatuses : ID and status of the given seats of the event, every seat when seatIDs is empty
+func (r *seatRepository) GetSeatStatuses(ctx context.Context, eventID int64, seatIDs []int64) ([]seat.Seat, error) {
+	query := `
+		SELECT id, status FROM seats
+		WHERE event_id = $1 AND (COALESCE(CARDINALITY($2::BIGINT[]), 0) = 0 OR id = ANY($2))
+		ORDER BY id ASC
+	`
+	rows, err := r.db.QueryContext(ctx, query, eventID, pq.Array(seatIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seats []seat.Seat
+	for rows.Next() {
+		var s seat.Seat
+		if err := rows.Scan(&s.ID, &s.Status); err != nil {
+			return nil, err
+		}
+		seats = append(seats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return seats, nil
+}
+
+// GetAvailableSeatIDsTx : lock up to limit AVAILABLE seats of the event, skip seats other requests hold
+func (r *seatRepository) GetAvailableSeatIDsTx(ctx context.Context, tx *sql.Tx, eventID int64, limit int) ([]int64, error) {
+	query := `
+		SELECT id FROM seats
+		WHERE event_id = $1 AND status = 'AVAILABLE'
+		ORDER BY id ASC
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`
+	rows, err := tx.QueryContext(ctx, query, eventID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seatIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		seatIDs = append(seatIDs, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return seatIDs, nil
+}
+
+// GetSeatsForUpdateTx : lock the seats in ascending ID order, so overlapping orders cannot deadlock
 func (r *seatRepository) GetSeatsForUpdateTx(ctx context.Context, tx *sql.Tx, seatIDs []int64) ([]seat.Seat, error) {
-	query := `SELECT id, status, price FROM seats WHERE id = ANY($1) FOR UPDATE`
+	query := `
+		SELECT s.id, s.event_id, s.zone, s.status, s.price, e.currency, s.version
+		FROM seats s JOIN events e ON e.id = s.event_id
+		WHERE s.id = ANY($1) ORDER BY s.id FOR UPDATE OF s
+	`
+	return r.querySeatsTx(ctx, tx, query, seatIDs)
+}
+
+// GetSeatsTx : read the seats without locking them, pair with ReserveSeatsVersionedTx
+func (r *seatRepository) GetSeatsTx(ctx context.Context, tx *sql.Tx, seatIDs []int64) ([]seat.Seat, error) {
+	query := `
+		SELECT s.id, s.event_id, s.zone, s.status, s.price, e.currency, s.version
+		FROM seats s JOIN events e ON e.id = s.event_id
+		WHERE s.id = ANY($1) ORDER BY s.id
+	`
+	return r.querySeatsTx(ctx, tx, query, seatIDs)
+}
+
+// GetSeatsSkipLockedTx : lock the seats nobody else holds a lock on, locked seats are left out of the result.
+// SKIP LOCKED over NOWAIT, so the caller still learns which seats were taken.
+func (r *seatRepository) GetSeatsSkipLockedTx(ctx context.Context, tx *sql.Tx, seatIDs []int64) ([]seat.Seat, error) {
+	query := `
+		SELECT s.id, s.event_id, s.zone, s.status, s.price, e.currency, s.version
+		FROM seats s JOIN events e ON e.id = s.event_id
+		WHERE s.id = ANY($1) ORDER BY s.id FOR UPDATE OF s SKIP LOCKED
+	`
+	return r.querySeatsTx(ctx, tx, query, seatIDs)
+}
+
+func (r *seatRepository) querySeatsTx(ctx context.Context, tx *sql.Tx, query string, seatIDs []int64) ([]seat.Seat, error) {
 	rows, err := tx.QueryContext(ctx, query, pq.Array(seatIDs))
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+
+	var seats []seat.Seat
+	for rows.Next() {
+		var s seat.Seat
+		if err := rows.Scan(
+			&s.ID,
+			&s.EventID,
+			&s.Zone,
+			&s.Status,
+			&s.Price.Amount,
+			&s.Price.Currency,
+			&s.Version,
+		); err != nil {
+			return nil, err
+		}
+		seats = append(seats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return seats, nil
+}
+
+// GetZoneSeatsForUpdateTx : lock every seat of the zone, so concurrent best-available picks wait in line
+func (r *seatRepository) GetZoneSeatsForUpdateTx(ctx context.Context, tx *sql.Tx, eventID int64, zone string) ([]seat.Seat, error) {
+	query := `
+		SELECT s.id, s.event_id, s.seat_number, s.zone, s.price, e.currency, s.status, s.version,
+			s.section, s.row_label, s.position, s.pos_x, s.pos_y, s.is_aisle
+		FROM seats s
+		JOIN events e ON e.id = s.event_id
+		WHERE s.event_id = $1 AND s.zone = $2
+		ORDER BY s.id ASC
+		FOR UPDATE OF s
+	`
+	rows, err := tx.QueryContext(ctx, query, eventID, zone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
 	var seats []seat.Seat
 	for rows.Next() {
 		var s seat.Seat
 		if err := rows.Scan(
 			&s.ID,
+			&s.EventID,
+			&s.SeatNumber,
+			&s.Zone,
+			&s.Price.Amount,
+			&s.Price.Currency,
 			&s.Status,
-			&s.Price,
+			&s.Version,
+			&s.Section,
+			&s.Row,
+			&s.Position,
+			&s.X,
+			&s.Y,
+			&s.Aisle,
 		); err != nil {
 			return nil, err
 		}
@@ -112,11 +315,15 @@ func (r *seatRepository) GetSeatsForUpdateTx(ctx context.Context, tx *sql.Tx, se
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+
+	if len(seats) == 0 {
+		return nil, errs.ErrZoneNotFound
+	}
 	return seats, nil
 }
 
 func (r *seatRepository) UpdateSeatsStatusTx(ctx context.Context, tx *sql.Tx, seatIDs []int64, status string) error {
-	query := `UPDATE seats SET status = $1 WHERE id = ANY($2)`
+	query := `UPDATE seats SET status = $1, version = version + 1 WHERE id = ANY($2)`
 	res, err := tx.ExecContext(ctx, query, status, pq.Array(seatIDs))
 	if err != nil {
 		return err
@@ -133,26 +340,183 @@ func (r *seatRepository) UpdateSeatsStatusTx(ctx context.Context, tx *sql.Tx, se
 	return nil
 }
 
-func (r *seatRepository) CancelSeatsTx(ctx context.Context, tx *sql.Tx, bookingID string) error {
+// ReserveSeatsVersionedTx : hold AVAILABLE seats only if nobody changed them since they were read.
+// Any seat whose version moved on fails the whole hold with ErrSeatVersionConflict
+func (r *seatRepository) ReserveSeatsVersionedTx(ctx context.Context, tx *sql.Tx, seats []seat.Seat) error {
+	ids := make([]int64, 0, len(seats))
+	versions := make([]int64, 0, len(seats))
+	for _, s := range seats {
+		ids = append(ids, s.ID)
+		versions = append(versions, int64(s.Version))
+	}
+
 	query := `
-		UPDATE seats SET status = 'AVAILABLE'
-		WHERE id IN (
-			SELECT seat_id FROM booking_items 
-			WHERE booking_id = $1
+		UPDATE seats SET status = 'RESERVED', version = seats.version + 1
+		FROM UNNEST($1::BIGINT[], $2::INT[]) AS v(id, version)
+		WHERE seats.id = v.id AND seats.version = v.version AND seats.status = 'AVAILABLE'
+	`
+	res, err := tx.ExecContext(ctx, query, pq.Array(ids), pq.Array(versions))
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows != int64(len(seats)) {
+		return errs.ErrSeatVersionConflict
+	}
+	return nil
+}
+
+// CancelSeatsTx : release every active seat of the booking and close its open resale listings,
+// returns the freed seat IDs, fails with ErrSeatCheckedIn once any seat was scanned at the gate
+func (r *seatRepository) CancelSeatsTx(ctx context.Context, tx *sql.Tx, bookingID string) ([]int64, error) {
+	if err := lockUnscannedTx(ctx, tx, bookingID, nil); err != nil {
+		return nil, err
+	}
+
+	query := `
+		WITH released AS (
+			UPDATE booking_items SET released_at = NOW()
+			WHERE booking_id = $1 AND released_at IS NULL AND checked_in_at IS NULL
+			RETURNING seat_id
+		), delisted AS (
+			UPDATE resale_listings SET status = 'CANCELLED'
+			WHERE seller_booking_id = $1 AND status = 'LISTED'
 		)
+		UPDATE seats SET status = 'AVAILABLE', version = version + 1
+		WHERE id IN (SELECT seat_id FROM released)
+		RETURNING id
 	`
-	res, err := tx.ExecContext(ctx, query, bookingID)
+	rows, err := tx.QueryContext(ctx, query, bookingID)
 	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seatIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		seatIDs = append(seatIDs, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(seatIDs) == 0 {
+		return nil, errs.ErrBookingNotFound
+	}
+	return seatIDs, nil
+}
+
+// RemoveSeatsTx : drop only the given seats from a booking, free them and close their open resale listings,
+// fails with ErrSeatCheckedIn if one of them was scanned at the gate
+func (r *seatRepository) RemoveSeatsTx(ctx context.Context, tx *sql.Tx, bookingID string, seatIDs []int64) error {
+	if err := lockUnscannedTx(ctx, tx, bookingID, seatIDs); err != nil {
 		return err
 	}
-	
+
+	query := `
+		WITH removed AS (
+			DELETE FROM booking_items
+			WHERE booking_id = $1 AND seat_id = ANY($2) AND released_at IS NULL AND checked_in_at IS NULL
+			RETURNING seat_id
+		), delisted AS (
+			UPDATE resale_listings SET status = 'CANCELLED'
+			WHERE seller_booking_id = $1 AND seat_id = ANY($2) AND status = 'LISTED'
+		)
+		UPDATE seats SET status = 'AVAILABLE', version = seats.version + 1
+		FROM removed
+		WHERE seats.id = removed.seat_id
+	`
+	res, err := tx.ExecContext(ctx, query, bookingID, pq.Array(seatIDs))
+	if err != nil {
+		return err
+	}
+
 	rows, err := res.RowsAffected()
 	if err != nil {
 		return err
 	}
-	
-	if rows == 0 {
-		return errs.ErrBookingNotFound
+
+	if rows != int64(len(seatIDs)) {
+		return errs.ErrSeatNotInBooking
+	}
+	return nil
+}
+
+// lockUnscannedTx : lock the booking's active items, all of them when seatIDs is nil,
+// so a check-in waits for the release, and refuse if one was already scanned
+func lockUnscannedTx(ctx context.Context, tx *sql.Tx, bookingID string, seatIDs []int64) error {
+	query := `
+		SELECT checked_in_at IS NOT NULL FROM booking_items
+		WHERE booking_id = $1 AND released_at IS NULL
+			AND ($2::bigint[] IS NULL OR seat_id = ANY($2))
+		FOR UPDATE
+	`
+	rows, err := tx.QueryContext(ctx, query, bookingID, pq.Array(seatIDs))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var scanned bool
+	for rows.Next() {
+		var checkedIn bool
+		if err := rows.Scan(&checkedIn); err != nil {
+			return err
+		}
+		scanned = scanned || checkedIn
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if scanned {
+		return errs.ErrSeatCheckedIn
 	}
 	return nil
-}
\ No newline at end of file
+}
+
+// SellSeatsTx : mark every active seat of the booking SOLD, returns the sold seat IDs
+func (r *seatRepository) SellSeatsTx(ctx context.Context, tx *sql.Tx, bookingID string) ([]int64, error) {
+	query := `
+		UPDATE seats SET status = 'SOLD', version = version + 1
+		WHERE id IN (
+			SELECT seat_id FROM booking_items
+			WHERE booking_id = $1 AND released_at IS NULL
+		)
+		RETURNING id
+	`
+	rows, err := tx.QueryContext(ctx, query, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seatIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		seatIDs = append(seatIDs, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(seatIDs) == 0 {
+		return nil, errs.ErrBookingNotFound
+	}
+	return seatIDs, nil
+}
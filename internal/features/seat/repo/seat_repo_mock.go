@@ -37,11 +37,12 @@ func (m *MockSeatRepository) EXPECT() *MockSeatRepositoryMockRecorder {
 }
 
 // CancelSeatsTx mocks base method.
-func (m *MockSeatRepository) CancelSeatsTx(ctx context.Context, tx *sql.Tx, bookingID string) error {
+func (m *MockSeatRepository) CancelSeatsTx(ctx context.Context, tx *sql.Tx, bookingID string) ([]int64, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "CancelSeatsTx", ctx, tx, bookingID)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // CancelSeatsTx indicates an expected call of CancelSeatsTx.
@@ -50,6 +51,21 @@ func (mr *MockSeatRepositoryMockRecorder) CancelSeatsTx(ctx, tx, bookingID inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSeatsTx", reflect.TypeOf((*MockSeatRepository)(nil).CancelSeatsTx), ctx, tx, bookingID)
 }
 
+// CountAvailableSeats mocks base method.
+func (m *MockSeatRepository) CountAvailableSeats(ctx context.Context, eventID int64) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountAvailableSeats", ctx, eventID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountAvailableSeats indicates an expected call of CountAvailableSeats.
+func (mr *MockSeatRepositoryMockRecorder) CountAvailableSeats(ctx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAvailableSeats", reflect.TypeOf((*MockSeatRepository)(nil).CountAvailableSeats), ctx, eventID)
+}
+
 // CreateSeatBatchTx mocks base method.
 func (m *MockSeatRepository) CreateSeatBatchTx(ctx context.Context, tx *sql.Tx, seats []seat.Seat) error {
 	m.ctrl.T.Helper()
@@ -64,6 +80,36 @@ func (mr *MockSeatRepositoryMockRecorder) CreateSeatBatchTx(ctx, tx, seats inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSeatBatchTx", reflect.TypeOf((*MockSeatRepository)(nil).CreateSeatBatchTx), ctx, tx, seats)
 }
 
+// GetAvailableSeatIDsTx mocks base method.
+func (m *MockSeatRepository) GetAvailableSeatIDsTx(ctx context.Context, tx *sql.Tx, eventID int64, limit int) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAvailableSeatIDsTx", ctx, tx, eventID, limit)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAvailableSeatIDsTx indicates an expected call of GetAvailableSeatIDsTx.
+func (mr *MockSeatRepositoryMockRecorder) GetAvailableSeatIDsTx(ctx, tx, eventID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAvailableSeatIDsTx", reflect.TypeOf((*MockSeatRepository)(nil).GetAvailableSeatIDsTx), ctx, tx, eventID, limit)
+}
+
+// GetSeatStatuses mocks base method.
+func (m *MockSeatRepository) GetSeatStatuses(ctx context.Context, eventID int64, seatIDs []int64) ([]seat.Seat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeatStatuses", ctx, eventID, seatIDs)
+	ret0, _ := ret[0].([]seat.Seat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeatStatuses indicates an expected call of GetSeatStatuses.
+func (mr *MockSeatRepositoryMockRecorder) GetSeatStatuses(ctx, eventID, seatIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeatStatuses", reflect.TypeOf((*MockSeatRepository)(nil).GetSeatStatuses), ctx, eventID, seatIDs)
+}
+
 // GetSeatsByEventID mocks base method.
 func (m *MockSeatRepository) GetSeatsByEventID(ctx context.Context, eventID int64) ([]seat.Seat, error) {
 	m.ctrl.T.Helper()
@@ -94,6 +140,94 @@ func (mr *MockSeatRepositoryMockRecorder) GetSeatsForUpdateTx(ctx, tx, seatIDs i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeatsForUpdateTx", reflect.TypeOf((*MockSeatRepository)(nil).GetSeatsForUpdateTx), ctx, tx, seatIDs)
 }
 
+// GetSeatsSkipLockedTx mocks base method.
+func (m *MockSeatRepository) GetSeatsSkipLockedTx(ctx context.Context, tx *sql.Tx, seatIDs []int64) ([]seat.Seat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeatsSkipLockedTx", ctx, tx, seatIDs)
+	ret0, _ := ret[0].([]seat.Seat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeatsSkipLockedTx indicates an expected call of GetSeatsSkipLockedTx.
+func (mr *MockSeatRepositoryMockRecorder) GetSeatsSkipLockedTx(ctx, tx, seatIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeatsSkipLockedTx", reflect.TypeOf((*MockSeatRepository)(nil).GetSeatsSkipLockedTx), ctx, tx, seatIDs)
+}
+
+// GetSeatsTx mocks base method.
+func (m *MockSeatRepository) GetSeatsTx(ctx context.Context, tx *sql.Tx, seatIDs []int64) ([]seat.Seat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSeatsTx", ctx, tx, seatIDs)
+	ret0, _ := ret[0].([]seat.Seat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSeatsTx indicates an expected call of GetSeatsTx.
+func (mr *MockSeatRepositoryMockRecorder) GetSeatsTx(ctx, tx, seatIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSeatsTx", reflect.TypeOf((*MockSeatRepository)(nil).GetSeatsTx), ctx, tx, seatIDs)
+}
+
+// GetZoneSeatsForUpdateTx mocks base method.
+func (m *MockSeatRepository) GetZoneSeatsForUpdateTx(ctx context.Context, tx *sql.Tx, eventID int64, zone string) ([]seat.Seat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetZoneSeatsForUpdateTx", ctx, tx, eventID, zone)
+	ret0, _ := ret[0].([]seat.Seat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetZoneSeatsForUpdateTx indicates an expected call of GetZoneSeatsForUpdateTx.
+func (mr *MockSeatRepositoryMockRecorder) GetZoneSeatsForUpdateTx(ctx, tx, eventID, zone interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetZoneSeatsForUpdateTx", reflect.TypeOf((*MockSeatRepository)(nil).GetZoneSeatsForUpdateTx), ctx, tx, eventID, zone)
+}
+
+// RemoveSeatsTx mocks base method.
+func (m *MockSeatRepository) RemoveSeatsTx(ctx context.Context, tx *sql.Tx, bookingID string, seatIDs []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSeatsTx", ctx, tx, bookingID, seatIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveSeatsTx indicates an expected call of RemoveSeatsTx.
+func (mr *MockSeatRepositoryMockRecorder) RemoveSeatsTx(ctx, tx, bookingID, seatIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSeatsTx", reflect.TypeOf((*MockSeatRepository)(nil).RemoveSeatsTx), ctx, tx, bookingID, seatIDs)
+}
+
+// ReserveSeatsVersionedTx mocks base method.
+func (m *MockSeatRepository) ReserveSeatsVersionedTx(ctx context.Context, tx *sql.Tx, seats []seat.Seat) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveSeatsVersionedTx", ctx, tx, seats)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReserveSeatsVersionedTx indicates an expected call of ReserveSeatsVersionedTx.
+func (mr *MockSeatRepositoryMockRecorder) ReserveSeatsVersionedTx(ctx, tx, seats interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveSeatsVersionedTx", reflect.TypeOf((*MockSeatRepository)(nil).ReserveSeatsVersionedTx), ctx, tx, seats)
+}
+
+// SellSeatsTx mocks base method.
+func (m *MockSeatRepository) SellSeatsTx(ctx context.Context, tx *sql.Tx, bookingID string) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SellSeatsTx", ctx, tx, bookingID)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SellSeatsTx indicates an expected call of SellSeatsTx.
+func (mr *MockSeatRepositoryMockRecorder) SellSeatsTx(ctx, tx, bookingID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SellSeatsTx", reflect.TypeOf((*MockSeatRepository)(nil).SellSeatsTx), ctx, tx, bookingID)
+}
+
 // UpdateSeatsStatusTx mocks base method.
 func (m *MockSeatRepository) UpdateSeatsStatusTx(ctx context.Context, tx *sql.Tx, seatIDs []int64, status string) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,53 @@
+package seat
+
+import "sort"
+
+// Section : one block of the venue, rows run from the stage (lowest y) backwards
+type Section struct {
+	Name string `json:"name"`
+	Rows []Row  `json:"rows"`
+}
+
+// Row : seats of one row sorted left to right by x
+type Row struct {
+	Label string `json:"label"`
+	Y     int    `json:"y"`
+	Seats []Seat `json:"seats"`
+}
+
+// GroupMap : group seats by section and row, sections keep the order they first appear in
+func GroupMap(seats []Seat) []Section {
+	sections := make([]Section, 0)
+	sectionIndex := make(map[string]int)
+	rowIndex := make(map[[2]string]int)
+
+	for _, s := range seats {
+		si, ok := sectionIndex[s.Section]
+		if !ok {
+			si = len(sections)
+			sectionIndex[s.Section] = si
+			sections = append(sections, Section{Name: s.Section})
+		}
+
+		key := [2]string{s.Section, s.Row}
+		ri, ok := rowIndex[key]
+		if !ok {
+			ri = len(sections[si].Rows)
+			rowIndex[key] = ri
+			sections[si].Rows = append(sections[si].Rows, Row{Label: s.Row, Y: s.Y})
+		}
+		sections[si].Rows[ri].Seats = append(sections[si].Rows[ri].Seats, s)
+	}
+
+	for _, sec := range sections {
+		sort.SliceStable(sec.Rows, func(a, b int) bool {
+			return sec.Rows[a].Y < sec.Rows[b].Y
+		})
+		for _, r := range sec.Rows {
+			sort.SliceStable(r.Seats, func(a, b int) bool {
+				return r.Seats[a].X < r.Seats[b].X
+			})
+		}
+	}
+	return sections
+}
@@ -0,0 +1,32 @@
+package seat_test
+
+import (
+	"testing"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/features/seat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupMap(t *testing.T) {
+	seats := []seat.Seat{
+		{ID: 1, Section: "L1", Row: "B", X: 1, Y: 1},
+		{ID: 2, Section: "L1", Row: "B", X: 0, Y: 1},
+		{ID: 3, Section: "L1", Row: "A", X: 0, Y: 0},
+		{ID: 4, Section: "R1", Row: "A", X: 10, Y: 0},
+	}
+
+	sections := seat.GroupMap(seats)
+
+	assert.Len(t, sections, 2)
+	assert.Equal(t, "L1", sections[0].Name)
+	assert.Equal(t, "R1", sections[1].Name)
+
+	rows := sections[0].Rows
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "A", rows[0].Label)
+	assert.Equal(t, "B", rows[1].Label)
+	assert.Equal(t, int64(2), rows[1].Seats[0].ID)
+	assert.Equal(t, int64(1), rows[1].Seats[1].ID)
+
+	assert.Empty(t, seat.GroupMap(nil))
+}
@@ -1,5 +1,10 @@
 package seat
 
+import (
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+)
+
 type SeatStatus string
 
 const (
@@ -8,11 +13,60 @@ const (
 	StatusSold      SeatStatus = "SOLD"
 )
 
+// LockStrategy : how CreateBooking keeps two orders from holding the same seat
+type LockStrategy string
+
+const (
+	// LockPessimistic : lock the seat rows with SELECT ... FOR UPDATE
+	LockPessimistic LockStrategy = "pessimistic"
+	// LockOptimistic : read without locks, hold with UPDATE ... WHERE version = read version
+	LockOptimistic LockStrategy = "optimistic"
+	// LockSkipLocked : lock with FOR UPDATE SKIP LOCKED, seats held by another order fail right away
+	LockSkipLocked LockStrategy = "skip_locked"
+)
+
 type Seat struct {
-	ID         int64      `json:"id" db:"id"`
-	EventID    int64      `json:"event_id" db:"event_id"`
-	SeatNumber string     `json:"seat_number" db:"seat_number"`
-	Price      float64    `json:"price" db:"price"`
-	Status     SeatStatus `json:"status" db:"status"`
-	Version    int        `json:"version" db:"version"`
+	ID         int64        `json:"id" db:"id"`
+	EventID    int64        `json:"event_id" db:"event_id"`
+	SeatNumber string       `json:"seat_number" db:"seat_number"`
+	Zone       string       `json:"zone" db:"zone"`
+	Section    string       `json:"section" db:"section"`
+	Row        string       `json:"row" db:"row_label"`
+	Position   int          `json:"position" db:"position"`
+	X          int          `json:"x" db:"pos_x"`
+	Y          int          `json:"y" db:"pos_y"`
+	Aisle      bool         `json:"aisle" db:"is_aisle"`
+	Price      money.Money  `json:"price" db:"price"`
+	Status     SeatStatus   `json:"status" db:"status"`
+	Version    int          `json:"version" db:"version"`
+	Resale     *ResaleOffer `json:"resale,omitempty" db:"-"`
+}
+
+// ResaleOffer : open resale listing of a SOLD seat
+type ResaleOffer struct {
+	ListingID int64       `json:"listing_id"`
+	Price     money.Money `json:"price"`
+}
+
+// CheckAvailable : every requested seat belongs to the event and is AVAILABLE.
+// Seats missing from seats do not exist or are locked by another order.
+func CheckAvailable(eventID int64, seatIDs []int64, seats []Seat) error {
+	available := make(map[int64]bool, len(seats))
+	for _, s := range seats {
+		if s.EventID != eventID {
+			return errs.ErrSeatNotInEvent
+		}
+		available[s.ID] = s.Status == StatusAvailable
+	}
+
+	var unavailable []int64
+	for _, id := range seatIDs {
+		if !available[id] {
+			unavailable = append(unavailable, id)
+		}
+	}
+	if len(unavailable) > 0 {
+		return &errs.SeatsUnavailableError{SeatIDs: unavailable}
+	}
+	return nil
 }
@@ -0,0 +1,66 @@
+package seat_test
+
+import (
+	"testing"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/seat"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckAvailable(t *testing.T) {
+	type testCase struct {
+		name           string
+		seatIDs        []int64
+		seats          []seat.Seat
+		expectedErr    error
+		expectedSeatID []int64
+	}
+
+	testCases := []testCase{
+		{
+			name:    "success",
+			seatIDs: []int64{1, 2},
+			seats: []seat.Seat{
+				{ID: 1, EventID: 10, Status: seat.StatusAvailable},
+				{ID: 2, EventID: 10, Status: seat.StatusAvailable},
+			},
+		},
+		{
+			name:    "fail seat of another event",
+			seatIDs: []int64{1, 2},
+			seats: []seat.Seat{
+				{ID: 1, EventID: 10, Status: seat.StatusAvailable},
+				{ID: 2, EventID: 11, Status: seat.StatusAvailable},
+			},
+			expectedErr: errs.ErrSeatNotInEvent,
+		},
+		{
+			name:    "fail missing and taken seats",
+			seatIDs: []int64{1, 2, 3},
+			seats: []seat.Seat{
+				{ID: 1, EventID: 10, Status: seat.StatusReserved},
+				{ID: 2, EventID: 10, Status: seat.StatusAvailable},
+			},
+			expectedErr:    errs.ErrSomeSeatNotAvailable,
+			expectedSeatID: []int64{1, 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := seat.CheckAvailable(10, tc.seatIDs, tc.seats)
+
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.expectedErr)
+
+			var unavailable *errs.SeatsUnavailableError
+			if tc.expectedSeatID != nil && assert.ErrorAs(t, err, &unavailable) {
+				assert.Equal(t, tc.expectedSeatID, unavailable.SeatIDs)
+			}
+		})
+	}
+}
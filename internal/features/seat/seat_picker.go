@@ -0,0 +1,141 @@
+package seat
+
+import (
+	"math"
+	"sort"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+)
+
+// PickBest : choose quantity AVAILABLE seats from a zone's seats.
+// seats must hold every seat of the zone, in any status, so each row's center is known.
+// A contiguous run in one row always wins over scattered seats.
+// Among runs the row nearest the stage wins, then the run closest to the row center.
+func PickBest(seats []Seat, quantity int) ([]Seat, error) {
+	if quantity <= 0 {
+		return nil, errs.ErrNotEnoughSeats
+	}
+
+	rows := groupRows(seats)
+
+	if run := bestRun(rows, quantity); run != nil {
+		return run, nil
+	}
+	return bestScattered(rows, quantity)
+}
+
+// seatRow : seats of one row sorted by x, rank 0 is the row nearest the stage
+type seatRow struct {
+	rank   int
+	center float64
+	seats  []Seat
+}
+
+// groupRows : split the seats into rows, rows with a lower y sit nearer the stage
+func groupRows(seats []Seat) []seatRow {
+	index := make(map[[3]string]int)
+	var rows []seatRow
+
+	for _, s := range seats {
+		key := [3]string{s.Zone, s.Section, s.Row}
+		i, ok := index[key]
+		if !ok {
+			i = len(rows)
+			index[key] = i
+			rows = append(rows, seatRow{})
+		}
+		rows[i].seats = append(rows[i].seats, s)
+	}
+
+	for i := range rows {
+		r := &rows[i]
+		sort.SliceStable(r.seats, func(a, b int) bool {
+			return r.seats[a].X < r.seats[b].X
+		})
+		r.center = float64(r.seats[0].X+r.seats[len(r.seats)-1].X) / 2
+	}
+
+	sort.SliceStable(rows, func(a, b int) bool {
+		return rows[a].seats[0].Y < rows[b].seats[0].Y
+	})
+	for i := range rows {
+		rows[i].rank = i
+	}
+	return rows
+}
+
+// bestRun : the best window of quantity adjacent AVAILABLE seats, nil when no row has one
+func bestRun(rows []seatRow, quantity int) []Seat {
+	var best []Seat
+	bestRank, bestDist := math.MaxInt, math.MaxFloat64
+
+	for _, r := range rows {
+		for start := 0; start+quantity <= len(r.seats); start++ {
+			window := r.seats[start : start+quantity]
+			if !adjacent(window) {
+				continue
+			}
+
+			first, last := window[0].X, window[len(window)-1].X
+			dist := math.Abs(float64(first+last)/2 - r.center)
+
+			if r.rank < bestRank || (r.rank == bestRank && dist < bestDist) {
+				best, bestRank, bestDist = window, r.rank, dist
+			}
+		}
+	}
+	return best
+}
+
+// bestScattered : the quantity AVAILABLE seats nearest the stage and the row centers
+func bestScattered(rows []seatRow, quantity int) ([]Seat, error) {
+	type candidate struct {
+		seat Seat
+		rank int
+		dist float64
+	}
+
+	var candidates []candidate
+	for _, r := range rows {
+		for _, s := range r.seats {
+			if s.Status != StatusAvailable {
+				continue
+			}
+			candidates = append(candidates, candidate{
+				seat: s,
+				rank: r.rank,
+				dist: math.Abs(float64(s.X) - r.center),
+			})
+		}
+	}
+
+	if len(candidates) < quantity {
+		return nil, errs.ErrNotEnoughSeats
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool {
+		if candidates[a].rank != candidates[b].rank {
+			return candidates[a].rank < candidates[b].rank
+		}
+		return candidates[a].dist < candidates[b].dist
+	})
+
+	picked := make([]Seat, 0, quantity)
+	for _, c := range candidates[:quantity] {
+		picked = append(picked, c.seat)
+	}
+	return picked, nil
+}
+
+// adjacent : every seat is AVAILABLE and sits right next to the previous one, an aisle breaks the run
+func adjacent(window []Seat) bool {
+	for i, s := range window {
+		if s.Status != StatusAvailable {
+			return false
+		}
+		if i > 0 && s.X != window[i-1].X+1 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+package seat_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/seat"
+	"github.com/stretchr/testify/assert"
+)
+
+// zoneSeats : one row of the zone, taken lists the positions that are not AVAILABLE
+func zoneSeats(zone string, count int, taken ...int) []seat.Seat {
+	return rowSeats(zone, "1", 0, 0, count, taken...)
+}
+
+// rowSeats : count seats of row at depth y, IDs start after idBase
+func rowSeats(zone, row string, y int, idBase int64, count int, taken ...int) []seat.Seat {
+	seats := make([]seat.Seat, 0, count)
+	for i := 1; i <= count; i++ {
+		seats = append(seats, seat.Seat{
+			ID:         idBase + int64(i),
+			SeatNumber: fmt.Sprintf("%s-%s%d", zone, row, i),
+			Zone:       zone,
+			Section:    zone,
+			Row:        row,
+			Position:   i,
+			X:          i - 1,
+			Y:          y,
+			Status:     seat.StatusAvailable,
+		})
+	}
+	for _, p := range taken {
+		seats[p-1].Status = seat.StatusSold
+	}
+	return seats
+}
+
+// aisleRow : six seats with an aisle after the third one
+func aisleRow() []seat.Seat {
+	seats := zoneSeats("A", 6, 1)
+	for i := 3; i < len(seats); i++ {
+		seats[i].X++
+	}
+	seats[2].Aisle, seats[3].Aisle = true, true
+	return seats
+}
+
+func TestPickBest(t *testing.T) {
+	type testCase struct {
+		name        string
+		seats       []seat.Seat
+		quantity    int
+		expectedIDs []int64
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:        "center of an empty row",
+			seats:       zoneSeats("A", 10),
+			quantity:    2,
+			expectedIDs: []int64{5, 6},
+		},
+		{
+			name:        "run next to a taken center",
+			seats:       zoneSeats("A", 9, 4, 5, 6),
+			quantity:    3,
+			expectedIDs: []int64{1, 2, 3},
+		},
+		{
+			name:        "adjacent run beats closer scattered seats",
+			seats:       zoneSeats("A", 10, 3, 5, 7),
+			quantity:    3,
+			expectedIDs: []int64{8, 9, 10},
+		},
+		{
+			name:        "scattered when no run fits",
+			seats:       zoneSeats("A", 7, 2, 4, 6),
+			quantity:    2,
+			expectedIDs: []int64{3, 5},
+		},
+		{
+			name:        "front row first",
+			seats:       append(rowSeats("A", "B", 1, 10, 6), rowSeats("A", "A", 0, 0, 6, 3)...),
+			quantity:    2,
+			expectedIDs: []int64{4, 5},
+		},
+		{
+			name:        "back row when the front row has no run",
+			seats:       append(rowSeats("A", "A", 0, 0, 4, 2, 4), rowSeats("A", "B", 1, 10, 4)...),
+			quantity:    2,
+			expectedIDs: []int64{12, 13},
+		},
+		{
+			name:        "aisle breaks a run",
+			seats:       aisleRow(),
+			quantity:    3,
+			expectedIDs: []int64{4, 5, 6},
+		},
+		{
+			name:        "fail not enough seats",
+			seats:       zoneSeats("A", 4, 1, 2, 3),
+			quantity:    2,
+			expectedErr: errs.ErrNotEnoughSeats,
+		},
+		{
+			name:        "fail zero quantity",
+			seats:       zoneSeats("A", 4),
+			quantity:    0,
+			expectedErr: errs.ErrNotEnoughSeats,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			picked, err := seat.PickBest(tc.seats, tc.quantity)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			ids := make([]int64, 0, len(picked))
+			for _, s := range picked {
+				ids = append(ids, s.ID)
+			}
+			assert.ElementsMatch(t, tc.expectedIDs, ids)
+		})
+	}
+}
@@ -0,0 +1,6 @@
+package transferhandler
+
+type CreateTransferReq struct {
+	Recipient string  `json:"recipient_username" validate:"required"`
+	SeatIDs   []int64 `json:"seat_ids" validate:"required,min=1"`
+}
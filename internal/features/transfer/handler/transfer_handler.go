@@ -0,0 +1,121 @@
+package transferhandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	transferusecase "github.com/codepnw/stdlib-ticket-system/internal/features/transfer/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/helper"
+	"github.com/codepnw/stdlib-ticket-system/pkg/utils"
+)
+
+type transferHandler struct {
+	uc transferusecase.TransferUsecase
+}
+
+func NewTransferHandler(uc transferusecase.TransferUsecase) *transferHandler {
+	return &transferHandler{uc: uc}
+}
+
+func (h *transferHandler) CreateTransfer(w http.ResponseWriter, r *http.Request) {
+	var req CreateTransferReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := utils.Validate(&req); err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.uc.CreateTransfer(r.Context(), r.PathValue("booking_id"), req.Recipient, req.SeatIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrBookingNotFound), errors.Is(err, errs.ErrRecipientNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, errs.ErrBookingNotOwned):
+			helper.ErrorResponse(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, errs.ErrTransferToSelf):
+			helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errs.ErrBookingNotPaid), errors.Is(err, errs.ErrTransferClosed), errors.Is(err, errs.ErrTransferSeatsUnavailable),
+			errors.Is(err, errs.ErrSeatAlreadyListed):
+			helper.ErrorResponse(w, http.StatusConflict, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	helper.SuccessResponse(w, http.StatusCreated, "transfer created", data)
+}
+
+func (h *transferHandler) GetMyTransfers(w http.ResponseWriter, r *http.Request) {
+	data, err := h.uc.GetMyTransfers(r.Context())
+	if err != nil {
+		helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	helper.SuccessResponse(w, http.StatusOK, "", data)
+}
+
+func (h *transferHandler) AcceptTransfer(w http.ResponseWriter, r *http.Request) {
+	transferID, err := helper.ParseInt64(r.PathValue("transfer_id"))
+	if err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.uc.AcceptTransfer(r.Context(), transferID)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrTransferNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, errs.ErrTransferNotOwned):
+			helper.ErrorResponse(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, errs.ErrTransferNotPending), errors.Is(err, errs.ErrTransferClosed),
+			errors.Is(err, errs.ErrTransferSeatsUnavailable), errors.Is(err, errs.ErrPurchaseLimitExceeded):
+			helper.ErrorResponse(w, http.StatusConflict, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	helper.SuccessResponse(w, http.StatusOK, "transfer accepted", data)
+}
+
+func (h *transferHandler) DeclineTransfer(w http.ResponseWriter, r *http.Request) {
+	h.closeTransfer(w, r, h.uc.DeclineTransfer, "transfer declined")
+}
+
+func (h *transferHandler) CancelTransfer(w http.ResponseWriter, r *http.Request) {
+	h.closeTransfer(w, r, h.uc.CancelTransfer, "transfer cancelled")
+}
+
+func (h *transferHandler) closeTransfer(w http.ResponseWriter, r *http.Request, fn func(ctx context.Context, transferID int64) error, msg string) {
+	transferID, err := helper.ParseInt64(r.PathValue("transfer_id"))
+	if err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := fn(r.Context(), transferID); err != nil {
+		switch {
+		case errors.Is(err, errs.ErrTransferNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, errs.ErrTransferNotOwned):
+			helper.ErrorResponse(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, errs.ErrTransferNotPending):
+			helper.ErrorResponse(w, http.StatusConflict, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	helper.SuccessResponse(w, http.StatusOK, msg, nil)
+}
@@ -0,0 +1,209 @@
+package transferrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/transfer"
+	"github.com/lib/pq"
+)
+
+const transferColumns = `
+	t.id, t.event_id, t.from_booking_id, COALESCE(t.to_booking_id::TEXT, ''), t.from_user_id, t.to_user_id,
+	t.status, t.created_at, t.responded_at,
+	ARRAY(SELECT ts.seat_id FROM ticket_transfer_seats ts WHERE ts.transfer_id = t.id ORDER BY ts.seat_id)
+`
+
+//go:generate mockgen -source=transfer_repo.go -destination=transfer_repo_mock.go -package=transferrepo
+type TransferRepository interface {
+	GetByID(ctx context.Context, transferID int64) (transfer.Transfer, error)
+	GetByUserID(ctx context.Context, userID int64) ([]transfer.Transfer, error)
+
+	// Transaction
+	CreateTransferTx(ctx context.Context, tx *sql.Tx, input transfer.Transfer) (int64, error)
+	GetForUpdateTx(ctx context.Context, tx *sql.Tx, transferID int64) (transfer.Transfer, error)
+	CountPendingSeatsTx(ctx context.Context, tx *sql.Tx, seatIDs []int64) (int, error)
+	CountListedSeatsTx(ctx context.Context, tx *sql.Tx, seatIDs []int64) (int, error)
+	UpdateStatusTx(ctx context.Context, tx *sql.Tx, transferID int64, from, to transfer.TransferStatus) error
+	CompleteTx(ctx context.Context, tx *sql.Tx, transferID int64, toBookingID string) error
+}
+
+type transferRepository struct {
+	db *sql.DB
+}
+
+func NewTransferRepository(db *sql.DB) TransferRepository {
+	return &transferRepository{db: db}
+}
+
+func (r *transferRepository) GetByID(ctx context.Context, transferID int64) (transfer.Transfer, error) {
+	query := `SELECT ` + transferColumns + ` FROM ticket_transfers t WHERE t.id = $1`
+
+	t, err := scanTransfer(r.db.QueryRowContext(ctx, query, transferID).Scan)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return transfer.Transfer{}, errs.ErrTransferNotFound
+		}
+		return transfer.Transfer{}, err
+	}
+	return t, nil
+}
+
+// GetByUserID : transfers the user sent or received, newest first
+func (r *transferRepository) GetByUserID(ctx context.Context, userID int64) ([]transfer.Transfer, error) {
+	query := `
+		SELECT ` + transferColumns + ` FROM ticket_transfers t
+		WHERE t.from_user_id = $1 OR t.to_user_id = $1
+		ORDER BY t.created_at DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transfers []transfer.Transfer
+	for rows.Next() {
+		t, err := scanTransfer(rows.Scan)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+func (r *transferRepository) CreateTransferTx(ctx context.Context, tx *sql.Tx, input transfer.Transfer) (int64, error) {
+	query := `
+		INSERT INTO ticket_transfers (event_id, from_booking_id, from_user_id, to_user_id, status)
+		VALUES ($1, $2, $3, $4, $5) RETURNING id
+	`
+	var id int64
+
+	err := tx.QueryRowContext(ctx, query, input.EventID, input.FromBookingID, input.FromUserID, input.ToUserID, input.Status).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	seatQuery := `
+		INSERT INTO ticket_transfer_seats (transfer_id, seat_id)
+		SELECT $1, UNNEST($2::BIGINT[])
+	`
+	if _, err := tx.ExecContext(ctx, seatQuery, id, pq.Array(input.SeatIDs)); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *transferRepository) GetForUpdateTx(ctx context.Context, tx *sql.Tx, transferID int64) (transfer.Transfer, error) {
+	query := `SELECT ` + transferColumns + ` FROM ticket_transfers t WHERE t.id = $1 FOR UPDATE OF t`
+
+	t, err := scanTransfer(tx.QueryRowContext(ctx, query, transferID).Scan)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return transfer.Transfer{}, errs.ErrTransferNotFound
+		}
+		return transfer.Transfer{}, err
+	}
+	return t, nil
+}
+
+// CountPendingSeatsTx : how many of the seats are already offered in a PENDING transfer
+func (r *transferRepository) CountPendingSeatsTx(ctx context.Context, tx *sql.Tx, seatIDs []int64) (int, error) {
+	query := `
+		SELECT COUNT(*) FROM ticket_transfer_seats ts
+		JOIN ticket_transfers t ON t.id = ts.transfer_id
+		WHERE t.status = 'PENDING' AND ts.seat_id = ANY($1)
+	`
+	var count int
+	if err := tx.QueryRowContext(ctx, query, pq.Array(seatIDs)).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountListedSeatsTx : how many of the seats have an open resale listing
+func (r *transferRepository) CountListedSeatsTx(ctx context.Context, tx *sql.Tx, seatIDs []int64) (int, error) {
+	query := `SELECT COUNT(*) FROM resale_listings WHERE status = 'LISTED' AND seat_id = ANY($1)`
+
+	var count int
+	if err := tx.QueryRowContext(ctx, query, pq.Array(seatIDs)).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// UpdateStatusTx : move a transfer only if it is still in from
+func (r *transferRepository) UpdateStatusTx(ctx context.Context, tx *sql.Tx, transferID int64, from, to transfer.TransferStatus) error {
+	query := `
+		UPDATE ticket_transfers SET status = $3, responded_at = NOW()
+		WHERE id = $1 AND status = $2
+	`
+	res, err := tx.ExecContext(ctx, query, transferID, from, to)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errs.ErrTransferNotPending
+	}
+	return nil
+}
+
+// CompleteTx : mark a PENDING transfer ACCEPTED and link the recipient's booking
+func (r *transferRepository) CompleteTx(ctx context.Context, tx *sql.Tx, transferID int64, toBookingID string) error {
+	query := `
+		UPDATE ticket_transfers SET status = 'ACCEPTED', to_booking_id = $2, responded_at = NOW()
+		WHERE id = $1 AND status = 'PENDING'
+	`
+	res, err := tx.ExecContext(ctx, query, transferID, toBookingID)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errs.ErrTransferNotPending
+	}
+	return nil
+}
+
+func scanTransfer(scan func(dest ...any) error) (transfer.Transfer, error) {
+	var t transfer.Transfer
+	var respondedAt sql.NullTime
+
+	err := scan(
+		&t.ID,
+		&t.EventID,
+		&t.FromBookingID,
+		&t.ToBookingID,
+		&t.FromUserID,
+		&t.ToUserID,
+		&t.Status,
+		&t.CreatedAt,
+		&respondedAt,
+		pq.Array(&t.SeatIDs),
+	)
+	if err != nil {
+		return transfer.Transfer{}, err
+	}
+	if respondedAt.Valid {
+		t.RespondedAt = respondedAt.Time
+	}
+	return t, nil
+}
@@ -0,0 +1,155 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: transfer_repo.go
+
+// Package transferrepo is a generated GoMock package.
+package transferrepo
+
+import (
+	context "context"
+	sql "database/sql"
+	reflect "reflect"
+
+	transfer "github.com/codepnw/stdlib-ticket-system/internal/features/transfer"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTransferRepository is a mock of TransferRepository interface.
+type MockTransferRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransferRepositoryMockRecorder
+}
+
+// MockTransferRepositoryMockRecorder is the mock recorder for MockTransferRepository.
+type MockTransferRepositoryMockRecorder struct {
+	mock *MockTransferRepository
+}
+
+// NewMockTransferRepository creates a new mock instance.
+func NewMockTransferRepository(ctrl *gomock.Controller) *MockTransferRepository {
+	mock := &MockTransferRepository{ctrl: ctrl}
+	mock.recorder = &MockTransferRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTransferRepository) EXPECT() *MockTransferRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CompleteTx mocks base method.
+func (m *MockTransferRepository) CompleteTx(ctx context.Context, tx *sql.Tx, transferID int64, toBookingID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteTx", ctx, tx, transferID, toBookingID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteTx indicates an expected call of CompleteTx.
+func (mr *MockTransferRepositoryMockRecorder) CompleteTx(ctx, tx, transferID, toBookingID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTx", reflect.TypeOf((*MockTransferRepository)(nil).CompleteTx), ctx, tx, transferID, toBookingID)
+}
+
+// CountListedSeatsTx mocks base method.
+func (m *MockTransferRepository) CountListedSeatsTx(ctx context.Context, tx *sql.Tx, seatIDs []int64) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountListedSeatsTx", ctx, tx, seatIDs)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountListedSeatsTx indicates an expected call of CountListedSeatsTx.
+func (mr *MockTransferRepositoryMockRecorder) CountListedSeatsTx(ctx, tx, seatIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountListedSeatsTx", reflect.TypeOf((*MockTransferRepository)(nil).CountListedSeatsTx), ctx, tx, seatIDs)
+}
+
+// CountPendingSeatsTx mocks base method.
+func (m *MockTransferRepository) CountPendingSeatsTx(ctx context.Context, tx *sql.Tx, seatIDs []int64) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountPendingSeatsTx", ctx, tx, seatIDs)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountPendingSeatsTx indicates an expected call of CountPendingSeatsTx.
+func (mr *MockTransferRepositoryMockRecorder) CountPendingSeatsTx(ctx, tx, seatIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountPendingSeatsTx", reflect.TypeOf((*MockTransferRepository)(nil).CountPendingSeatsTx), ctx, tx, seatIDs)
+}
+
+// CreateTransferTx mocks base method.
+func (m *MockTransferRepository) CreateTransferTx(ctx context.Context, tx *sql.Tx, input transfer.Transfer) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransferTx", ctx, tx, input)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransferTx indicates an expected call of CreateTransferTx.
+func (mr *MockTransferRepositoryMockRecorder) CreateTransferTx(ctx, tx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransferTx", reflect.TypeOf((*MockTransferRepository)(nil).CreateTransferTx), ctx, tx, input)
+}
+
+// GetByID mocks base method.
+func (m *MockTransferRepository) GetByID(ctx context.Context, transferID int64) (transfer.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, transferID)
+	ret0, _ := ret[0].(transfer.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockTransferRepositoryMockRecorder) GetByID(ctx, transferID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockTransferRepository)(nil).GetByID), ctx, transferID)
+}
+
+// GetByUserID mocks base method.
+func (m *MockTransferRepository) GetByUserID(ctx context.Context, userID int64) ([]transfer.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID)
+	ret0, _ := ret[0].([]transfer.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockTransferRepositoryMockRecorder) GetByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockTransferRepository)(nil).GetByUserID), ctx, userID)
+}
+
+// GetForUpdateTx mocks base method.
+func (m *MockTransferRepository) GetForUpdateTx(ctx context.Context, tx *sql.Tx, transferID int64) (transfer.Transfer, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdateTx", ctx, tx, transferID)
+	ret0, _ := ret[0].(transfer.Transfer)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdateTx indicates an expected call of GetForUpdateTx.
+func (mr *MockTransferRepositoryMockRecorder) GetForUpdateTx(ctx, tx, transferID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdateTx", reflect.TypeOf((*MockTransferRepository)(nil).GetForUpdateTx), ctx, tx, transferID)
+}
+
+// UpdateStatusTx mocks base method.
+func (m *MockTransferRepository) UpdateStatusTx(ctx context.Context, tx *sql.Tx, transferID int64, from transfer.TransferStatus, to transfer.TransferStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatusTx", ctx, tx, transferID, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatusTx indicates an expected call of UpdateStatusTx.
+func (mr *MockTransferRepositoryMockRecorder) UpdateStatusTx(ctx, tx, transferID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatusTx", reflect.TypeOf((*MockTransferRepository)(nil).UpdateStatusTx), ctx, tx, transferID, from, to)
+}
@@ -0,0 +1,25 @@
+package transfer
+
+import "time"
+
+type TransferStatus string
+
+const (
+	StatusPending   TransferStatus = "PENDING"
+	StatusAccepted  TransferStatus = "ACCEPTED"
+	StatusDeclined  TransferStatus = "DECLINED"
+	StatusCancelled TransferStatus = "CANCELLED"
+)
+
+type Transfer struct {
+	ID            int64          `json:"id" db:"id"`
+	EventID       int64          `json:"event_id" db:"event_id"`
+	FromBookingID string         `json:"from_booking_id" db:"from_booking_id"`
+	ToBookingID   string         `json:"to_booking_id" db:"to_booking_id"`
+	FromUserID    int64          `json:"from_user_id" db:"from_user_id"`
+	ToUserID      int64          `json:"to_user_id" db:"to_user_id"`
+	Status        TransferStatus `json:"status" db:"status"`
+	SeatIDs       []int64        `json:"seat_ids" db:"-"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+	RespondedAt   time.Time      `json:"responded_at" db:"responded_at"`
+}
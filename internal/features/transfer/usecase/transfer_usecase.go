@@ -0,0 +1,356 @@
+package transferusecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/authcontext"
+	"github.com/codepnw/stdlib-ticket-system/internal/config"
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+	bookingrepo "github.com/codepnw/stdlib-ticket-system/internal/features/booking/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	eventrepo "github.com/codepnw/stdlib-ticket-system/internal/features/event/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/transfer"
+	transferrepo "github.com/codepnw/stdlib-ticket-system/internal/features/transfer/repo"
+	userrepo "github.com/codepnw/stdlib-ticket-system/internal/features/user/repo"
+	"github.com/codepnw/stdlib-ticket-system/pkg/database"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+)
+
+type TransferUsecase interface {
+	CreateTransfer(ctx context.Context, bookingID, recipient string, seatIDs []int64) (displayTransfer, error)
+	GetMyTransfers(ctx context.Context) ([]displayTransfer, error)
+	AcceptTransfer(ctx context.Context, transferID int64) (displayTransfer, error)
+	DeclineTransfer(ctx context.Context, transferID int64) error
+	CancelTransfer(ctx context.Context, transferID int64) error
+}
+
+type transferUsecase struct {
+	location     *time.Location
+	cutoff       time.Duration
+	tx           database.TxManager
+	transferRepo transferrepo.TransferRepository
+	bookRepo     bookingrepo.BookingRepository
+	eventRepo    eventrepo.EventRepository
+	userRepo     userrepo.UserRepository
+}
+
+func NewTransferUsecase(location *time.Location, cutoff time.Duration, tx database.TxManager, transferRepo transferrepo.TransferRepository, bookRepo bookingrepo.BookingRepository, eventRepo eventrepo.EventRepository, userRepo userrepo.UserRepository) TransferUsecase {
+	return &transferUsecase{
+		location:     location,
+		cutoff:       cutoff,
+		tx:           tx,
+		transferRepo: transferRepo,
+		bookRepo:     bookRepo,
+		eventRepo:    eventRepo,
+		userRepo:     userRepo,
+	}
+}
+
+type displayTransfer struct {
+	ID            int64   `json:"id"`
+	EventID       int64   `json:"event_id"`
+	FromBookingID string  `json:"from_booking_id"`
+	ToBookingID   string  `json:"to_booking_id,omitempty"`
+	FromUserID    int64   `json:"from_user_id"`
+	ToUserID      int64   `json:"to_user_id"`
+	Status        string  `json:"status"`
+	SeatIDs       []int64 `json:"seat_ids"`
+	CreatedAt     string  `json:"created_at"`
+	RespondedAt   string  `json:"responded_at,omitempty"`
+}
+
+func (u *transferUsecase) toDisplay(t transfer.Transfer) displayTransfer {
+	d := displayTransfer{
+		ID:            t.ID,
+		EventID:       t.EventID,
+		FromBookingID: t.FromBookingID,
+		ToBookingID:   t.ToBookingID,
+		FromUserID:    t.FromUserID,
+		ToUserID:      t.ToUserID,
+		Status:        string(t.Status),
+		SeatIDs:       t.SeatIDs,
+		CreatedAt:     t.CreatedAt.In(u.location).Format(time.DateTime),
+	}
+	if !t.RespondedAt.IsZero() {
+		d.RespondedAt = t.RespondedAt.In(u.location).Format(time.DateTime)
+	}
+	return d
+}
+
+func (u *transferUsecase) CreateTransfer(ctx context.Context, bookingID, recipient string, seatIDs []int64) (displayTransfer, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	userID := authcontext.GetUserID(ctx)
+
+	// 1. Check Booking
+	bookData, err := u.bookRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		return displayTransfer{}, err
+	}
+	if bookData.UserID != userID {
+		return displayTransfer{}, errs.ErrBookingNotOwned
+	}
+	if bookData.Status != booking.StatusPaid {
+		return displayTransfer{}, errs.ErrBookingNotPaid
+	}
+	// 2. Check Cutoff
+	eventData, err := u.eventRepo.GetEventByID(ctx, bookData.EventID)
+	if err != nil {
+		return displayTransfer{}, err
+	}
+	if err := u.checkCutoff(eventData); err != nil {
+		return displayTransfer{}, err
+	}
+	// 3. Check Recipient
+	to, err := u.userRepo.FindUsername(ctx, recipient)
+	if err != nil {
+		if errors.Is(err, errs.ErrInvalidCredentials) {
+			return displayTransfer{}, errs.ErrRecipientNotFound
+		}
+		return displayTransfer{}, err
+	}
+	if to.ID == userID {
+		return displayTransfer{}, errs.ErrTransferToSelf
+	}
+	// 4. Seats must be active and not yet used at the gate
+	seatIDs = uniqueIDs(seatIDs)
+	if err := u.checkSeats(ctx, bookingID, seatIDs); err != nil {
+		return displayTransfer{}, err
+	}
+
+	input := transfer.Transfer{
+		EventID:       bookData.EventID,
+		FromBookingID: bookingID,
+		FromUserID:    userID,
+		ToUserID:      to.ID,
+		Status:        transfer.StatusPending,
+		SeatIDs:       seatIDs,
+	}
+
+	err = u.tx.WithTx(ctx, func(tx *sql.Tx) error {
+		// Serialize transfers of this user, so a seat sits in one PENDING transfer at a time
+		if err := u.bookRepo.LockUserEventTx(ctx, tx, userID, bookData.EventID); err != nil {
+			return err
+		}
+		pending, err := u.transferRepo.CountPendingSeatsTx(ctx, tx, seatIDs)
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return errs.ErrTransferSeatsUnavailable
+		}
+		// A listed seat is for sale, the owner cancels the listing first
+		listed, err := u.transferRepo.CountListedSeatsTx(ctx, tx, seatIDs)
+		if err != nil {
+			return err
+		}
+		if listed > 0 {
+			return errs.ErrSeatAlreadyListed
+		}
+
+		input.ID, err = u.transferRepo.CreateTransferTx(ctx, tx, input)
+		return err
+	})
+	if err != nil {
+		return displayTransfer{}, err
+	}
+
+	input.CreatedAt = time.Now()
+	return u.toDisplay(input), nil
+}
+
+func (u *transferUsecase) GetMyTransfers(ctx context.Context) ([]displayTransfer, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	transfers, err := u.transferRepo.GetByUserID(ctx, authcontext.GetUserID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]displayTransfer, 0, len(transfers))
+	for _, t := range transfers {
+		result = append(result, u.toDisplay(t))
+	}
+	return result, nil
+}
+
+// AcceptTransfer : move the seats into a new PAID booking of the recipient.
+// The recipient paid nothing, so the new booking has no payment and cancelling it
+// fails with ErrBookingNotRefundable. A sender left without seats ends TRANSFERRED
+func (u *transferUsecase) AcceptTransfer(ctx context.Context, transferID int64) (displayTransfer, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	userID := authcontext.GetUserID(ctx)
+
+	// 1. Check Transfer
+	data, err := u.transferRepo.GetByID(ctx, transferID)
+	if err != nil {
+		return displayTransfer{}, err
+	}
+	if data.ToUserID != userID {
+		return displayTransfer{}, errs.ErrTransferNotOwned
+	}
+	// 2. Check Cutoff
+	eventData, err := u.eventRepo.GetEventByID(ctx, data.EventID)
+	if err != nil {
+		return displayTransfer{}, err
+	}
+	if err := u.checkCutoff(eventData); err != nil {
+		return displayTransfer{}, err
+	}
+	fees, err := u.eventRepo.GetFees(ctx, data.EventID)
+	if err != nil {
+		return displayTransfer{}, err
+	}
+
+	err = u.tx.WithTx(ctx, func(tx *sql.Tx) error {
+		// 3. Lock Transfer
+		data, err = u.transferRepo.GetForUpdateTx(ctx, tx, transferID)
+		if err != nil {
+			return err
+		}
+		if data.Status != transfer.StatusPending {
+			return errs.ErrTransferNotPending
+		}
+
+		// 4. Recipient limit, transferred seats count like purchased ones
+		if err := u.bookRepo.LockUserEventTx(ctx, tx, userID, data.EventID); err != nil {
+			return err
+		}
+		held, err := u.bookRepo.CountUserSeatsTx(ctx, tx, userID, data.EventID)
+		if err != nil {
+			return err
+		}
+		if err := eventData.PurchaseLimit(len(data.SeatIDs), held); err != nil {
+			return err
+		}
+
+		// 5. New PAID Booking for the recipient
+		toBookingID, err := u.bookRepo.CreateBookingTx(ctx, tx, booking.Booking{
+			UserID:         userID,
+			EventID:        data.EventID,
+			TotalAmount:    money.Zero(eventData.Currency),
+			DiscountAmount: money.Zero(eventData.Currency),
+			Status:         booking.StatusPaid,
+		})
+		if err != nil {
+			return err
+		}
+
+		// 6. Move Seats, fails if the sender cancelled or used any of them meanwhile
+		if err := u.bookRepo.MoveItemsTx(ctx, tx, data.FromBookingID, toBookingID, data.SeatIDs); err != nil {
+			return err
+		}
+		if _, err := u.bookRepo.RecalculateTotalTx(ctx, tx, toBookingID, fees, eventData.TaxPercent); err != nil {
+			return err
+		}
+		if _, err := u.bookRepo.RecalculateTotalTx(ctx, tx, data.FromBookingID, fees, eventData.TaxPercent); err != nil {
+			return err
+		}
+
+		// 7. Close the sender's booking once its last seat is gone
+		remaining, err := u.bookRepo.GetActiveSeatIDsTx(ctx, tx, data.FromBookingID)
+		if err != nil {
+			return err
+		}
+		if len(remaining) == 0 {
+			if err := u.bookRepo.UpdateStatusTx(ctx, tx, data.FromBookingID, booking.StatusPaid, booking.StatusTransferred); err != nil {
+				return err
+			}
+		}
+
+		// 8. Audit
+		if err := u.transferRepo.CompleteTx(ctx, tx, transferID, toBookingID); err != nil {
+			return err
+		}
+
+		data.Status = transfer.StatusAccepted
+		data.ToBookingID = toBookingID
+		data.RespondedAt = time.Now()
+		return nil
+	})
+	if err != nil {
+		return displayTransfer{}, err
+	}
+	return u.toDisplay(data), nil
+}
+
+// DeclineTransfer : the recipient turns the transfer down
+func (u *transferUsecase) DeclineTransfer(ctx context.Context, transferID int64) error {
+	return u.closeTransfer(ctx, transferID, transfer.StatusDeclined, func(t transfer.Transfer, userID int64) bool {
+		return t.ToUserID == userID
+	})
+}
+
+// CancelTransfer : the sender withdraws the transfer
+func (u *transferUsecase) CancelTransfer(ctx context.Context, transferID int64) error {
+	return u.closeTransfer(ctx, transferID, transfer.StatusCancelled, func(t transfer.Transfer, userID int64) bool {
+		return t.FromUserID == userID
+	})
+}
+
+func (u *transferUsecase) closeTransfer(ctx context.Context, transferID int64, to transfer.TransferStatus, allowed func(t transfer.Transfer, userID int64) bool) error {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	data, err := u.transferRepo.GetByID(ctx, transferID)
+	if err != nil {
+		return err
+	}
+	if !allowed(data, authcontext.GetUserID(ctx)) {
+		return errs.ErrTransferNotOwned
+	}
+	if data.Status != transfer.StatusPending {
+		return errs.ErrTransferNotPending
+	}
+
+	return u.tx.WithTx(ctx, func(tx *sql.Tx) error {
+		return u.transferRepo.UpdateStatusTx(ctx, tx, transferID, transfer.StatusPending, to)
+	})
+}
+
+func (u *transferUsecase) checkCutoff(e event.Event) error {
+	if time.Now().Add(u.cutoff).After(e.EventDate) {
+		return errs.ErrTransferClosed
+	}
+	return nil
+}
+
+func (u *transferUsecase) checkSeats(ctx context.Context, bookingID string, seatIDs []int64) error {
+	items, err := u.bookRepo.GetItems(ctx, bookingID)
+	if err != nil {
+		return err
+	}
+
+	transferable := make(map[int64]bool, len(items))
+	for _, i := range items {
+		if i.ReleasedAt == nil && i.CheckedInAt == nil {
+			transferable[i.SeatID] = true
+		}
+	}
+	for _, id := range seatIDs {
+		if !transferable[id] {
+			return errs.ErrTransferSeatsUnavailable
+		}
+	}
+	return nil
+}
+
+func uniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]bool, len(ids))
+	result := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
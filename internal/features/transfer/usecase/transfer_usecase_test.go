@@ -0,0 +1,412 @@
+package transferusecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/authcontext"
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+	bookingrepo "github.com/codepnw/stdlib-ticket-system/internal/features/booking/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	eventrepo "github.com/codepnw/stdlib-ticket-system/internal/features/event/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/transfer"
+	transferrepo "github.com/codepnw/stdlib-ticket-system/internal/features/transfer/repo"
+	transferusecase "github.com/codepnw/stdlib-ticket-system/internal/features/transfer/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/user"
+	userrepo "github.com/codepnw/stdlib-ticket-system/internal/features/user/repo"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var ErrMockDBError = errors.New("db error")
+
+type mockTx struct{}
+
+func (m mockTx) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	return fn(nil)
+}
+
+func (m mockTx) WithRetryTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	return fn(nil)
+}
+
+type mocks struct {
+	transfer *transferrepo.MockTransferRepository
+	book     *bookingrepo.MockBookingRepository
+	event    *eventrepo.MockEventRepository
+	user     *userrepo.MockUserRepository
+}
+
+func TestCreateTransfer(t *testing.T) {
+	paid := booking.Booking{ID: "mock-uuid-1", UserID: 1, EventID: 10, Status: booking.StatusPaid}
+	future := event.Event{ID: 10, Currency: "THB", EventDate: time.Now().Add(72 * time.Hour)}
+
+	type testCase struct {
+		name        string
+		recipient   string
+		seatIDs     []int64
+		mockFn      func(m mocks)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:      "success",
+			recipient: "friend",
+			seatIDs:   []int64{11, 11},
+			mockFn: func(m mocks) {
+				m.book.EXPECT().GetByID(gomock.Any(), paid.ID).Return(paid, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), paid.EventID).Return(future, nil).Times(1)
+
+				m.user.EXPECT().FindUsername(gomock.Any(), "friend").Return(user.User{ID: 2, Username: "friend"}, nil).Times(1)
+
+				mockItems := []booking.BookingItemResponse{{SeatID: 11}, {SeatID: 12}}
+				m.book.EXPECT().GetItems(gomock.Any(), paid.ID).Return(mockItems, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), paid.EventID).Return(nil).Times(1)
+
+				m.transfer.EXPECT().CountPendingSeatsTx(gomock.Any(), gomock.Any(), []int64{11}).Return(0, nil).Times(1)
+
+				m.transfer.EXPECT().CountListedSeatsTx(gomock.Any(), gomock.Any(), []int64{11}).Return(0, nil).Times(1)
+
+				m.transfer.EXPECT().CreateTransferTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sql.Tx, input transfer.Transfer) (int64, error) {
+						assert.Equal(t, transfer.StatusPending, input.Status)
+						assert.Equal(t, int64(2), input.ToUserID)
+						assert.Equal(t, []int64{11}, input.SeatIDs)
+						return 1, nil
+					}).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:      "fail seat listed for resale",
+			recipient: "friend",
+			seatIDs:   []int64{11},
+			mockFn: func(m mocks) {
+				m.book.EXPECT().GetByID(gomock.Any(), paid.ID).Return(paid, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), paid.EventID).Return(future, nil).Times(1)
+
+				m.user.EXPECT().FindUsername(gomock.Any(), "friend").Return(user.User{ID: 2, Username: "friend"}, nil).Times(1)
+
+				mockItems := []booking.BookingItemResponse{{SeatID: 11}}
+				m.book.EXPECT().GetItems(gomock.Any(), paid.ID).Return(mockItems, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), paid.EventID).Return(nil).Times(1)
+
+				m.transfer.EXPECT().CountPendingSeatsTx(gomock.Any(), gomock.Any(), []int64{11}).Return(0, nil).Times(1)
+
+				m.transfer.EXPECT().CountListedSeatsTx(gomock.Any(), gomock.Any(), []int64{11}).Return(1, nil).Times(1)
+			},
+			expectedErr: errs.ErrSeatAlreadyListed,
+		},
+		{
+			name:      "fail other user booking",
+			recipient: "friend",
+			seatIDs:   []int64{11},
+			mockFn: func(m mocks) {
+				data := paid
+				data.UserID = 3
+				m.book.EXPECT().GetByID(gomock.Any(), paid.ID).Return(data, nil).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotOwned,
+		},
+		{
+			name:      "fail booking not paid",
+			recipient: "friend",
+			seatIDs:   []int64{11},
+			mockFn: func(m mocks) {
+				data := paid
+				data.Status = booking.StatusPending
+				m.book.EXPECT().GetByID(gomock.Any(), paid.ID).Return(data, nil).Times(1)
+			},
+			expectedErr: errs.ErrBookingNotPaid,
+		},
+		{
+			name:      "fail close to event date",
+			recipient: "friend",
+			seatIDs:   []int64{11},
+			mockFn: func(m mocks) {
+				m.book.EXPECT().GetByID(gomock.Any(), paid.ID).Return(paid, nil).Times(1)
+
+				soon := event.Event{ID: 10, Currency: "THB", EventDate: time.Now().Add(time.Hour)}
+				m.event.EXPECT().GetEventByID(gomock.Any(), paid.EventID).Return(soon, nil).Times(1)
+			},
+			expectedErr: errs.ErrTransferClosed,
+		},
+		{
+			name:      "fail recipient not found",
+			recipient: "nobody",
+			seatIDs:   []int64{11},
+			mockFn: func(m mocks) {
+				m.book.EXPECT().GetByID(gomock.Any(), paid.ID).Return(paid, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), paid.EventID).Return(future, nil).Times(1)
+
+				m.user.EXPECT().FindUsername(gomock.Any(), "nobody").Return(user.User{}, errs.ErrInvalidCredentials).Times(1)
+			},
+			expectedErr: errs.ErrRecipientNotFound,
+		},
+		{
+			name:      "fail transfer to self",
+			recipient: "me",
+			seatIDs:   []int64{11},
+			mockFn: func(m mocks) {
+				m.book.EXPECT().GetByID(gomock.Any(), paid.ID).Return(paid, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), paid.EventID).Return(future, nil).Times(1)
+
+				m.user.EXPECT().FindUsername(gomock.Any(), "me").Return(user.User{ID: 1, Username: "me"}, nil).Times(1)
+			},
+			expectedErr: errs.ErrTransferToSelf,
+		},
+		{
+			name:      "fail seat already checked in",
+			recipient: "friend",
+			seatIDs:   []int64{11},
+			mockFn: func(m mocks) {
+				m.book.EXPECT().GetByID(gomock.Any(), paid.ID).Return(paid, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), paid.EventID).Return(future, nil).Times(1)
+
+				m.user.EXPECT().FindUsername(gomock.Any(), "friend").Return(user.User{ID: 2, Username: "friend"}, nil).Times(1)
+
+				checkedIn := time.Now()
+				mockItems := []booking.BookingItemResponse{{SeatID: 11, CheckedInAt: &checkedIn}}
+				m.book.EXPECT().GetItems(gomock.Any(), paid.ID).Return(mockItems, nil).Times(1)
+			},
+			expectedErr: errs.ErrTransferSeatsUnavailable,
+		},
+		{
+			name:      "fail seat in pending transfer",
+			recipient: "friend",
+			seatIDs:   []int64{11},
+			mockFn: func(m mocks) {
+				m.book.EXPECT().GetByID(gomock.Any(), paid.ID).Return(paid, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), paid.EventID).Return(future, nil).Times(1)
+
+				m.user.EXPECT().FindUsername(gomock.Any(), "friend").Return(user.User{ID: 2, Username: "friend"}, nil).Times(1)
+
+				m.book.EXPECT().GetItems(gomock.Any(), paid.ID).Return([]booking.BookingItemResponse{{SeatID: 11}}, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(1), paid.EventID).Return(nil).Times(1)
+
+				m.transfer.EXPECT().CountPendingSeatsTx(gomock.Any(), gomock.Any(), []int64{11}).Return(1, nil).Times(1)
+			},
+			expectedErr: errs.ErrTransferSeatsUnavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, m := setup(t)
+
+			// Mock FN
+			tc.mockFn(m)
+
+			ctx := authcontext.SetUserID(context.Background(), int64(1))
+			_, err := uc.CreateTransfer(ctx, paid.ID, tc.recipient, tc.seatIDs)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAcceptTransfer(t *testing.T) {
+	pending := transfer.Transfer{
+		ID:            1,
+		EventID:       10,
+		FromBookingID: "mock-uuid-1",
+		FromUserID:    1,
+		ToUserID:      2,
+		Status:        transfer.StatusPending,
+		SeatIDs:       []int64{11, 12},
+	}
+	future := event.Event{ID: 10, Currency: "THB", EventDate: time.Now().Add(72 * time.Hour)}
+
+	type testCase struct {
+		name        string
+		mockFn      func(m mocks)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "success",
+			mockFn: func(m mocks) {
+				m.transfer.EXPECT().GetByID(gomock.Any(), pending.ID).Return(pending, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), pending.EventID).Return(future, nil).Times(1)
+
+				m.event.EXPECT().GetFees(gomock.Any(), pending.EventID).Return(nil, nil).Times(1)
+
+				m.transfer.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), pending.ID).Return(pending, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(2), pending.EventID).Return(nil).Times(1)
+
+				m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(2), pending.EventID).Return(0, nil).Times(1)
+
+				m.book.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sql.Tx, input booking.Booking) (string, error) {
+						assert.Equal(t, booking.StatusPaid, input.Status)
+						assert.Equal(t, int64(2), input.UserID)
+						return "mock-uuid-2", nil
+					}).Times(1)
+
+				m.book.EXPECT().MoveItemsTx(gomock.Any(), gomock.Any(), "mock-uuid-1", "mock-uuid-2", pending.SeatIDs).Return(nil).Times(1)
+
+				m.book.EXPECT().RecalculateTotalTx(gomock.Any(), gomock.Any(), "mock-uuid-2", gomock.Any(), gomock.Any()).Return(money.New(20000, "THB"), nil).Times(1)
+
+				m.book.EXPECT().RecalculateTotalTx(gomock.Any(), gomock.Any(), "mock-uuid-1", gomock.Any(), gomock.Any()).Return(money.New(10000, "THB"), nil).Times(1)
+
+				m.book.EXPECT().GetActiveSeatIDsTx(gomock.Any(), gomock.Any(), "mock-uuid-1").Return([]int64{13}, nil).Times(1)
+
+				m.transfer.EXPECT().CompleteTx(gomock.Any(), gomock.Any(), pending.ID, "mock-uuid-2").Return(nil).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "success last seats close sender booking",
+			mockFn: func(m mocks) {
+				m.transfer.EXPECT().GetByID(gomock.Any(), pending.ID).Return(pending, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), pending.EventID).Return(future, nil).Times(1)
+
+				m.event.EXPECT().GetFees(gomock.Any(), pending.EventID).Return(nil, nil).Times(1)
+
+				m.transfer.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), pending.ID).Return(pending, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(2), pending.EventID).Return(nil).Times(1)
+
+				m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(2), pending.EventID).Return(0, nil).Times(1)
+
+				m.book.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).Return("mock-uuid-2", nil).Times(1)
+
+				m.book.EXPECT().MoveItemsTx(gomock.Any(), gomock.Any(), "mock-uuid-1", "mock-uuid-2", pending.SeatIDs).Return(nil).Times(1)
+
+				m.book.EXPECT().RecalculateTotalTx(gomock.Any(), gomock.Any(), "mock-uuid-2", gomock.Any(), gomock.Any()).Return(money.New(20000, "THB"), nil).Times(1)
+
+				m.book.EXPECT().RecalculateTotalTx(gomock.Any(), gomock.Any(), "mock-uuid-1", gomock.Any(), gomock.Any()).Return(money.Zero("THB"), nil).Times(1)
+
+				m.book.EXPECT().GetActiveSeatIDsTx(gomock.Any(), gomock.Any(), "mock-uuid-1").Return(nil, nil).Times(1)
+
+				m.book.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), "mock-uuid-1", booking.StatusPaid, booking.StatusTransferred).Return(nil).Times(1)
+
+				m.transfer.EXPECT().CompleteTx(gomock.Any(), gomock.Any(), pending.ID, "mock-uuid-2").Return(nil).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "fail not recipient",
+			mockFn: func(m mocks) {
+				data := pending
+				data.ToUserID = 3
+				m.transfer.EXPECT().GetByID(gomock.Any(), pending.ID).Return(data, nil).Times(1)
+			},
+			expectedErr: errs.ErrTransferNotOwned,
+		},
+		{
+			name: "fail not pending",
+			mockFn: func(m mocks) {
+				m.transfer.EXPECT().GetByID(gomock.Any(), pending.ID).Return(pending, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), pending.EventID).Return(future, nil).Times(1)
+
+				m.event.EXPECT().GetFees(gomock.Any(), pending.EventID).Return(nil, nil).Times(1)
+
+				data := pending
+				data.Status = transfer.StatusCancelled
+				m.transfer.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), pending.ID).Return(data, nil).Times(1)
+			},
+			expectedErr: errs.ErrTransferNotPending,
+		},
+		{
+			name: "fail recipient limit",
+			mockFn: func(m mocks) {
+				m.transfer.EXPECT().GetByID(gomock.Any(), pending.ID).Return(pending, nil).Times(1)
+
+				limited := future
+				limited.MaxSeatsPerUser = 4
+				m.event.EXPECT().GetEventByID(gomock.Any(), pending.EventID).Return(limited, nil).Times(1)
+
+				m.event.EXPECT().GetFees(gomock.Any(), pending.EventID).Return(nil, nil).Times(1)
+
+				m.transfer.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), pending.ID).Return(pending, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(2), pending.EventID).Return(nil).Times(1)
+
+				m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(2), pending.EventID).Return(3, nil).Times(1)
+			},
+			expectedErr: errs.ErrPurchaseLimitExceeded,
+		},
+		{
+			name: "fail seats used meanwhile",
+			mockFn: func(m mocks) {
+				m.transfer.EXPECT().GetByID(gomock.Any(), pending.ID).Return(pending, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), pending.EventID).Return(future, nil).Times(1)
+
+				m.event.EXPECT().GetFees(gomock.Any(), pending.EventID).Return(nil, nil).Times(1)
+
+				m.transfer.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), pending.ID).Return(pending, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), int64(2), pending.EventID).Return(nil).Times(1)
+
+				m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), int64(2), pending.EventID).Return(0, nil).Times(1)
+
+				m.book.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).Return("mock-uuid-2", nil).Times(1)
+
+				m.book.EXPECT().MoveItemsTx(gomock.Any(), gomock.Any(), "mock-uuid-1", "mock-uuid-2", pending.SeatIDs).Return(errs.ErrTransferSeatsUnavailable).Times(1)
+			},
+			expectedErr: errs.ErrTransferSeatsUnavailable,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, m := setup(t)
+
+			// Mock FN
+			tc.mockFn(m)
+
+			ctx := authcontext.SetUserID(context.Background(), int64(2))
+			_, err := uc.AcceptTransfer(ctx, pending.ID)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func setup(t *testing.T) (transferusecase.TransferUsecase, mocks) {
+	ctrl := gomock.NewController(t)
+
+	loc, _ := time.LoadLocation("Asia/Bangkok")
+
+	m := mocks{
+		transfer: transferrepo.NewMockTransferRepository(ctrl),
+		book:     bookingrepo.NewMockBookingRepository(ctrl),
+		event:    eventrepo.NewMockEventRepository(ctrl),
+		user:     userrepo.NewMockUserRepository(ctrl),
+	}
+	uc := transferusecase.NewTransferUsecase(loc, 24*time.Hour, mockTx{}, m.transfer, m.book, m.event, m.user)
+
+	return uc, m
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/lib/pq"
 )
 
+//go:generate mockgen -source=user_repo.go -destination=user_repo_mock.go -package=userrepo
 type UserRepository interface {
 	CreateUser(ctx context.Context, input user.User) (user.User, error)
 	FindUsername(ctx context.Context, username string) (user.User, error)
@@ -29,10 +30,11 @@ func NewUserRepository(db *sql.DB) UserRepository {
 func (r *userRepository) CreateUser(ctx context.Context, input user.User) (user.User, error) {
 	query := `
 		INSERT INTO users (username, hash_password)
-		VALUES ($1, $2) RETURNING id
+		VALUES ($1, $2) RETURNING id, role
 	`
 	err := r.db.QueryRowContext(ctx, query, input.Username, input.HashPassword).Scan(
 		&input.ID,
+		&input.Role,
 	)
 	if err != nil {
 		if pqErr := err.(*pq.Error); pqErr.Code == pq.ErrorCode("23505") {
@@ -45,7 +47,7 @@ func (r *userRepository) CreateUser(ctx context.Context, input user.User) (user.
 
 func (r *userRepository) FindUsername(ctx context.Context, username string) (user.User, error) {
 	query := `
-		SELECT id, username, hash_password
+		SELECT id, username, hash_password, role
 		FROM users WHERE username = $1 LIMIT 1
 	`
 	var u user.User
@@ -53,6 +55,7 @@ func (r *userRepository) FindUsername(ctx context.Context, username string) (use
 		&u.ID,
 		&u.Username,
 		&u.HashPassword,
+		&u.Role,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -2,10 +2,18 @@ package user
 
 import "time"
 
+type Role string
+
+const (
+	RoleCustomer Role = "CUSTOMER"
+	RoleStaff    Role = "STAFF"
+)
+
 type User struct {
 	ID           int64  `json:"id" db:"id"`
 	Username     string `json:"username" db:"username"`
 	HashPassword string `json:"-" db:"hash_password"`
+	Role         Role   `json:"role" db:"role"`
 }
 
 type Auth struct {
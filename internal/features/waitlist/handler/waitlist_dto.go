@@ -0,0 +1,5 @@
+package waitlisthandler
+
+type JoinWaitlistReq struct {
+	SeatCount int `json:"seat_count" validate:"required,gt=0"`
+}
@@ -0,0 +1,114 @@
+package waitlisthandler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	waitlistusecase "github.com/codepnw/stdlib-ticket-system/internal/features/waitlist/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/helper"
+	"github.com/codepnw/stdlib-ticket-system/pkg/utils"
+)
+
+type waitlistHandler struct {
+	uc waitlistusecase.WaitlistUsecase
+}
+
+func NewWaitlistHandler(uc waitlistusecase.WaitlistUsecase) *waitlistHandler {
+	return &waitlistHandler{uc: uc}
+}
+
+func (h *waitlistHandler) JoinWaitlist(w http.ResponseWriter, r *http.Request) {
+	eventID, err := helper.ParseInt64(r.PathValue("event_id"))
+	if err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var req JoinWaitlistReq
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := utils.Validate(&req); err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.uc.JoinWaitlist(r.Context(), eventID, req.SeatCount)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrEventNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, errs.ErrWaitlistAlreadyJoined), errors.Is(err, errs.ErrSeatsStillAvailable), errors.Is(err, errs.ErrPurchaseLimitExceeded):
+			helper.ErrorResponse(w, http.StatusConflict, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	helper.SuccessResponse(w, http.StatusCreated, "joined waitlist", data)
+}
+
+func (h *waitlistHandler) GetMyEntries(w http.ResponseWriter, r *http.Request) {
+	data, err := h.uc.GetMyEntries(r.Context())
+	if err != nil {
+		helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	helper.SuccessResponse(w, http.StatusOK, "", data)
+}
+
+func (h *waitlistHandler) AcceptOffer(w http.ResponseWriter, r *http.Request) {
+	entryID, err := helper.ParseInt64(r.PathValue("entry_id"))
+	if err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	data, err := h.uc.AcceptOffer(r.Context(), entryID)
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrWaitlistEntryNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, errs.ErrWaitlistNotOwned):
+			helper.ErrorResponse(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, errs.ErrOfferNotActive), errors.Is(err, errs.ErrOfferExpired):
+			helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, errs.ErrPurchaseLimitExceeded), errors.Is(err, errs.ErrSomeSeatNotAvailable), errors.Is(err, errs.ErrZoneNotOnSale):
+			helper.ErrorResponse(w, http.StatusConflict, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	helper.SuccessResponse(w, http.StatusOK, "offer accepted", data)
+}
+
+func (h *waitlistHandler) LeaveWaitlist(w http.ResponseWriter, r *http.Request) {
+	entryID, err := helper.ParseInt64(r.PathValue("entry_id"))
+	if err != nil {
+		helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := h.uc.LeaveWaitlist(r.Context(), entryID); err != nil {
+		switch {
+		case errors.Is(err, errs.ErrWaitlistEntryNotFound):
+			helper.ErrorResponse(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, errs.ErrWaitlistNotOwned):
+			helper.ErrorResponse(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, errs.ErrWaitlistEntryClosed):
+			helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+
+		default:
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	helper.SuccessResponse(w, http.StatusOK, "left waitlist", nil)
+}
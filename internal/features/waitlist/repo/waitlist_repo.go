@@ -0,0 +1,273 @@
+package waitlistrepo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/waitlist"
+	"github.com/lib/pq"
+)
+
+//go:generate mockgen -source=waitlist_repo.go -destination=waitlist_repo_mock.go -package=waitlistrepo
+type WaitlistRepository interface {
+	GetByID(ctx context.Context, entryID int64) (waitlist.Entry, error)
+	GetByUserID(ctx context.Context, userID int64) ([]waitlist.Entry, error)
+
+	// Transaction
+	CreateEntryTx(ctx context.Context, tx *sql.Tx, input waitlist.Entry) (int64, error)
+	GetForUpdateTx(ctx context.Context, tx *sql.Tx, entryID int64) (waitlist.Entry, error)
+	GetNextWaitingTx(ctx context.Context, tx *sql.Tx, eventID int64) (waitlist.Entry, error)
+	GetExpiredOffersTx(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]waitlist.Entry, error)
+	GetOfferSeatIDsTx(ctx context.Context, tx *sql.Tx, entryID int64) ([]int64, error)
+	OfferTx(ctx context.Context, tx *sql.Tx, entryID int64, seatIDs []int64, expiresAt time.Time) error
+	UpdateStatusTx(ctx context.Context, tx *sql.Tx, entryID int64, from, to waitlist.WaitlistStatus) error
+}
+
+type waitlistRepository struct {
+	db *sql.DB
+}
+
+func NewWaitlistRepository(db *sql.DB) WaitlistRepository {
+	return &waitlistRepository{db: db}
+}
+
+func (r *waitlistRepository) GetByID(ctx context.Context, entryID int64) (waitlist.Entry, error) {
+	query := `
+		SELECT
+			w.id, w.event_id, w.user_id, w.seat_count, w.status, w.offer_expires_at, w.created_at,
+			COALESCE(ARRAY_AGG(o.seat_id ORDER BY o.seat_id) FILTER (WHERE o.seat_id IS NOT NULL), '{}')
+		FROM waitlist_entries w
+		LEFT JOIN waitlist_offer_seats o ON o.entry_id = w.id
+		WHERE w.id = $1
+		GROUP BY w.id
+	`
+	e, err := scanEntry(r.db.QueryRowContext(ctx, query, entryID).Scan, true)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return waitlist.Entry{}, errs.ErrWaitlistEntryNotFound
+		}
+		return waitlist.Entry{}, err
+	}
+	return e, nil
+}
+
+func (r *waitlistRepository) GetByUserID(ctx context.Context, userID int64) ([]waitlist.Entry, error) {
+	query := `
+		SELECT
+			w.id, w.event_id, w.user_id, w.seat_count, w.status, w.offer_expires_at, w.created_at,
+			COALESCE(ARRAY_AGG(o.seat_id ORDER BY o.seat_id) FILTER (WHERE o.seat_id IS NOT NULL), '{}')
+		FROM waitlist_entries w
+		LEFT JOIN waitlist_offer_seats o ON o.entry_id = w.id
+		WHERE w.user_id = $1
+		GROUP BY w.id
+		ORDER BY w.created_at DESC
+	`
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []waitlist.Entry
+	for rows.Next() {
+		e, err := scanEntry(rows.Scan, true)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *waitlistRepository) CreateEntryTx(ctx context.Context, tx *sql.Tx, input waitlist.Entry) (int64, error) {
+	query := `
+		INSERT INTO waitlist_entries (event_id, user_id, seat_count, status)
+		VALUES ($1, $2, $3, $4) RETURNING id
+	`
+	var id int64
+
+	err := tx.QueryRowContext(ctx, query, input.EventID, input.UserID, input.SeatCount, input.Status).Scan(&id)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == pq.ErrorCode("23505") {
+			return 0, errs.ErrWaitlistAlreadyJoined
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+func (r *waitlistRepository) GetForUpdateTx(ctx context.Context, tx *sql.Tx, entryID int64) (waitlist.Entry, error) {
+	query := `
+		SELECT id, event_id, user_id, seat_count, status, offer_expires_at, created_at
+		FROM waitlist_entries WHERE id = $1
+		FOR UPDATE
+	`
+	e, err := scanEntry(tx.QueryRowContext(ctx, query, entryID).Scan, false)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return waitlist.Entry{}, errs.ErrWaitlistEntryNotFound
+		}
+		return waitlist.Entry{}, err
+	}
+	return e, nil
+}
+
+// GetNextWaitingTx : lock the oldest WAITING entry of the event. It waits for an entry another
+// transaction holds instead of skipping it, so the queue is always served in order
+func (r *waitlistRepository) GetNextWaitingTx(ctx context.Context, tx *sql.Tx, eventID int64) (waitlist.Entry, error) {
+	query := `
+		SELECT id, event_id, user_id, seat_count, status, offer_expires_at, created_at
+		FROM waitlist_entries
+		WHERE event_id = $1 AND status = 'WAITING'
+		ORDER BY created_at ASC, id ASC
+		LIMIT 1
+		FOR UPDATE
+	`
+	e, err := scanEntry(tx.QueryRowContext(ctx, query, eventID).Scan, false)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return waitlist.Entry{}, errs.ErrWaitlistEntryNotFound
+		}
+		return waitlist.Entry{}, err
+	}
+	return e, nil
+}
+
+// GetExpiredOffersTx : lock OFFERED entries whose offer has lapsed, skip rows other sweepers hold
+func (r *waitlistRepository) GetExpiredOffersTx(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]waitlist.Entry, error) {
+	query := `
+		SELECT id, event_id, user_id, seat_count, status, offer_expires_at, created_at
+		FROM waitlist_entries
+		WHERE status = 'OFFERED' AND offer_expires_at <= $1
+		ORDER BY offer_expires_at ASC
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`
+	rows, err := tx.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []waitlist.Entry
+	for rows.Next() {
+		e, err := scanEntry(rows.Scan, false)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *waitlistRepository) GetOfferSeatIDsTx(ctx context.Context, tx *sql.Tx, entryID int64) ([]int64, error) {
+	query := `SELECT seat_id FROM waitlist_offer_seats WHERE entry_id = $1 ORDER BY seat_id ASC`
+	rows, err := tx.QueryContext(ctx, query, entryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var seatIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		seatIDs = append(seatIDs, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return seatIDs, nil
+}
+
+// OfferTx : attach the held seats to a WAITING entry and start its offer window
+func (r *waitlistRepository) OfferTx(ctx context.Context, tx *sql.Tx, entryID int64, seatIDs []int64, expiresAt time.Time) error {
+	query := `
+		UPDATE waitlist_entries SET status = 'OFFERED', offer_expires_at = $2, updated_at = NOW()
+		WHERE id = $1 AND status = 'WAITING'
+	`
+	res, err := tx.ExecContext(ctx, query, entryID, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errs.ErrWaitlistEntryNotFound
+	}
+
+	seatQuery := `
+		INSERT INTO waitlist_offer_seats (entry_id, seat_id)
+		SELECT $1, UNNEST($2::BIGINT[])
+	`
+	_, err = tx.ExecContext(ctx, seatQuery, entryID, pq.Array(seatIDs))
+	return err
+}
+
+// UpdateStatusTx : move an entry only if it is still in from
+func (r *waitlistRepository) UpdateStatusTx(ctx context.Context, tx *sql.Tx, entryID int64, from, to waitlist.WaitlistStatus) error {
+	query := `
+		UPDATE waitlist_entries SET status = $3, updated_at = NOW()
+		WHERE id = $1 AND status = $2
+	`
+	res, err := tx.ExecContext(ctx, query, entryID, from, to)
+	if err != nil {
+		return err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rows == 0 {
+		return errs.ErrWaitlistEntryNotFound
+	}
+	return nil
+}
+
+// scanEntry : scan one entry row, withSeats when the query aggregates the offered seat IDs
+func scanEntry(scan func(dest ...any) error, withSeats bool) (waitlist.Entry, error) {
+	var e waitlist.Entry
+	var expiresAt sql.NullTime
+
+	dest := []any{
+		&e.ID,
+		&e.EventID,
+		&e.UserID,
+		&e.SeatCount,
+		&e.Status,
+		&expiresAt,
+		&e.CreatedAt,
+	}
+	if withSeats {
+		dest = append(dest, pq.Array(&e.SeatIDs))
+	}
+
+	if err := scan(dest...); err != nil {
+		return waitlist.Entry{}, err
+	}
+	if expiresAt.Valid {
+		e.OfferExpiresAt = expiresAt.Time
+	}
+	return e, nil
+}
@@ -0,0 +1,171 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: waitlist_repo.go
+
+// Package waitlistrepo is a generated GoMock package.
+package waitlistrepo
+
+import (
+	context "context"
+	sql "database/sql"
+	reflect "reflect"
+	time "time"
+
+	waitlist "github.com/codepnw/stdlib-ticket-system/internal/features/waitlist"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockWaitlistRepository is a mock of WaitlistRepository interface.
+type MockWaitlistRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWaitlistRepositoryMockRecorder
+}
+
+// MockWaitlistRepositoryMockRecorder is the mock recorder for MockWaitlistRepository.
+type MockWaitlistRepositoryMockRecorder struct {
+	mock *MockWaitlistRepository
+}
+
+// NewMockWaitlistRepository creates a new mock instance.
+func NewMockWaitlistRepository(ctrl *gomock.Controller) *MockWaitlistRepository {
+	mock := &MockWaitlistRepository{ctrl: ctrl}
+	mock.recorder = &MockWaitlistRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWaitlistRepository) EXPECT() *MockWaitlistRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateEntryTx mocks base method.
+func (m *MockWaitlistRepository) CreateEntryTx(ctx context.Context, tx *sql.Tx, input waitlist.Entry) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEntryTx", ctx, tx, input)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEntryTx indicates an expected call of CreateEntryTx.
+func (mr *MockWaitlistRepositoryMockRecorder) CreateEntryTx(ctx, tx, input interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEntryTx", reflect.TypeOf((*MockWaitlistRepository)(nil).CreateEntryTx), ctx, tx, input)
+}
+
+// GetByID mocks base method.
+func (m *MockWaitlistRepository) GetByID(ctx context.Context, entryID int64) (waitlist.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, entryID)
+	ret0, _ := ret[0].(waitlist.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockWaitlistRepositoryMockRecorder) GetByID(ctx, entryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockWaitlistRepository)(nil).GetByID), ctx, entryID)
+}
+
+// GetByUserID mocks base method.
+func (m *MockWaitlistRepository) GetByUserID(ctx context.Context, userID int64) ([]waitlist.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByUserID", ctx, userID)
+	ret0, _ := ret[0].([]waitlist.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByUserID indicates an expected call of GetByUserID.
+func (mr *MockWaitlistRepositoryMockRecorder) GetByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByUserID", reflect.TypeOf((*MockWaitlistRepository)(nil).GetByUserID), ctx, userID)
+}
+
+// GetExpiredOffersTx mocks base method.
+func (m *MockWaitlistRepository) GetExpiredOffersTx(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]waitlist.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredOffersTx", ctx, tx, now, limit)
+	ret0, _ := ret[0].([]waitlist.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredOffersTx indicates an expected call of GetExpiredOffersTx.
+func (mr *MockWaitlistRepositoryMockRecorder) GetExpiredOffersTx(ctx, tx, now, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredOffersTx", reflect.TypeOf((*MockWaitlistRepository)(nil).GetExpiredOffersTx), ctx, tx, now, limit)
+}
+
+// GetForUpdateTx mocks base method.
+func (m *MockWaitlistRepository) GetForUpdateTx(ctx context.Context, tx *sql.Tx, entryID int64) (waitlist.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdateTx", ctx, tx, entryID)
+	ret0, _ := ret[0].(waitlist.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdateTx indicates an expected call of GetForUpdateTx.
+func (mr *MockWaitlistRepositoryMockRecorder) GetForUpdateTx(ctx, tx, entryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdateTx", reflect.TypeOf((*MockWaitlistRepository)(nil).GetForUpdateTx), ctx, tx, entryID)
+}
+
+// GetNextWaitingTx mocks base method.
+func (m *MockWaitlistRepository) GetNextWaitingTx(ctx context.Context, tx *sql.Tx, eventID int64) (waitlist.Entry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNextWaitingTx", ctx, tx, eventID)
+	ret0, _ := ret[0].(waitlist.Entry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNextWaitingTx indicates an expected call of GetNextWaitingTx.
+func (mr *MockWaitlistRepositoryMockRecorder) GetNextWaitingTx(ctx, tx, eventID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNextWaitingTx", reflect.TypeOf((*MockWaitlistRepository)(nil).GetNextWaitingTx), ctx, tx, eventID)
+}
+
+// GetOfferSeatIDsTx mocks base method.
+func (m *MockWaitlistRepository) GetOfferSeatIDsTx(ctx context.Context, tx *sql.Tx, entryID int64) ([]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOfferSeatIDsTx", ctx, tx, entryID)
+	ret0, _ := ret[0].([]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOfferSeatIDsTx indicates an expected call of GetOfferSeatIDsTx.
+func (mr *MockWaitlistRepositoryMockRecorder) GetOfferSeatIDsTx(ctx, tx, entryID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOfferSeatIDsTx", reflect.TypeOf((*MockWaitlistRepository)(nil).GetOfferSeatIDsTx), ctx, tx, entryID)
+}
+
+// OfferTx mocks base method.
+func (m *MockWaitlistRepository) OfferTx(ctx context.Context, tx *sql.Tx, entryID int64, seatIDs []int64, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OfferTx", ctx, tx, entryID, seatIDs, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OfferTx indicates an expected call of OfferTx.
+func (mr *MockWaitlistRepositoryMockRecorder) OfferTx(ctx, tx, entryID, seatIDs, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OfferTx", reflect.TypeOf((*MockWaitlistRepository)(nil).OfferTx), ctx, tx, entryID, seatIDs, expiresAt)
+}
+
+// UpdateStatusTx mocks base method.
+func (m *MockWaitlistRepository) UpdateStatusTx(ctx context.Context, tx *sql.Tx, entryID int64, from waitlist.WaitlistStatus, to waitlist.WaitlistStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatusTx", ctx, tx, entryID, from, to)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatusTx indicates an expected call of UpdateStatusTx.
+func (mr *MockWaitlistRepositoryMockRecorder) UpdateStatusTx(ctx, tx, entryID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatusTx", reflect.TypeOf((*MockWaitlistRepository)(nil).UpdateStatusTx), ctx, tx, entryID, from, to)
+}
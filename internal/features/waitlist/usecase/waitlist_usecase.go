@@ -0,0 +1,445 @@
+package waitlistusecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/authcontext"
+	"github.com/codepnw/stdlib-ticket-system/internal/config"
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+	bookingrepo "github.com/codepnw/stdlib-ticket-system/internal/features/booking/repo"
+	bookingusecase "github.com/codepnw/stdlib-ticket-system/internal/features/booking/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	eventrepo "github.com/codepnw/stdlib-ticket-system/internal/features/event/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/seat"
+	seatrepo "github.com/codepnw/stdlib-ticket-system/internal/features/seat/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/waitlist"
+	waitlistrepo "github.com/codepnw/stdlib-ticket-system/internal/features/waitlist/repo"
+	"github.com/codepnw/stdlib-ticket-system/pkg/database"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+)
+
+// expireBatchSize : max offers released per sweep
+const expireBatchSize = 100
+
+type WaitlistUsecase interface {
+	JoinWaitlist(ctx context.Context, eventID int64, seatCount int) (displayEntry, error)
+	GetMyEntries(ctx context.Context) ([]displayEntry, error)
+	AcceptOffer(ctx context.Context, entryID int64) (displayAcceptedOffer, error)
+	LeaveWaitlist(ctx context.Context, entryID int64) error
+	// OfferSeatsTx : offer AVAILABLE seats to the queue in order, inside the caller's transaction
+	OfferSeatsTx(ctx context.Context, tx *sql.Tx, eventID int64) error
+	ExpireOffers(ctx context.Context) error
+}
+
+type waitlistUsecase struct {
+	location      *time.Location
+	offerDuration time.Duration
+	holdDuration  time.Duration
+	tx            database.TxManager
+	waitRepo      waitlistrepo.WaitlistRepository
+	bookRepo      bookingrepo.BookingRepository
+	seatRepo      seatrepo.SeatRepository
+	eventRepo     eventrepo.EventRepository
+	notifier      bookingusecase.SeatNotifier
+}
+
+func NewWaitlistUsecase(location *time.Location, offerDuration, holdDuration time.Duration, tx database.TxManager, waitRepo waitlistrepo.WaitlistRepository, bookRepo bookingrepo.BookingRepository, seatRepo seatrepo.SeatRepository, eventRepo eventrepo.EventRepository, notifier bookingusecase.SeatNotifier) WaitlistUsecase {
+	return &waitlistUsecase{
+		location:      location,
+		offerDuration: offerDuration,
+		holdDuration:  holdDuration,
+		tx:            tx,
+		waitRepo:      waitRepo,
+		bookRepo:      bookRepo,
+		seatRepo:      seatRepo,
+		eventRepo:     eventRepo,
+		notifier:      notifier,
+	}
+}
+
+type displayEntry struct {
+	ID             int64   `json:"id"`
+	EventID        int64   `json:"event_id"`
+	SeatCount      int     `json:"seat_count"`
+	Status         string  `json:"status"`
+	SeatIDs        []int64 `json:"seat_ids,omitempty"`
+	OfferExpiresAt string  `json:"offer_expires_at,omitempty"`
+	CreatedAt      string  `json:"created_at"`
+}
+
+func (u *waitlistUsecase) toDisplay(e waitlist.Entry) displayEntry {
+	result := displayEntry{
+		ID:        e.ID,
+		EventID:   e.EventID,
+		SeatCount: e.SeatCount,
+		Status:    string(e.Status),
+		SeatIDs:   e.SeatIDs,
+		CreatedAt: e.CreatedAt.In(u.location).Format(time.DateTime),
+	}
+	if e.Status == waitlist.StatusOffered && !e.OfferExpiresAt.IsZero() {
+		result.OfferExpiresAt = e.OfferExpiresAt.In(u.location).Format(time.DateTime)
+	}
+	return result
+}
+
+func (u *waitlistUsecase) JoinWaitlist(ctx context.Context, eventID int64, seatCount int) (displayEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	userID := authcontext.GetUserID(ctx)
+
+	// 1. Check Event & Order Limit
+	eventData, err := u.eventRepo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return displayEntry{}, err
+	}
+	if err := eventData.PurchaseLimit(seatCount, 0); err != nil {
+		return displayEntry{}, err
+	}
+
+	// 2. Only a sold out event has a waitlist
+	available, err := u.seatRepo.CountAvailableSeats(ctx, eventID)
+	if err != nil {
+		return displayEntry{}, err
+	}
+	if available >= seatCount {
+		return displayEntry{}, errs.ErrSeatsStillAvailable
+	}
+
+	var entryID int64
+	var offered []int64
+	err = u.tx.WithTx(ctx, func(tx *sql.Tx) error {
+		entryID, err = u.waitRepo.CreateEntryTx(ctx, tx, waitlist.Entry{
+			EventID:   eventID,
+			UserID:    userID,
+			SeatCount: seatCount,
+			Status:    waitlist.StatusWaiting,
+		})
+		if err != nil {
+			return err
+		}
+
+		// 3. Seats freed since the check go to the queue right away
+		offered, err = u.offerSeatsTx(ctx, tx, eventID)
+		return err
+	})
+	if err != nil {
+		return displayEntry{}, err
+	}
+	u.notifier.SeatsChanged(ctx, eventID, offered)
+
+	entry, err := u.waitRepo.GetByID(ctx, entryID)
+	if err != nil {
+		return displayEntry{}, err
+	}
+	return u.toDisplay(entry), nil
+}
+
+func (u *waitlistUsecase) GetMyEntries(ctx context.Context) ([]displayEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	entries, err := u.waitRepo.GetByUserID(ctx, authcontext.GetUserID(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]displayEntry, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, u.toDisplay(e))
+	}
+	return result, nil
+}
+
+type displayAcceptedOffer struct {
+	EntryID     int64       `json:"entry_id"`
+	BookingID   string      `json:"booking_id"`
+	SeatIDs     []int64     `json:"seat_ids"`
+	TotalAmount money.Money `json:"total_amount"`
+	Status      string      `json:"status"`
+	ExpiresAt   string      `json:"expires_at"`
+}
+
+// AcceptOffer : turn the offered seats into a PENDING booking for the entry owner
+func (u *waitlistUsecase) AcceptOffer(ctx context.Context, entryID int64) (displayAcceptedOffer, error) {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	userID := authcontext.GetUserID(ctx)
+
+	// 1. Check Entry & Ownership
+	entry, err := u.waitRepo.GetByID(ctx, entryID)
+	if err != nil {
+		return displayAcceptedOffer{}, err
+	}
+	if entry.UserID != userID {
+		return displayAcceptedOffer{}, errs.ErrWaitlistNotOwned
+	}
+
+	eventData, err := u.eventRepo.GetEventByID(ctx, entry.EventID)
+	if err != nil {
+		return displayAcceptedOffer{}, err
+	}
+
+	expiresAt := time.Now().Add(u.holdDuration)
+	var result displayAcceptedOffer
+	var seatIDs []int64
+
+	err = u.tx.WithTx(ctx, func(tx *sql.Tx) error {
+		// 2. Check Offer
+		locked, err := u.waitRepo.GetForUpdateTx(ctx, tx, entryID)
+		if err != nil {
+			return err
+		}
+		if locked.Status != waitlist.StatusOffered {
+			return errs.ErrOfferNotActive
+		}
+		if time.Now().After(locked.OfferExpiresAt) {
+			return errs.ErrOfferExpired
+		}
+
+		seatIDs, err = u.waitRepo.GetOfferSeatIDsTx(ctx, tx, entryID)
+		if err != nil {
+			return err
+		}
+
+		// 3. Check User Limit
+		if err := u.bookRepo.LockUserEventTx(ctx, tx, userID, locked.EventID); err != nil {
+			return err
+		}
+		held, err := u.bookRepo.CountUserSeatsTx(ctx, tx, userID, locked.EventID)
+		if err != nil {
+			return err
+		}
+		if err := eventData.PurchaseLimit(len(seatIDs), held); err != nil {
+			return err
+		}
+
+		// 4. Offered Seats stay RESERVED, now for the booking
+		seats, err := u.seatRepo.GetSeatsForUpdateTx(ctx, tx, seatIDs)
+		if err != nil {
+			return err
+		}
+		if len(seats) != len(seatIDs) {
+			return errs.ErrSomeSeatNotAvailable
+		}
+
+		tiers, err := u.eventRepo.GetPriceTiers(ctx, locked.EventID)
+		if err != nil {
+			return err
+		}
+		fees, err := u.eventRepo.GetFees(ctx, locked.EventID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		totalAmount := money.Zero(eventData.Currency)
+		items := make([]booking.BookingItem, 0, len(seats))
+		for _, s := range seats {
+			if s.Status != seat.StatusReserved {
+				return errs.ErrSomeSeatNotAvailable
+			}
+			unitPrice, err := event.ZonePrice(tiers, s.Zone, s.Price, now)
+			if err != nil {
+				return err
+			}
+			if totalAmount, err = totalAmount.Add(unitPrice); err != nil {
+				return err
+			}
+			items = append(items, booking.BookingItem{SeatID: s.ID, UnitPrice: unitPrice})
+		}
+
+		// 5. Create Booking, priced with the fees and tax of the event
+		price, err := booking.Price(totalAmount, money.Zero(eventData.Currency), len(seats), fees, eventData.TaxPercent)
+		if err != nil {
+			return err
+		}
+		bookingID, err := u.bookRepo.CreateBookingTx(ctx, tx, booking.Booking{
+			UserID:         userID,
+			EventID:        locked.EventID,
+			TotalAmount:    price.Total,
+			DiscountAmount: money.Zero(eventData.Currency),
+			Status:         booking.StatusPending,
+			ExpiresAt:      expiresAt,
+		})
+		if err != nil {
+			return err
+		}
+		if err := u.bookRepo.CreateBookingItemsTx(ctx, tx, bookingID, items); err != nil {
+			return err
+		}
+		if len(price.Lines) > 0 {
+			if err := u.bookRepo.CreateLinesTx(ctx, tx, bookingID, price.Lines); err != nil {
+				return err
+			}
+		}
+
+		// 6. Close Entry
+		if err := u.waitRepo.UpdateStatusTx(ctx, tx, entryID, waitlist.StatusOffered, waitlist.StatusAccepted); err != nil {
+			return err
+		}
+
+		result = displayAcceptedOffer{
+			EntryID:     entryID,
+			BookingID:   bookingID,
+			SeatIDs:     seatIDs,
+			TotalAmount: price.Total,
+			Status:      string(booking.StatusPending),
+			ExpiresAt:   expiresAt.In(u.location).Format(time.DateTime),
+		}
+		return nil
+	})
+	if err != nil {
+		return displayAcceptedOffer{}, err
+	}
+	u.notifier.SeatsChanged(ctx, entry.EventID, seatIDs)
+	return result, nil
+}
+
+// LeaveWaitlist : drop an open entry, an offer in hand goes to the next in line
+func (u *waitlistUsecase) LeaveWaitlist(ctx context.Context, entryID int64) error {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	// 1. Check Entry & Ownership
+	entry, err := u.waitRepo.GetByID(ctx, entryID)
+	if err != nil {
+		return err
+	}
+	if entry.UserID != authcontext.GetUserID(ctx) {
+		return errs.ErrWaitlistNotOwned
+	}
+
+	var changed []int64
+	err = u.tx.WithTx(ctx, func(tx *sql.Tx) error {
+		locked, err := u.waitRepo.GetForUpdateTx(ctx, tx, entryID)
+		if err != nil {
+			return err
+		}
+
+		switch locked.Status {
+		case waitlist.StatusWaiting:
+			return u.waitRepo.UpdateStatusTx(ctx, tx, entryID, waitlist.StatusWaiting, waitlist.StatusCancelled)
+		case waitlist.StatusOffered:
+			changed, err = u.releaseOfferTx(ctx, tx, locked, waitlist.StatusCancelled)
+			return err
+		default:
+			return errs.ErrWaitlistEntryClosed
+		}
+	})
+	if err != nil {
+		return err
+	}
+	u.notifier.SeatsChanged(ctx, entry.EventID, changed)
+	return nil
+}
+
+func (u *waitlistUsecase) OfferSeatsTx(ctx context.Context, tx *sql.Tx, eventID int64) error {
+	_, err := u.offerSeatsTx(ctx, tx, eventID)
+	return err
+}
+
+// offerSeatsTx : returns the seat IDs held for new offers, to publish after commit
+func (u *waitlistUsecase) offerSeatsTx(ctx context.Context, tx *sql.Tx, eventID int64) ([]int64, error) {
+	var offered []int64
+	for {
+		// 1. Next in line
+		entry, err := u.waitRepo.GetNextWaitingTx(ctx, tx, eventID)
+		if err != nil {
+			if errors.Is(err, errs.ErrWaitlistEntryNotFound) {
+				return offered, nil
+			}
+			return nil, err
+		}
+
+		// 2. Not enough seats yet, the queue keeps its order
+		seatIDs, err := u.seatRepo.GetAvailableSeatIDsTx(ctx, tx, eventID, entry.SeatCount)
+		if err != nil {
+			return nil, err
+		}
+		if len(seatIDs) < entry.SeatCount {
+			return offered, nil
+		}
+
+		// 3. Hold Seats for the offer
+		if err := u.seatRepo.UpdateSeatsStatusTx(ctx, tx, seatIDs, string(seat.StatusReserved)); err != nil {
+			return nil, err
+		}
+		if err := u.waitRepo.OfferTx(ctx, tx, entry.ID, seatIDs, time.Now().Add(u.offerDuration)); err != nil {
+			return nil, err
+		}
+		offered = append(offered, seatIDs...)
+		log.Printf("offered %d seats of event %d to waitlist entry %d", len(seatIDs), eventID, entry.ID)
+	}
+}
+
+// ExpireOffers : release lapsed offers and pass the seats to the next in line
+func (u *waitlistUsecase) ExpireOffers(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, config.ContextTimeout)
+	defer cancel()
+
+	var expired int
+	changed := make(map[int64][]int64)
+	err := u.tx.WithTx(ctx, func(tx *sql.Tx) error {
+		entries, err := u.waitRepo.GetExpiredOffersTx(ctx, tx, time.Now(), expireBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range entries {
+			seatIDs, err := u.releaseOfferTx(ctx, tx, e, waitlist.StatusExpired)
+			if err != nil {
+				return err
+			}
+			changed[e.EventID] = append(changed[e.EventID], seatIDs...)
+		}
+		expired = len(entries)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for eventID, seatIDs := range changed {
+		u.notifier.SeatsChanged(ctx, eventID, seatIDs)
+	}
+
+	if expired > 0 {
+		log.Printf("expired %d waitlist offers", expired)
+	}
+	return nil
+}
+
+// releaseOfferTx : close an OFFERED entry, free its seats and offer them again,
+// returns every seat that changed status
+func (u *waitlistUsecase) releaseOfferTx(ctx context.Context, tx *sql.Tx, entry waitlist.Entry, to waitlist.WaitlistStatus) ([]int64, error) {
+	if err := u.waitRepo.UpdateStatusTx(ctx, tx, entry.ID, waitlist.StatusOffered, to); err != nil {
+		return nil, err
+	}
+
+	seatIDs, err := u.waitRepo.GetOfferSeatIDsTx(ctx, tx, entry.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(seatIDs) > 0 {
+		if err := u.seatRepo.UpdateSeatsStatusTx(ctx, tx, seatIDs, string(seat.StatusAvailable)); err != nil {
+			return nil, err
+		}
+	}
+
+	offered, err := u.offerSeatsTx(ctx, tx, entry.EventID)
+	if err != nil {
+		return nil, err
+	}
+	// A freed seat may be offered again right away, publish it once
+	changed := append(seatIDs, offered...)
+	slices.Sort(changed)
+	return slices.Compact(changed), nil
+}
@@ -0,0 +1,395 @@
+package waitlistusecase_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/authcontext"
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/booking"
+	bookingrepo "github.com/codepnw/stdlib-ticket-system/internal/features/booking/repo"
+	bookingusecase "github.com/codepnw/stdlib-ticket-system/internal/features/booking/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/event"
+	eventrepo "github.com/codepnw/stdlib-ticket-system/internal/features/event/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/seat"
+	seatrepo "github.com/codepnw/stdlib-ticket-system/internal/features/seat/repo"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/waitlist"
+	waitlistrepo "github.com/codepnw/stdlib-ticket-system/internal/features/waitlist/repo"
+	waitlistusecase "github.com/codepnw/stdlib-ticket-system/internal/features/waitlist/usecase"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+var ErrMockDBError = errors.New("db error")
+
+type mockTx struct{}
+
+func (m mockTx) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	return fn(nil)
+}
+
+func (m mockTx) WithRetryTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	return fn(nil)
+}
+
+type mocks struct {
+	wait     *waitlistrepo.MockWaitlistRepository
+	book     *bookingrepo.MockBookingRepository
+	seat     *seatrepo.MockSeatRepository
+	event    *eventrepo.MockEventRepository
+	notifier *bookingusecase.MockSeatNotifier
+}
+
+func TestJoinWaitlist(t *testing.T) {
+	type testCase struct {
+		name        string
+		seatCount   int
+		mockFn      func(m mocks, seatCount int)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:      "success",
+			seatCount: 2,
+			mockFn: func(m mocks, seatCount int) {
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10}, nil).Times(1)
+
+				m.seat.EXPECT().CountAvailableSeats(gomock.Any(), int64(10)).Return(1, nil).Times(1)
+
+				m.wait.EXPECT().CreateEntryTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sql.Tx, input waitlist.Entry) (int64, error) {
+						assert.Equal(t, waitlist.StatusWaiting, input.Status)
+						assert.Equal(t, seatCount, input.SeatCount)
+						return 1, nil
+					}).Times(1)
+
+				m.wait.EXPECT().GetNextWaitingTx(gomock.Any(), gomock.Any(), int64(10)).Return(waitlist.Entry{ID: 1, EventID: 10, SeatCount: seatCount}, nil).Times(1)
+
+				m.seat.EXPECT().GetAvailableSeatIDsTx(gomock.Any(), gomock.Any(), int64(10), seatCount).Return([]int64{5}, nil).Times(1)
+
+				m.notifier.EXPECT().SeatsChanged(gomock.Any(), int64(10), []int64(nil)).Times(1)
+
+				m.wait.EXPECT().GetByID(gomock.Any(), int64(1)).Return(waitlist.Entry{ID: 1, EventID: 10, SeatCount: seatCount, Status: waitlist.StatusWaiting}, nil).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name:      "fail seats still available",
+			seatCount: 2,
+			mockFn: func(m mocks, seatCount int) {
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10}, nil).Times(1)
+
+				m.seat.EXPECT().CountAvailableSeats(gomock.Any(), int64(10)).Return(2, nil).Times(1)
+			},
+			expectedErr: errs.ErrSeatsStillAvailable,
+		},
+		{
+			name:      "fail seats per order limit",
+			seatCount: 5,
+			mockFn: func(m mocks, seatCount int) {
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10, MaxSeatsPerOrder: 4}, nil).Times(1)
+			},
+			expectedErr: errs.ErrPurchaseLimitExceeded,
+		},
+		{
+			name:      "fail already joined",
+			seatCount: 2,
+			mockFn: func(m mocks, seatCount int) {
+				m.event.EXPECT().GetEventByID(gomock.Any(), int64(10)).Return(event.Event{ID: 10}, nil).Times(1)
+
+				m.seat.EXPECT().CountAvailableSeats(gomock.Any(), int64(10)).Return(0, nil).Times(1)
+
+				m.wait.EXPECT().CreateEntryTx(gomock.Any(), gomock.Any(), gomock.Any()).Return(int64(0), errs.ErrWaitlistAlreadyJoined).Times(1)
+			},
+			expectedErr: errs.ErrWaitlistAlreadyJoined,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, m := setup(t)
+
+			// Mock FN
+			tc.mockFn(m, tc.seatCount)
+
+			ctx := authcontext.SetUserID(context.Background(), int64(1))
+			_, err := uc.JoinWaitlist(ctx, 10, tc.seatCount)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestOfferSeatsTx(t *testing.T) {
+	type testCase struct {
+		name        string
+		mockFn      func(m mocks)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "success offers in queue order",
+			mockFn: func(m mocks) {
+				first := waitlist.Entry{ID: 1, EventID: 10, SeatCount: 2, Status: waitlist.StatusWaiting}
+				second := waitlist.Entry{ID: 2, EventID: 10, SeatCount: 3, Status: waitlist.StatusWaiting}
+
+				gomock.InOrder(
+					m.wait.EXPECT().GetNextWaitingTx(gomock.Any(), gomock.Any(), int64(10)).Return(first, nil),
+					m.seat.EXPECT().GetAvailableSeatIDsTx(gomock.Any(), gomock.Any(), int64(10), 2).Return([]int64{5, 6}, nil),
+					m.seat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), []int64{5, 6}, string(seat.StatusReserved)).Return(nil),
+					m.wait.EXPECT().OfferTx(gomock.Any(), gomock.Any(), first.ID, []int64{5, 6}, gomock.Any()).Return(nil),
+
+					// Next in line wants more than what is left
+					m.wait.EXPECT().GetNextWaitingTx(gomock.Any(), gomock.Any(), int64(10)).Return(second, nil),
+					m.seat.EXPECT().GetAvailableSeatIDsTx(gomock.Any(), gomock.Any(), int64(10), 3).Return([]int64{7}, nil),
+				)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "success empty queue",
+			mockFn: func(m mocks) {
+				m.wait.EXPECT().GetNextWaitingTx(gomock.Any(), gomock.Any(), int64(10)).Return(waitlist.Entry{}, errs.ErrWaitlistEntryNotFound).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "fail hold seats",
+			mockFn: func(m mocks) {
+				m.wait.EXPECT().GetNextWaitingTx(gomock.Any(), gomock.Any(), int64(10)).Return(waitlist.Entry{ID: 1, EventID: 10, SeatCount: 1}, nil).Times(1)
+
+				m.seat.EXPECT().GetAvailableSeatIDsTx(gomock.Any(), gomock.Any(), int64(10), 1).Return([]int64{5}, nil).Times(1)
+
+				m.seat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), []int64{5}, string(seat.StatusReserved)).Return(ErrMockDBError).Times(1)
+			},
+			expectedErr: ErrMockDBError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, m := setup(t)
+
+			// Mock FN
+			tc.mockFn(m)
+
+			err := uc.OfferSeatsTx(context.Background(), nil, 10)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAcceptOffer(t *testing.T) {
+	offered := waitlist.Entry{
+		ID:             1,
+		EventID:        10,
+		UserID:         1,
+		SeatCount:      2,
+		Status:         waitlist.StatusOffered,
+		OfferExpiresAt: time.Now().Add(5 * time.Minute),
+	}
+	seatIDs := []int64{5, 6}
+
+	type testCase struct {
+		name        string
+		mockFn      func(m mocks)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "success",
+			mockFn: func(m mocks) {
+				m.wait.EXPECT().GetByID(gomock.Any(), offered.ID).Return(offered, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), offered.EventID).Return(event.Event{ID: offered.EventID, Currency: "THB"}, nil).Times(1)
+
+				m.wait.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), offered.ID).Return(offered, nil).Times(1)
+
+				m.wait.EXPECT().GetOfferSeatIDsTx(gomock.Any(), gomock.Any(), offered.ID).Return(seatIDs, nil).Times(1)
+
+				m.book.EXPECT().LockUserEventTx(gomock.Any(), gomock.Any(), offered.UserID, offered.EventID).Return(nil).Times(1)
+
+				m.book.EXPECT().CountUserSeatsTx(gomock.Any(), gomock.Any(), offered.UserID, offered.EventID).Return(0, nil).Times(1)
+
+				mockSeats := []seat.Seat{
+					{ID: 5, Price: money.New(10000, "THB"), Status: seat.StatusReserved},
+					{ID: 6, Price: money.New(15000, "THB"), Status: seat.StatusReserved},
+				}
+				m.seat.EXPECT().GetSeatsForUpdateTx(gomock.Any(), gomock.Any(), seatIDs).Return(mockSeats, nil).Times(1)
+
+				m.event.EXPECT().GetPriceTiers(gomock.Any(), offered.EventID).Return(nil, nil).Times(1)
+
+				m.event.EXPECT().GetFees(gomock.Any(), offered.EventID).Return(nil, nil).Times(1)
+
+				m.book.EXPECT().CreateBookingTx(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, tx *sql.Tx, input booking.Booking) (string, error) {
+						assert.Equal(t, booking.StatusPending, input.Status)
+						assert.Equal(t, money.New(25000, "THB"), input.TotalAmount)
+						return "mock-uuid-1", nil
+					}).Times(1)
+
+				mockItems := []booking.BookingItem{
+					{SeatID: 5, UnitPrice: money.New(10000, "THB")},
+					{SeatID: 6, UnitPrice: money.New(15000, "THB")},
+				}
+				m.book.EXPECT().CreateBookingItemsTx(gomock.Any(), gomock.Any(), "mock-uuid-1", mockItems).Return(nil).Times(1)
+
+				m.wait.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), offered.ID, waitlist.StatusOffered, waitlist.StatusAccepted).Return(nil).Times(1)
+
+				m.notifier.EXPECT().SeatsChanged(gomock.Any(), offered.EventID, seatIDs).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "fail other user entry",
+			mockFn: func(m mocks) {
+				data := offered
+				data.UserID = 2
+				m.wait.EXPECT().GetByID(gomock.Any(), offered.ID).Return(data, nil).Times(1)
+			},
+			expectedErr: errs.ErrWaitlistNotOwned,
+		},
+		{
+			name: "fail not offered",
+			mockFn: func(m mocks) {
+				data := offered
+				data.Status = waitlist.StatusWaiting
+				m.wait.EXPECT().GetByID(gomock.Any(), offered.ID).Return(data, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), offered.EventID).Return(event.Event{ID: offered.EventID, Currency: "THB"}, nil).Times(1)
+
+				m.wait.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), offered.ID).Return(data, nil).Times(1)
+			},
+			expectedErr: errs.ErrOfferNotActive,
+		},
+		{
+			name: "fail offer expired",
+			mockFn: func(m mocks) {
+				data := offered
+				data.OfferExpiresAt = time.Now().Add(-time.Minute)
+				m.wait.EXPECT().GetByID(gomock.Any(), offered.ID).Return(data, nil).Times(1)
+
+				m.event.EXPECT().GetEventByID(gomock.Any(), offered.EventID).Return(event.Event{ID: offered.EventID, Currency: "THB"}, nil).Times(1)
+
+				m.wait.EXPECT().GetForUpdateTx(gomock.Any(), gomock.Any(), offered.ID).Return(data, nil).Times(1)
+			},
+			expectedErr: errs.ErrOfferExpired,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, m := setup(t)
+
+			// Mock FN
+			tc.mockFn(m)
+
+			ctx := authcontext.SetUserID(context.Background(), int64(1))
+			_, err := uc.AcceptOffer(ctx, offered.ID)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestExpireOffers(t *testing.T) {
+	type testCase struct {
+		name        string
+		mockFn      func(m mocks)
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name: "success passes seats to next in line",
+			mockFn: func(m mocks) {
+				lapsed := waitlist.Entry{ID: 1, EventID: 10, SeatCount: 1, Status: waitlist.StatusOffered}
+				next := waitlist.Entry{ID: 2, EventID: 10, SeatCount: 1, Status: waitlist.StatusWaiting}
+
+				m.wait.EXPECT().GetExpiredOffersTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return([]waitlist.Entry{lapsed}, nil).Times(1)
+
+				m.wait.EXPECT().UpdateStatusTx(gomock.Any(), gomock.Any(), lapsed.ID, waitlist.StatusOffered, waitlist.StatusExpired).Return(nil).Times(1)
+
+				m.wait.EXPECT().GetOfferSeatIDsTx(gomock.Any(), gomock.Any(), lapsed.ID).Return([]int64{5}, nil).Times(1)
+
+				m.seat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), []int64{5}, string(seat.StatusAvailable)).Return(nil).Times(1)
+
+				gomock.InOrder(
+					m.wait.EXPECT().GetNextWaitingTx(gomock.Any(), gomock.Any(), int64(10)).Return(next, nil),
+					m.seat.EXPECT().GetAvailableSeatIDsTx(gomock.Any(), gomock.Any(), int64(10), 1).Return([]int64{5}, nil),
+					m.seat.EXPECT().UpdateSeatsStatusTx(gomock.Any(), gomock.Any(), []int64{5}, string(seat.StatusReserved)).Return(nil),
+					m.wait.EXPECT().OfferTx(gomock.Any(), gomock.Any(), next.ID, []int64{5}, gomock.Any()).Return(nil),
+					m.wait.EXPECT().GetNextWaitingTx(gomock.Any(), gomock.Any(), int64(10)).Return(waitlist.Entry{}, errs.ErrWaitlistEntryNotFound),
+				)
+
+				m.notifier.EXPECT().SeatsChanged(gomock.Any(), int64(10), []int64{5}).Times(1)
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "fail get expired",
+			mockFn: func(m mocks) {
+				m.wait.EXPECT().GetExpiredOffersTx(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, ErrMockDBError).Times(1)
+			},
+			expectedErr: ErrMockDBError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Setup
+			uc, m := setup(t)
+
+			// Mock FN
+			tc.mockFn(m)
+
+			err := uc.ExpireOffers(context.Background())
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func setup(t *testing.T) (waitlistusecase.WaitlistUsecase, mocks) {
+	ctrl := gomock.NewController(t)
+
+	loc, _ := time.LoadLocation("Asia/Bangkok")
+
+	m := mocks{
+		wait:     waitlistrepo.NewMockWaitlistRepository(ctrl),
+		book:     bookingrepo.NewMockBookingRepository(ctrl),
+		seat:     seatrepo.NewMockSeatRepository(ctrl),
+		event:    eventrepo.NewMockEventRepository(ctrl),
+		notifier: bookingusecase.NewMockSeatNotifier(ctrl),
+	}
+	uc := waitlistusecase.NewWaitlistUsecase(loc, 10*time.Minute, 15*time.Minute, mockTx{}, m.wait, m.book, m.seat, m.event, m.notifier)
+
+	return uc, m
+}
@@ -0,0 +1,24 @@
+package waitlist
+
+import "time"
+
+type WaitlistStatus string
+
+const (
+	StatusWaiting   WaitlistStatus = "WAITING"
+	StatusOffered   WaitlistStatus = "OFFERED"
+	StatusAccepted  WaitlistStatus = "ACCEPTED"
+	StatusExpired   WaitlistStatus = "EXPIRED"
+	StatusCancelled WaitlistStatus = "CANCELLED"
+)
+
+type Entry struct {
+	ID             int64          `json:"id" db:"id"`
+	EventID        int64          `json:"event_id" db:"event_id"`
+	UserID         int64          `json:"user_id" db:"user_id"`
+	SeatCount      int            `json:"seat_count" db:"seat_count"`
+	Status         WaitlistStatus `json:"status" db:"status"`
+	OfferExpiresAt time.Time      `json:"offer_expires_at" db:"offer_expires_at"`
+	SeatIDs        []int64        `json:"seat_ids" db:"-"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+}
@@ -36,6 +36,17 @@ func ErrorResponse(w http.ResponseWriter, code int, msg string) {
 	})
 }
 
+// ErrorDataResponse : ErrorResponse with details the client can act on
+func ErrorDataResponse(w http.ResponseWriter, code int, msg string, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(response{
+		Success: false,
+		Message: msg,
+		Data:    data,
+	})
+}
+
 func ParseInt64(id string) (int64, error) {
 	return strconv.ParseInt(id, 10, 64)
 }
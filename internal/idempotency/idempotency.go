@@ -0,0 +1,112 @@
+package idempotency
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+type Record struct {
+	UserID       int64     `db:"user_id"`
+	Key          string    `db:"idempotency_key"`
+	RequestHash  string    `db:"request_hash"`
+	StatusCode   int       `db:"status_code"`
+	ResponseBody []byte    `db:"response_body"`
+	Completed    bool      `db:"-"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+type Store interface {
+	// Claim : reserve the key for this request, or return the record that already holds it
+	Claim(ctx context.Context, userID int64, key, requestHash string) (rec Record, claimed bool, err error)
+	Complete(ctx context.Context, userID int64, key string, statusCode int, body []byte) error
+	Release(ctx context.Context, userID int64, key string) error
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) Store {
+	return &store{db: db}
+}
+
+func (s *store) Claim(ctx context.Context, userID int64, key, requestHash string) (Record, bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (user_id, idempotency_key, request_hash)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, idempotency_key) DO NOTHING
+	`
+	res, err := s.db.ExecContext(ctx, query, userID, key, requestHash)
+	if err != nil {
+		return Record{}, false, err
+	}
+
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return Record{}, false, err
+	}
+	if rows == 1 {
+		return Record{UserID: userID, Key: key, RequestHash: requestHash}, true, nil
+	}
+
+	// Key already taken
+	query = `
+		SELECT user_id, idempotency_key, request_hash, status_code, response_body, created_at
+		FROM idempotency_keys WHERE user_id = $1 AND idempotency_key = $2
+	`
+	var rec Record
+	var statusCode sql.NullInt64
+
+	err = s.db.QueryRowContext(ctx, query, userID, key).Scan(
+		&rec.UserID,
+		&rec.Key,
+		&rec.RequestHash,
+		&statusCode,
+		&rec.ResponseBody,
+		&rec.CreatedAt,
+	)
+	if err != nil {
+		return Record{}, false, err
+	}
+	rec.StatusCode = int(statusCode.Int64)
+	rec.Completed = statusCode.Valid
+	return rec, false, nil
+}
+
+func (s *store) Complete(ctx context.Context, userID int64, key string, statusCode int, body []byte) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $3, response_body = $4, completed_at = NOW()
+		WHERE user_id = $1 AND idempotency_key = $2
+	`
+	_, err := s.db.ExecContext(ctx, query, userID, key, statusCode, body)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Release : drop an unfinished key so the client can retry with it
+func (s *store) Release(ctx context.Context, userID int64, key string) error {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE user_id = $1 AND idempotency_key = $2 AND status_code IS NULL
+	`
+	_, err := s.db.ExecContext(ctx, query, userID, key)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *store) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM idempotency_keys WHERE created_at < $1`
+
+	res, err := s.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
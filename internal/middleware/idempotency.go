@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/authcontext"
+	"github.com/codepnw/stdlib-ticket-system/internal/config"
+	"github.com/codepnw/stdlib-ticket-system/internal/helper"
+	"github.com/codepnw/stdlib-ticket-system/internal/idempotency"
+)
+
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLen  = 255
+	maxIdempotentBodySize = 1 << 20
+)
+
+type IdempotencyMiddleware struct {
+	store idempotency.Store
+	ttl   time.Duration
+}
+
+func NewIdempotencyMiddleware(store idempotency.Store, ttl time.Duration) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{store: store, ttl: ttl}
+}
+
+// Idempotency : replay the stored response for a retried request. must run after AuthMiddleware
+func (m *IdempotencyMiddleware) Idempotency(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			helper.ErrorResponse(w, http.StatusBadRequest, "idempotency key too long")
+			return
+		}
+
+		userID := authcontext.GetUserID(r.Context())
+		if userID == 0 {
+			helper.ErrorResponse(w, http.StatusUnauthorized, "user is required")
+			return
+		}
+
+		// Read one byte past the limit, a cut off body would hash and replay as a different request
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxIdempotentBodySize+1))
+		if err != nil {
+			helper.ErrorResponse(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if len(body) > maxIdempotentBodySize {
+			helper.ErrorResponse(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := requestHash(r, body)
+
+		rec, claimed, err := m.store.Claim(r.Context(), userID, key, hash)
+		if err != nil {
+			helper.ErrorResponse(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		if !claimed {
+			switch {
+			case rec.RequestHash != hash:
+				helper.ErrorResponse(w, http.StatusUnprocessableEntity, "idempotency key already used for a different request")
+			case !rec.Completed:
+				helper.ErrorResponse(w, http.StatusConflict, "request with this idempotency key is in progress")
+			default:
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set(IdempotencyReplayedHeader, "true")
+				w.WriteHeader(rec.StatusCode)
+				w.Write(rec.ResponseBody)
+			}
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		// Save even if the client has gone away
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), config.ContextTimeout)
+		defer cancel()
+
+		if recorder.status >= http.StatusInternalServerError {
+			// Server errors are not final, let the client retry
+			if err := m.store.Release(ctx, userID, key); err != nil {
+				log.Printf("release idempotency key failed: %v", err)
+			}
+			return
+		}
+		if err := m.store.Complete(ctx, userID, key, recorder.status, recorder.body.Bytes()); err != nil {
+			log.Printf("save idempotency key failed: %v", err)
+		}
+	})
+}
+
+// Cleanup : delete keys older than the ttl
+func (m *IdempotencyMiddleware) Cleanup(ctx context.Context) error {
+	_, err := m.store.DeleteBefore(ctx, time.Now().Add(-m.ttl))
+	return err
+}
+
+func requestHash(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rr *responseRecorder) WriteHeader(code int) {
+	rr.status = code
+	rr.ResponseWriter.WriteHeader(code)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	rr.body.Write(b)
+	return rr.ResponseWriter.Write(b)
+}
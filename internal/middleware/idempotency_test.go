@@ -0,0 +1,191 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/authcontext"
+	"github.com/codepnw/stdlib-ticket-system/internal/idempotency"
+	"github.com/codepnw/stdlib-ticket-system/internal/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]idempotency.Record
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: make(map[string]idempotency.Record)}
+}
+
+func (s *memoryStore) Claim(ctx context.Context, userID int64, key, requestHash string) (idempotency.Record, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rec, ok := s.records[key]; ok {
+		return rec, false, nil
+	}
+	rec := idempotency.Record{UserID: userID, Key: key, RequestHash: requestHash, CreatedAt: time.Now()}
+	s.records[key] = rec
+	return rec, true, nil
+}
+
+func (s *memoryStore) Complete(ctx context.Context, userID int64, key string, statusCode int, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec := s.records[key]
+	rec.StatusCode = statusCode
+	rec.ResponseBody = body
+	rec.Completed = true
+	s.records[key] = rec
+	return nil
+}
+
+func (s *memoryStore) Release(ctx context.Context, userID int64, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+func (s *memoryStore) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func TestIdempotency(t *testing.T) {
+	type testCase struct {
+		name           string
+		firstBody      string
+		secondBody     string
+		handlerStatus  int
+		expectedStatus int
+		expectedCalls  int
+		replayed       bool
+	}
+
+	testCases := []testCase{
+		{
+			name:           "replay same body",
+			firstBody:      `{"event_id":1,"seat_ids":[1]}`,
+			secondBody:     `{"event_id":1,"seat_ids":[1]}`,
+			handlerStatus:  http.StatusOK,
+			expectedStatus: http.StatusOK,
+			expectedCalls:  1,
+			replayed:       true,
+		},
+		{
+			name:           "replay client error",
+			firstBody:      `{"event_id":1}`,
+			secondBody:     `{"event_id":1}`,
+			handlerStatus:  http.StatusBadRequest,
+			expectedStatus: http.StatusBadRequest,
+			expectedCalls:  1,
+			replayed:       true,
+		},
+		{
+			name:           "fail different body",
+			firstBody:      `{"event_id":1,"seat_ids":[1]}`,
+			secondBody:     `{"event_id":1,"seat_ids":[2]}`,
+			handlerStatus:  http.StatusOK,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedCalls:  1,
+		},
+		{
+			name:           "retry after server error",
+			firstBody:      `{"event_id":1,"seat_ids":[1]}`,
+			secondBody:     `{"event_id":1,"seat_ids":[1]}`,
+			handlerStatus:  http.StatusInternalServerError,
+			expectedStatus: http.StatusInternalServerError,
+			expectedCalls:  2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.WriteHeader(tc.handlerStatus)
+				w.Write([]byte(`{"success":true}`))
+			})
+			h := middleware.NewIdempotencyMiddleware(newMemoryStore(), time.Hour).Idempotency(next)
+
+			first := httptest.NewRecorder()
+			h.ServeHTTP(first, newRequest(tc.firstBody, "key-1"))
+			assert.Equal(t, tc.handlerStatus, first.Code)
+
+			second := httptest.NewRecorder()
+			h.ServeHTTP(second, newRequest(tc.secondBody, "key-1"))
+
+			assert.Equal(t, tc.expectedStatus, second.Code)
+			assert.Equal(t, tc.expectedCalls, calls)
+			if tc.replayed {
+				assert.Equal(t, "true", second.Header().Get(middleware.IdempotencyReplayedHeader))
+				assert.Equal(t, first.Body.String(), second.Body.String())
+			}
+		})
+	}
+}
+
+func TestIdempotencyInProgress(t *testing.T) {
+	var h http.Handler
+	var retry *httptest.ResponseRecorder
+
+	calls := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		// Client retries while the first request is still running
+		retry = httptest.NewRecorder()
+		h.ServeHTTP(retry, newRequest(`{"event_id":1}`, "key-1"))
+		w.WriteHeader(http.StatusOK)
+	})
+	h = middleware.NewIdempotencyMiddleware(newMemoryStore(), time.Hour).Idempotency(next)
+
+	h.ServeHTTP(httptest.NewRecorder(), newRequest(`{"event_id":1}`, "key-1"))
+
+	assert.Equal(t, http.StatusConflict, retry.Code)
+	assert.Equal(t, 1, calls)
+}
+
+func TestIdempotencyWithoutKey(t *testing.T) {
+	calls := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	})
+	h := middleware.NewIdempotencyMiddleware(newMemoryStore(), time.Hour).Idempotency(next)
+
+	h.ServeHTTP(httptest.NewRecorder(), newRequest(`{}`, ""))
+	h.ServeHTTP(httptest.NewRecorder(), newRequest(`{}`, ""))
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestIdempotencyBodyTooLarge(t *testing.T) {
+	calls := 0
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	})
+	h := middleware.NewIdempotencyMiddleware(newMemoryStore(), time.Hour).Idempotency(next)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newRequest(strings.Repeat("a", 1<<20+1), "key-1"))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	assert.Equal(t, 0, calls)
+}
+
+func newRequest(body, key string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/bookings", strings.NewReader(body))
+	if key != "" {
+		r.Header.Set(middleware.IdempotencyKeyHeader, key)
+	}
+	return r.WithContext(authcontext.SetUserID(r.Context(), 1))
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/codepnw/stdlib-ticket-system/internal/config"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/user"
 	"github.com/codepnw/stdlib-ticket-system/internal/helper"
 	jwttoken "github.com/codepnw/stdlib-ticket-system/pkg/jwt"
 )
@@ -47,3 +48,20 @@ func (m *AuthMiddleware) AuthMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// RequireStaff : reject non-staff users. must run after AuthMiddleware
+func (m *AuthMiddleware) RequireStaff(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := r.Context().Value(config.ContextUserClaimsKey).(*jwttoken.Payload)
+		if !ok {
+			helper.ErrorResponse(w, http.StatusUnauthorized, "user is required")
+			return
+		}
+		if claims.Role != user.RoleStaff {
+			helper.ErrorResponse(w, http.StatusForbidden, "staff only")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,59 @@
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/config"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/user"
+	"github.com/codepnw/stdlib-ticket-system/internal/middleware"
+	jwttoken "github.com/codepnw/stdlib-ticket-system/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireStaff(t *testing.T) {
+	type testCase struct {
+		name     string
+		claims   *jwttoken.Payload
+		expected int
+	}
+
+	testCases := []testCase{
+		{
+			name:     "success staff",
+			claims:   &jwttoken.Payload{UserID: 1, Role: user.RoleStaff},
+			expected: http.StatusOK,
+		},
+		{
+			name:     "fail customer",
+			claims:   &jwttoken.Payload{UserID: 1, Role: user.RoleCustomer},
+			expected: http.StatusForbidden,
+		},
+		{
+			name:     "fail no claims",
+			claims:   nil,
+			expected: http.StatusUnauthorized,
+		},
+	}
+
+	m := middleware.NewMiddleware(nil)
+	handler := m.RequireStaff(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/events/1/checkins/stats", nil)
+			if tc.claims != nil {
+				req = req.WithContext(context.WithValue(req.Context(), config.ContextUserClaimsKey, tc.claims))
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, tc.expected, rec.Code)
+		})
+	}
+}
@@ -1,35 +1,76 @@
 package server
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"net/http"
 	"time"
 
+	"github.com/codepnw/stdlib-ticket-system/internal/config"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/availability"
+	availabilityhandler "github.com/codepnw/stdlib-ticket-system/internal/features/availability/handler"
+	availabilityusecase "github.com/codepnw/stdlib-ticket-system/internal/features/availability/usecase"
 	bookinghandler "github.com/codepnw/stdlib-ticket-system/internal/features/booking/handler"
 	bookingrepo "github.com/codepnw/stdlib-ticket-system/internal/features/booking/repo"
 	bookingusecase "github.com/codepnw/stdlib-ticket-system/internal/features/booking/usecase"
+	carthandler "github.com/codepnw/stdlib-ticket-system/internal/features/cart/handler"
+	cartrepo "github.com/codepnw/stdlib-ticket-system/internal/features/cart/repo"
+	cartusecase "github.com/codepnw/stdlib-ticket-system/internal/features/cart/usecase"
+	checkinhandler "github.com/codepnw/stdlib-ticket-system/internal/features/checkin/handler"
+	checkinrepo "github.com/codepnw/stdlib-ticket-system/internal/features/checkin/repo"
+	checkinusecase "github.com/codepnw/stdlib-ticket-system/internal/features/checkin/usecase"
 	eventhandler "github.com/codepnw/stdlib-ticket-system/internal/features/event/handler"
 	eventrepo "github.com/codepnw/stdlib-ticket-system/internal/features/event/repo"
 	eventusecase "github.com/codepnw/stdlib-ticket-system/internal/features/event/usecase"
+	paymentgateway "github.com/codepnw/stdlib-ticket-system/internal/features/payment/gateway"
+	paymenthandler "github.com/codepnw/stdlib-ticket-system/internal/features/payment/handler"
+	paymentrepo "github.com/codepnw/stdlib-ticket-system/internal/features/payment/repo"
+	paymentusecase "github.com/codepnw/stdlib-ticket-system/internal/features/payment/usecase"
+	promohandler "github.com/codepnw/stdlib-ticket-system/internal/features/promo/handler"
+	promorepo "github.com/codepnw/stdlib-ticket-system/internal/features/promo/repo"
+	promousecase "github.com/codepnw/stdlib-ticket-system/internal/features/promo/usecase"
+	resalehandler "github.com/codepnw/stdlib-ticket-system/internal/features/resale/handler"
+	resalerepo "github.com/codepnw/stdlib-ticket-system/internal/features/resale/repo"
+	resaleusecase "github.com/codepnw/stdlib-ticket-system/internal/features/resale/usecase"
+	"github.com/codepnw/stdlib-ticket-system/internal/features/seat"
 	seatrepo "github.com/codepnw/stdlib-ticket-system/internal/features/seat/repo"
+	transferhandler "github.com/codepnw/stdlib-ticket-system/internal/features/transfer/handler"
+	transferrepo "github.com/codepnw/stdlib-ticket-system/internal/features/transfer/repo"
+	transferusecase "github.com/codepnw/stdlib-ticket-system/internal/features/transfer/usecase"
 	userhandler "github.com/codepnw/stdlib-ticket-system/internal/features/user/handler"
 	userrepo "github.com/codepnw/stdlib-ticket-system/internal/features/user/repo"
 	userusecase "github.com/codepnw/stdlib-ticket-system/internal/features/user/usecase"
+	waitlisthandler "github.com/codepnw/stdlib-ticket-system/internal/features/waitlist/handler"
+	waitlistrepo "github.com/codepnw/stdlib-ticket-system/internal/features/waitlist/repo"
+	waitlistusecase "github.com/codepnw/stdlib-ticket-system/internal/features/waitlist/usecase"
 	"github.com/codepnw/stdlib-ticket-system/internal/middleware"
+	"github.com/codepnw/stdlib-ticket-system/internal/worker"
 	"github.com/codepnw/stdlib-ticket-system/pkg/database"
 	jwttoken "github.com/codepnw/stdlib-ticket-system/pkg/jwt"
+	"github.com/codepnw/stdlib-ticket-system/pkg/pubsub"
+	"github.com/codepnw/stdlib-ticket-system/pkg/ticketcode"
 	"github.com/codepnw/stdlib-ticket-system/pkg/utils"
 )
 
 type ServerConfig struct {
-	Location   *time.Location             `validate:"required"`
-	DB         *sql.DB                    `validate:"required"`
-	Mux        *http.ServeMux             `validate:"required"`
-	Tx         database.TxManager         `validate:"required"`
-	Addr       string                     `validate:"required"`
-	Token      jwttoken.JWTToken          `validate:"required"`
-	Middleware *middleware.AuthMiddleware `validate:"required"`
+	Location    *time.Location                    `validate:"required"`
+	DB          *sql.DB                           `validate:"required"`
+	Mux         *http.ServeMux                    `validate:"required"`
+	Tx          database.TxManager                `validate:"required"`
+	Addr        string                            `validate:"required"`
+	Token       jwttoken.JWTToken                 `validate:"required"`
+	Tickets     ticketcode.Signer                 `validate:"required"`
+	Middleware  *middleware.AuthMiddleware        `validate:"required"`
+	Idempotency *middleware.IdempotencyMiddleware `validate:"required"`
+	Booking     config.BookingConfig
+	Waitlist    config.WaitlistConfig
+	Transfer    config.TransferConfig
+	Resale      config.ResaleConfig
+	Stream      config.StreamConfig
+	Cart        config.CartConfig
+	Payment     config.PaymentConfig
+	Gateway     paymentgateway.PaymentGateway `validate:"required"`
 }
 
 func Run(cfg *ServerConfig) error {
@@ -37,9 +78,23 @@ func Run(cfg *ServerConfig) error {
 		return err
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	cfg.eventRoutes()
 	cfg.userRoutes()
-	cfg.bookingRoutes()
+	stream := cfg.streamRoutes()
+	waitlist := cfg.waitlistRoutes(ctx, stream)
+	cfg.bookingRoutes(ctx, waitlist, stream)
+	cfg.paymentRoutes(ctx, waitlist, stream)
+	cfg.cartRoutes(ctx, waitlist, stream)
+	cfg.checkinRoutes()
+	cfg.transferRoutes()
+	cfg.resaleRoutes(ctx)
+	cfg.promoRoutes()
+
+	// Background Workers
+	go worker.RunEvery(ctx, "idempotency-cleanup", time.Hour, cfg.Idempotency.Cleanup)
 
 	log.Println("server running...")
 
@@ -61,6 +116,19 @@ func (cfg ServerConfig) eventRoutes() {
 	cfg.Mux.HandleFunc("GET /events/{event_id}/seats", handler.GetSeatsByEventID)
 }
 
+// streamRoutes : the usecase is returned so booking changes reach the live seat maps
+func (cfg ServerConfig) streamRoutes() availabilityusecase.AvailabilityUsecase {
+	seatRepo := seatrepo.NewSeatRepository(cfg.DB)
+	eventRepo := eventrepo.NewEventRepository(cfg.DB)
+	hub := pubsub.NewHub[int64, []availability.SeatStatus](cfg.Stream.ClientBuffer)
+	uc := availabilityusecase.NewAvailabilityUsecase(hub, seatRepo, eventRepo)
+	handler := availabilityhandler.NewAvailabilityHandler(uc, cfg.Stream.Heartbeat)
+
+	cfg.Mux.HandleFunc("GET /events/{event_id}/seats/stream", handler.StreamSeats)
+
+	return uc
+}
+
 func (cfg ServerConfig) userRoutes() {
 	repo := userrepo.NewUserRepository(cfg.DB)
 	uc := userusecase.NewUserUsecase(cfg.Tx, cfg.Token, repo)
@@ -70,13 +138,132 @@ func (cfg ServerConfig) userRoutes() {
 	cfg.Mux.HandleFunc("POST /login", handler.Login)
 }
 
-func (cfg ServerConfig) bookingRoutes() {
+func (cfg ServerConfig) bookingRoutes(ctx context.Context, offerer bookingusecase.SeatOfferer, notifier bookingusecase.SeatNotifier) {
 	bookRepo := bookingrepo.NewBookingRepository(cfg.DB)
 	seatRepo := seatrepo.NewSeatRepository(cfg.DB)
-	uc := bookingusecase.NewBookingUsecase(cfg.Location, cfg.Tx, bookRepo, seatRepo)
+	eventRepo := eventrepo.NewEventRepository(cfg.DB)
+	promoRepo := promorepo.NewPromoRepository(cfg.DB)
+	paymentRepo := paymentrepo.NewPaymentRepository(cfg.DB)
+	refunder := paymentusecase.NewPaymentUsecase(cfg.Tx, cfg.Gateway, paymentRepo, bookRepo, seatRepo, eventRepo, offerer, notifier)
+	uc := bookingusecase.NewBookingUsecase(cfg.Location, cfg.Booking.HoldDuration, seat.LockStrategy(cfg.Booking.LockStrategy), cfg.Tx, bookRepo, seatRepo, eventRepo, promoRepo, refunder, offerer, notifier, cfg.Tickets)
 	handler := bookinghandler.NewBookingHandler(uc)
-	
-	cfg.Mux.Handle("POST /bookings", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.CreateBooking)))
+
+	// Release expired seat holds
+	go worker.RunEvery(ctx, "booking-expiry", cfg.Booking.SweepInterval, uc.ExpireBookings)
+
+	cfg.Mux.Handle("POST /bookings", cfg.Middleware.AuthMiddleware(cfg.Idempotency.Idempotency(http.HandlerFunc(handler.CreateBooking))))
 	cfg.Mux.Handle("GET /bookings/me", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.GetBookingHistory)))
+	cfg.Mux.Handle("GET /bookings/{booking_id}", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.GetBookingDetail)))
+	cfg.Mux.Handle("GET /bookings/{booking_id}/tickets", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.GetTickets)))
 	cfg.Mux.Handle("POST /bookings/cancel", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.CancelBooking)))
+	cfg.Mux.Handle("POST /bookings/{booking_id}/seats/cancel", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.CancelSeats)))
+}
+
+func (cfg ServerConfig) cartRoutes(ctx context.Context, offerer bookingusecase.SeatOfferer, notifier bookingusecase.SeatNotifier) {
+	cartRepo := cartrepo.NewCartRepository(cfg.DB)
+	bookRepo := bookingrepo.NewBookingRepository(cfg.DB)
+	seatRepo := seatrepo.NewSeatRepository(cfg.DB)
+	eventRepo := eventrepo.NewEventRepository(cfg.DB)
+	uc := cartusecase.NewCartUsecase(cfg.Location, cfg.Cart.HoldDuration, cfg.Booking.HoldDuration, cfg.Tx, cartRepo, bookRepo, seatRepo, eventRepo, offerer, notifier)
+	handler := carthandler.NewCartHandler(uc)
+
+	// Release expired cart holds
+	go worker.RunEvery(ctx, "cart-expiry", cfg.Cart.SweepInterval, uc.ExpireHolds)
+
+	cfg.Mux.Handle("GET /cart", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.GetCart)))
+	cfg.Mux.Handle("POST /cart/events/{event_id}", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.AddSeats)))
+	cfg.Mux.Handle("DELETE /cart/events/{event_id}", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.RemoveEvent)))
+	cfg.Mux.Handle("POST /cart/checkout", cfg.Middleware.AuthMiddleware(cfg.Idempotency.Idempotency(http.HandlerFunc(handler.Checkout))))
+}
+
+func (cfg ServerConfig) paymentRoutes(ctx context.Context, offerer bookingusecase.SeatOfferer, notifier bookingusecase.SeatNotifier) {
+	paymentRepo := paymentrepo.NewPaymentRepository(cfg.DB)
+	bookRepo := bookingrepo.NewBookingRepository(cfg.DB)
+	seatRepo := seatrepo.NewSeatRepository(cfg.DB)
+	eventRepo := eventrepo.NewEventRepository(cfg.DB)
+	uc := paymentusecase.NewPaymentUsecase(cfg.Tx, cfg.Gateway, paymentRepo, bookRepo, seatRepo, eventRepo, offerer, notifier)
+	handler := paymenthandler.NewPaymentHandler(uc)
+
+	// Send refunds whose gateway call failed after the booking change
+	go worker.RunEvery(ctx, "refund-settlement", cfg.Payment.SweepInterval, uc.SettleRefunds)
+
+	cfg.Mux.Handle("POST /bookings/{booking_id}/pay", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.PayBooking)))
+}
+
+func (cfg ServerConfig) checkinRoutes() {
+	checkinRepo := checkinrepo.NewCheckinRepository(cfg.DB)
+	eventRepo := eventrepo.NewEventRepository(cfg.DB)
+	uc := checkinusecase.NewCheckinUsecase(cfg.Location, cfg.Tx, checkinRepo, eventRepo)
+	handler := checkinhandler.NewCheckinHandler(uc)
+
+	staff := func(h http.HandlerFunc) http.Handler {
+		return cfg.Middleware.AuthMiddleware(cfg.Middleware.RequireStaff(h))
+	}
+
+	cfg.Mux.Handle("POST /events/{event_id}/checkins", staff(handler.CheckIn))
+	cfg.Mux.Handle("GET /events/{event_id}/checkins/stats", staff(handler.GetStats))
+}
+
+func (cfg ServerConfig) transferRoutes() {
+	transferRepo := transferrepo.NewTransferRepository(cfg.DB)
+	bookRepo := bookingrepo.NewBookingRepository(cfg.DB)
+	eventRepo := eventrepo.NewEventRepository(cfg.DB)
+	userRepo := userrepo.NewUserRepository(cfg.DB)
+	uc := transferusecase.NewTransferUsecase(cfg.Location, cfg.Transfer.Cutoff, cfg.Tx, transferRepo, bookRepo, eventRepo, userRepo)
+	handler := transferhandler.NewTransferHandler(uc)
+
+	cfg.Mux.Handle("POST /bookings/{booking_id}/transfers", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.CreateTransfer)))
+	cfg.Mux.Handle("GET /transfers/me", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.GetMyTransfers)))
+	cfg.Mux.Handle("POST /transfers/{transfer_id}/accept", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.AcceptTransfer)))
+	cfg.Mux.Handle("POST /transfers/{transfer_id}/decline", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.DeclineTransfer)))
+	cfg.Mux.Handle("POST /transfers/{transfer_id}/cancel", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.CancelTransfer)))
+}
+
+func (cfg ServerConfig) resaleRoutes(ctx context.Context) {
+	resaleRepo := resalerepo.NewResaleRepository(cfg.DB)
+	paymentRepo := paymentrepo.NewPaymentRepository(cfg.DB)
+	bookRepo := bookingrepo.NewBookingRepository(cfg.DB)
+	eventRepo := eventrepo.NewEventRepository(cfg.DB)
+	uc := resaleusecase.NewResaleUsecase(cfg.Location, cfg.Resale.FeePercent, cfg.Tx, cfg.Gateway, resaleRepo, paymentRepo, bookRepo, eventRepo)
+	handler := resalehandler.NewResaleHandler(uc)
+
+	// Pay sellers whose payout failed after the sale
+	go worker.RunEvery(ctx, "resale-payouts", cfg.Resale.SweepInterval, uc.SettlePayouts)
+
+	cfg.Mux.Handle("POST /bookings/{booking_id}/resale", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.ListSeat)))
+	cfg.Mux.Handle("POST /resale/{listing_id}/cancel", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.CancelListing)))
+	cfg.Mux.Handle("POST /resale/{listing_id}/buy", cfg.Middleware.AuthMiddleware(cfg.Idempotency.Idempotency(http.HandlerFunc(handler.BuyListing))))
+}
+
+func (cfg ServerConfig) promoRoutes() {
+	repo := promorepo.NewPromoRepository(cfg.DB)
+	uc := promousecase.NewPromoUsecase(repo)
+	handler := promohandler.NewPromoHandler(uc)
+
+	staff := func(h http.HandlerFunc) http.Handler {
+		return cfg.Middleware.AuthMiddleware(cfg.Middleware.RequireStaff(h))
+	}
+
+	cfg.Mux.Handle("POST /promos", staff(handler.CreatePromo))
+	cfg.Mux.Handle("GET /promos", staff(handler.GetAllPromos))
+}
+
+// waitlistRoutes : the usecase is returned so freed seats elsewhere can be offered to the queue
+func (cfg ServerConfig) waitlistRoutes(ctx context.Context, notifier bookingusecase.SeatNotifier) waitlistusecase.WaitlistUsecase {
+	waitRepo := waitlistrepo.NewWaitlistRepository(cfg.DB)
+	bookRepo := bookingrepo.NewBookingRepository(cfg.DB)
+	seatRepo := seatrepo.NewSeatRepository(cfg.DB)
+	eventRepo := eventrepo.NewEventRepository(cfg.DB)
+	uc := waitlistusecase.NewWaitlistUsecase(cfg.Location, cfg.Waitlist.OfferDuration, cfg.Booking.HoldDuration, cfg.Tx, waitRepo, bookRepo, seatRepo, eventRepo, notifier)
+	handler := waitlisthandler.NewWaitlistHandler(uc)
+
+	// Pass lapsed offers to the next in line
+	go worker.RunEvery(ctx, "waitlist-offer-expiry", cfg.Waitlist.SweepInterval, uc.ExpireOffers)
+
+	cfg.Mux.Handle("POST /events/{event_id}/waitlist", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.JoinWaitlist)))
+	cfg.Mux.Handle("GET /waitlist/me", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.GetMyEntries)))
+	cfg.Mux.Handle("POST /waitlist/{entry_id}/accept", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.AcceptOffer)))
+	cfg.Mux.Handle("POST /waitlist/{entry_id}/leave", cfg.Middleware.AuthMiddleware(http.HandlerFunc(handler.LeaveWaitlist)))
+
+	return uc
 }
@@ -0,0 +1,27 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RunEvery : call fn on every tick until ctx is done
+func RunEvery(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Printf("%s worker started (every %s)", name, interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("%s worker stopped", name)
+			return
+		case <-ticker.C:
+			if err := fn(ctx); err != nil {
+				log.Printf("%s worker failed: %v", name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_bookings_pending_expires;
+
+DROP INDEX IF EXISTS idx_unique_active_seat;
+
+ALTER TABLE booking_items DROP COLUMN IF EXISTS released_at;
+
+CREATE UNIQUE INDEX idx_unique_seat ON booking_items(seat_id);
+
+ALTER TABLE bookings
+ALTER COLUMN expires_at TYPE TIMESTAMP;
@@ -0,0 +1,15 @@
+ALTER TABLE bookings
+ALTER COLUMN expires_at TYPE TIMESTAMPTZ;
+
+ALTER TABLE booking_items
+ADD COLUMN released_at TIMESTAMPTZ;
+
+UPDATE booking_items bi SET released_at = NOW()
+FROM bookings b
+WHERE b.id = bi.booking_id AND b.status IN ('CANCELLED', 'FAILED');
+
+DROP INDEX IF EXISTS idx_unique_seat;
+
+CREATE UNIQUE INDEX idx_unique_active_seat ON booking_items(seat_id) WHERE released_at IS NULL;
+
+CREATE INDEX idx_bookings_pending_expires ON bookings(expires_at) WHERE status = 'PENDING';
@@ -0,0 +1,15 @@
+CREATE TYPE payments_status AS ENUM ('CAPTURED', 'FAILED', 'REFUNDED');
+
+CREATE TABLE IF NOT EXISTS payments (
+    id BIGSERIAL PRIMARY KEY,
+    booking_id UUID NOT NULL REFERENCES bookings(id),
+    provider VARCHAR(50) NOT NULL,
+    authorization_id VARCHAR(255),
+    capture_id VARCHAR(255),
+    amount DECIMAL(10, 2) NOT NULL,
+    status payments_status NOT NULL,
+    failure_reason TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_payments_booking_id ON payments(booking_id);
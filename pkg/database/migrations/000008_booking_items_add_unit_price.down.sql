@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_booking_items_booking_id;
+
+ALTER TABLE booking_items DROP COLUMN IF EXISTS unit_price;
+
+ALTER TABLE seats DROP COLUMN IF EXISTS zone;
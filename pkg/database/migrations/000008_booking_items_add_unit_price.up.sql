@@ -0,0 +1,13 @@
+ALTER TABLE seats
+ADD COLUMN zone VARCHAR(50);
+
+UPDATE seats SET zone = regexp_replace(seat_number, '[0-9]+$', '');
+
+ALTER TABLE booking_items
+ADD COLUMN unit_price DECIMAL(10, 2);
+
+UPDATE booking_items bi SET unit_price = s.price
+FROM seats s
+WHERE s.id = bi.seat_id;
+
+CREATE INDEX idx_booking_items_booking_id ON booking_items(booking_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS refunds;
+
+DROP TABLE IF EXISTS event_refund_policies;
+
+-- enum values cannot be dropped, REFUNDED stays on bookings_status
@@ -0,0 +1,21 @@
+ALTER TYPE bookings_status ADD VALUE IF NOT EXISTS 'REFUNDED';
+
+CREATE TABLE IF NOT EXISTS event_refund_policies (
+    event_id BIGINT PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+    full_refund_days INT NOT NULL,
+    partial_refund_days INT NOT NULL,
+    partial_refund_percent INT NOT NULL CHECK (partial_refund_percent BETWEEN 0 AND 100),
+    CHECK (full_refund_days >= partial_refund_days AND partial_refund_days >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS refunds (
+    id BIGSERIAL PRIMARY KEY,
+    booking_id UUID NOT NULL REFERENCES bookings(id),
+    payment_id BIGINT NOT NULL REFERENCES payments(id),
+    amount DECIMAL(10, 2) NOT NULL,
+    percent INT NOT NULL,
+    provider_refund_id VARCHAR(255),
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_refunds_booking_id ON refunds(booking_id);
@@ -0,0 +1,3 @@
+ALTER TABLE events
+DROP COLUMN IF EXISTS max_seats_per_order,
+DROP COLUMN IF EXISTS max_seats_per_user;
@@ -0,0 +1,3 @@
+ALTER TABLE events
+ADD COLUMN max_seats_per_order INT NOT NULL DEFAULT 0,
+ADD COLUMN max_seats_per_user INT NOT NULL DEFAULT 0;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS waitlist_offer_seats;
+
+DROP TABLE IF EXISTS waitlist_entries;
+
+DROP TYPE IF EXISTS waitlist_status;
@@ -0,0 +1,28 @@
+CREATE TYPE waitlist_status AS ENUM ('WAITING', 'OFFERED', 'ACCEPTED', 'EXPIRED', 'CANCELLED');
+
+CREATE TABLE waitlist_entries (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id),
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    seat_count INT NOT NULL CHECK (seat_count > 0),
+    status waitlist_status NOT NULL DEFAULT 'WAITING',
+    offer_expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+-- One open entry per user and event
+CREATE UNIQUE INDEX idx_waitlist_open_entry ON waitlist_entries(event_id, user_id)
+WHERE status IN ('WAITING', 'OFFERED');
+
+CREATE INDEX idx_waitlist_queue ON waitlist_entries(event_id, created_at)
+WHERE status = 'WAITING';
+
+CREATE INDEX idx_waitlist_offer_expiry ON waitlist_entries(offer_expires_at)
+WHERE status = 'OFFERED';
+
+CREATE TABLE waitlist_offer_seats (
+    entry_id BIGINT NOT NULL REFERENCES waitlist_entries(id) ON DELETE CASCADE,
+    seat_id BIGINT NOT NULL REFERENCES seats(id),
+    PRIMARY KEY (entry_id, seat_id)
+);
@@ -0,0 +1,8 @@
+ALTER TABLE booking_items
+DROP COLUMN IF EXISTS checked_in_by,
+DROP COLUMN IF EXISTS checked_in_gate,
+DROP COLUMN IF EXISTS checked_in_at;
+
+ALTER TABLE users DROP COLUMN IF EXISTS role;
+
+DROP TYPE IF EXISTS user_role;
@@ -0,0 +1,9 @@
+CREATE TYPE user_role AS ENUM ('CUSTOMER', 'STAFF');
+
+ALTER TABLE users
+ADD COLUMN role user_role NOT NULL DEFAULT 'CUSTOMER';
+
+ALTER TABLE booking_items
+ADD COLUMN checked_in_at TIMESTAMPTZ,
+ADD COLUMN checked_in_gate VARCHAR(50),
+ADD COLUMN checked_in_by BIGINT REFERENCES users(id);
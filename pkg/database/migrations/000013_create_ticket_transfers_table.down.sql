@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS ticket_transfer_seats;
+
+DROP TABLE IF EXISTS ticket_transfers;
+
+DROP TYPE IF EXISTS transfer_status;
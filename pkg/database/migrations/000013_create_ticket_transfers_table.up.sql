@@ -0,0 +1,24 @@
+CREATE TYPE transfer_status AS ENUM ('PENDING', 'ACCEPTED', 'DECLINED', 'CANCELLED');
+
+CREATE TABLE ticket_transfers (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id),
+    from_booking_id UUID NOT NULL REFERENCES bookings(id),
+    to_booking_id UUID REFERENCES bookings(id),
+    from_user_id BIGINT NOT NULL REFERENCES users(id),
+    to_user_id BIGINT NOT NULL REFERENCES users(id),
+    status transfer_status NOT NULL DEFAULT 'PENDING',
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    responded_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_ticket_transfers_from_user ON ticket_transfers(from_user_id);
+CREATE INDEX idx_ticket_transfers_to_user ON ticket_transfers(to_user_id);
+
+CREATE TABLE ticket_transfer_seats (
+    transfer_id BIGINT NOT NULL REFERENCES ticket_transfers(id) ON DELETE CASCADE,
+    seat_id BIGINT NOT NULL REFERENCES seats(id),
+    PRIMARY KEY (transfer_id, seat_id)
+);
+
+CREATE INDEX idx_ticket_transfer_seats_seat_id ON ticket_transfer_seats(seat_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS resale_listings;
+
+DROP TYPE IF EXISTS resale_status;
+
+ALTER TABLE events DROP COLUMN IF EXISTS resale_cap_percent;
@@ -0,0 +1,27 @@
+ALTER TABLE events
+ADD COLUMN resale_cap_percent INT NOT NULL DEFAULT 0;
+
+CREATE TYPE resale_status AS ENUM ('LISTED', 'SOLD', 'CANCELLED');
+
+CREATE TABLE resale_listings (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id),
+    seat_id BIGINT NOT NULL REFERENCES seats(id),
+    seller_booking_id UUID NOT NULL REFERENCES bookings(id),
+    seller_id BIGINT NOT NULL REFERENCES users(id),
+    price DECIMAL(10, 2) NOT NULL CHECK (price > 0),
+    status resale_status NOT NULL DEFAULT 'LISTED',
+    buyer_id BIGINT REFERENCES users(id),
+    buyer_booking_id UUID REFERENCES bookings(id),
+    capture_id VARCHAR(255),
+    fee DECIMAL(10, 2),
+    payout_amount DECIMAL(10, 2),
+    payout_id VARCHAR(255),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    sold_at TIMESTAMPTZ
+);
+
+-- One open listing per seat
+CREATE UNIQUE INDEX idx_resale_listed_seat ON resale_listings(seat_id) WHERE status = 'LISTED';
+
+CREATE INDEX idx_resale_listings_event ON resale_listings(event_id) WHERE status = 'LISTED';
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_seats_layout;
+
+ALTER TABLE seats
+DROP COLUMN IF EXISTS is_aisle,
+DROP COLUMN IF EXISTS pos_y,
+DROP COLUMN IF EXISTS pos_x,
+DROP COLUMN IF EXISTS position,
+DROP COLUMN IF EXISTS row_label,
+DROP COLUMN IF EXISTS section;
+
+-- Section seat numbers such as "FLOOR-A12" do not fit the old column, the down migration stops on them
+ALTER TABLE seats ALTER COLUMN seat_number TYPE VARCHAR(10);
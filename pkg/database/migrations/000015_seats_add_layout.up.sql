@@ -0,0 +1,26 @@
+ALTER TABLE seats
+ALTER COLUMN seat_number TYPE VARCHAR(30),
+ADD COLUMN section VARCHAR(50) NOT NULL DEFAULT '',
+ADD COLUMN row_label VARCHAR(10) NOT NULL DEFAULT '',
+ADD COLUMN position INT NOT NULL DEFAULT 0,
+ADD COLUMN pos_x INT NOT NULL DEFAULT 0,
+ADD COLUMN pos_y INT NOT NULL DEFAULT 0,
+ADD COLUMN is_aisle BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Older events have one row per zone, "A12" is the 12th seat of that row.
+-- Zones were created in order, so the zone's rank by first seat id is its row on the map
+WITH zone_rows AS (
+    SELECT event_id, zone, ROW_NUMBER() OVER (PARTITION BY event_id ORDER BY MIN(id)) - 1 AS pos_y
+    FROM seats
+    GROUP BY event_id, zone
+)
+UPDATE seats s SET
+    section = COALESCE(s.zone, ''),
+    row_label = '1',
+    position = COALESCE(SUBSTRING(s.seat_number FROM '[0-9]+$')::INT, 0),
+    pos_x = GREATEST(COALESCE(SUBSTRING(s.seat_number FROM '[0-9]+$')::INT, 1) - 1, 0),
+    pos_y = zr.pos_y
+FROM zone_rows zr
+WHERE zr.event_id = s.event_id AND zr.zone IS NOT DISTINCT FROM s.zone;
+
+CREATE INDEX idx_seats_layout ON seats(event_id, section, row_label, position);
@@ -0,0 +1,11 @@
+CREATE TABLE cart_items (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    event_id BIGINT NOT NULL REFERENCES events(id),
+    seat_id BIGINT NOT NULL UNIQUE REFERENCES seats(id),
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_cart_items_user_event ON cart_items(user_id, event_id);
+CREATE INDEX idx_cart_items_expires_at ON cart_items(expires_at);
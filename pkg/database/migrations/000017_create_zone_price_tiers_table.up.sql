@@ -0,0 +1,12 @@
+CREATE TABLE zone_price_tiers (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    zone VARCHAR(50) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    price DECIMAL(10, 2) NOT NULL CHECK (price >= 0),
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    CHECK (starts_at IS NULL OR ends_at IS NULL OR starts_at < ends_at)
+);
+
+CREATE INDEX idx_zone_price_tiers_event_zone ON zone_price_tiers(event_id, zone);
@@ -0,0 +1,6 @@
+ALTER TABLE bookings DROP COLUMN IF EXISTS discount_amount;
+
+DROP TABLE IF EXISTS promo_redemptions;
+DROP TABLE IF EXISTS promo_codes;
+
+DROP TYPE IF EXISTS promo_discount_type;
@@ -0,0 +1,32 @@
+CREATE TYPE promo_discount_type AS ENUM ('PERCENT', 'FIXED');
+
+CREATE TABLE promo_codes (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    discount_type promo_discount_type NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount > 0),
+    event_id BIGINT REFERENCES events(id) ON DELETE CASCADE,
+    zone VARCHAR(50),
+    starts_at TIMESTAMPTZ,
+    ends_at TIMESTAMPTZ,
+    max_uses INT NOT NULL DEFAULT 0 CHECK (max_uses >= 0),
+    max_uses_per_user INT NOT NULL DEFAULT 0 CHECK (max_uses_per_user >= 0),
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    CHECK (discount_type <> 'PERCENT' OR amount <= 100),
+    CHECK (zone IS NULL OR event_id IS NOT NULL),
+    CHECK (starts_at IS NULL OR ends_at IS NULL OR starts_at < ends_at)
+);
+
+CREATE TABLE promo_redemptions (
+    id BIGSERIAL PRIMARY KEY,
+    promo_id BIGINT NOT NULL REFERENCES promo_codes(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    booking_id UUID NOT NULL UNIQUE REFERENCES bookings(id),
+    discount DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_promo_redemptions_promo_user ON promo_redemptions(promo_id, user_id);
+
+ALTER TABLE bookings
+ADD COLUMN discount_amount DECIMAL(10, 2) NOT NULL DEFAULT 0;
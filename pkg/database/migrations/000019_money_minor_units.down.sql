@@ -0,0 +1,31 @@
+ALTER TABLE promo_codes DROP CONSTRAINT IF EXISTS promo_codes_discount_check;
+
+ALTER TABLE promo_codes
+ADD COLUMN amount DECIMAL(10, 2);
+
+UPDATE promo_codes SET amount = COALESCE(percent, fixed_amount / 100.0);
+
+ALTER TABLE promo_codes
+ALTER COLUMN amount SET NOT NULL,
+ADD CHECK (amount > 0),
+ADD CHECK (discount_type <> 'PERCENT' OR amount <= 100),
+DROP COLUMN IF EXISTS currency,
+DROP COLUMN IF EXISTS fixed_amount,
+DROP COLUMN IF EXISTS percent;
+
+ALTER TABLE resale_listings
+ALTER COLUMN price TYPE DECIMAL(10, 2) USING price / 100.0,
+ALTER COLUMN fee TYPE DECIMAL(10, 2) USING fee / 100.0,
+ALTER COLUMN payout_amount TYPE DECIMAL(10, 2) USING payout_amount / 100.0;
+
+ALTER TABLE promo_redemptions ALTER COLUMN discount TYPE DECIMAL(10, 2) USING discount / 100.0;
+ALTER TABLE refunds ALTER COLUMN amount TYPE DECIMAL(10, 2) USING amount / 100.0;
+ALTER TABLE payments ALTER COLUMN amount TYPE DECIMAL(10, 2) USING amount / 100.0;
+ALTER TABLE booking_items ALTER COLUMN unit_price TYPE DECIMAL(10, 2) USING unit_price / 100.0;
+ALTER TABLE bookings ALTER COLUMN discount_amount TYPE DECIMAL(10, 2) USING discount_amount / 100.0;
+ALTER TABLE bookings ALTER COLUMN total_amount TYPE DECIMAL(10, 2) USING total_amount / 100.0;
+ALTER TABLE zone_price_tiers ALTER COLUMN price TYPE DECIMAL(10, 2) USING price / 100.0;
+ALTER TABLE seats ALTER COLUMN price TYPE DECIMAL(10, 2) USING price / 100.0;
+
+ALTER TABLE bookings DROP COLUMN IF EXISTS currency;
+ALTER TABLE events DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,43 @@
+-- Amounts are stored in the minor unit of their currency, 10.50 THB is 1050.
+-- Every event so far was sold in THB, so existing rows are THB and scale by 100.
+
+ALTER TABLE events
+ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'THB';
+
+ALTER TABLE events ALTER COLUMN currency DROP DEFAULT;
+
+ALTER TABLE bookings
+ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'THB';
+
+ALTER TABLE bookings ALTER COLUMN currency DROP DEFAULT;
+
+ALTER TABLE seats ALTER COLUMN price TYPE BIGINT USING ROUND(price * 100);
+ALTER TABLE zone_price_tiers ALTER COLUMN price TYPE BIGINT USING ROUND(price * 100);
+ALTER TABLE bookings ALTER COLUMN total_amount TYPE BIGINT USING ROUND(total_amount * 100);
+ALTER TABLE bookings ALTER COLUMN discount_amount TYPE BIGINT USING ROUND(discount_amount * 100);
+ALTER TABLE booking_items ALTER COLUMN unit_price TYPE BIGINT USING ROUND(unit_price * 100);
+ALTER TABLE payments ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100);
+ALTER TABLE refunds ALTER COLUMN amount TYPE BIGINT USING ROUND(amount * 100);
+ALTER TABLE promo_redemptions ALTER COLUMN discount TYPE BIGINT USING ROUND(discount * 100);
+
+ALTER TABLE resale_listings
+ALTER COLUMN price TYPE BIGINT USING ROUND(price * 100),
+ALTER COLUMN fee TYPE BIGINT USING ROUND(fee * 100),
+ALTER COLUMN payout_amount TYPE BIGINT USING ROUND(payout_amount * 100);
+
+-- Percent codes keep a percentage, fixed codes an amount in their own currency
+ALTER TABLE promo_codes
+ADD COLUMN percent DECIMAL(5, 2),
+ADD COLUMN fixed_amount BIGINT,
+ADD COLUMN currency CHAR(3);
+
+UPDATE promo_codes SET percent = amount WHERE discount_type = 'PERCENT';
+UPDATE promo_codes SET fixed_amount = ROUND(amount * 100), currency = 'THB' WHERE discount_type = 'FIXED';
+
+ALTER TABLE promo_codes DROP COLUMN amount;
+
+ALTER TABLE promo_codes
+ADD CONSTRAINT promo_codes_discount_check CHECK (
+    (discount_type = 'PERCENT' AND percent > 0 AND percent <= 100 AND fixed_amount IS NULL AND currency IS NULL)
+    OR (discount_type = 'FIXED' AND fixed_amount > 0 AND currency IS NOT NULL AND percent IS NULL)
+);
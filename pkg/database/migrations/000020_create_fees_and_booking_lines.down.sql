@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS booking_lines;
+DROP TYPE IF EXISTS booking_line_kind;
+
+ALTER TABLE events DROP COLUMN IF EXISTS tax_percent;
+
+DROP TABLE IF EXISTS event_fees;
+DROP TYPE IF EXISTS fee_type;
@@ -0,0 +1,32 @@
+CREATE TYPE fee_type AS ENUM ('PER_TICKET', 'PER_ORDER', 'PERCENT');
+
+CREATE TABLE event_fees (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    fee_type fee_type NOT NULL,
+    amount BIGINT,
+    percent DECIMAL(5, 2),
+    CHECK (
+        (fee_type = 'PERCENT' AND percent > 0 AND percent <= 100 AND amount IS NULL)
+        OR (fee_type <> 'PERCENT' AND amount > 0 AND percent IS NULL)
+    )
+);
+
+CREATE INDEX idx_event_fees_event ON event_fees(event_id);
+
+ALTER TABLE events
+ADD COLUMN tax_percent DECIMAL(5, 2) NOT NULL DEFAULT 0 CHECK (tax_percent >= 0 AND tax_percent <= 100);
+
+CREATE TYPE booking_line_kind AS ENUM ('FEE', 'TAX');
+
+CREATE TABLE booking_lines (
+    id BIGSERIAL PRIMARY KEY,
+    booking_id UUID NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    kind booking_line_kind NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    amount BIGINT NOT NULL CHECK (amount >= 0),
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_booking_lines_booking ON booking_lines(booking_id);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_booking_items_ticket_type;
+
+ALTER TABLE booking_items DROP COLUMN IF EXISTS ticket_type_id;
+
+DROP TABLE IF EXISTS ticket_type_prices;
+
+DROP TABLE IF EXISTS ticket_types;
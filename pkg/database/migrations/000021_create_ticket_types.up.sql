@@ -0,0 +1,20 @@
+CREATE TABLE ticket_types (
+    id BIGSERIAL PRIMARY KEY,
+    event_id BIGINT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    eligibility VARCHAR(255),
+    quota INT NOT NULL DEFAULT 0 CHECK (quota >= 0),
+    UNIQUE (event_id, name)
+);
+
+CREATE TABLE ticket_type_prices (
+    ticket_type_id BIGINT NOT NULL REFERENCES ticket_types(id) ON DELETE CASCADE,
+    zone VARCHAR(50) NOT NULL,
+    price BIGINT NOT NULL CHECK (price >= 0),
+    PRIMARY KEY (ticket_type_id, zone)
+);
+
+ALTER TABLE booking_items
+ADD COLUMN ticket_type_id BIGINT REFERENCES ticket_types(id);
+
+CREATE INDEX idx_booking_items_ticket_type ON booking_items(ticket_type_id) WHERE ticket_type_id IS NOT NULL;
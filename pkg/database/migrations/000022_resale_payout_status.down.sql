@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_resale_listings_payout_pending;
+
+ALTER TABLE resale_listings DROP COLUMN IF EXISTS payout_status;
+
+DROP TYPE IF EXISTS resale_payout_status;
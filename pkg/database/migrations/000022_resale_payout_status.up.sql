@@ -0,0 +1,9 @@
+-- Sellers are paid after the sale commits, PENDING payouts are retried until the gateway accepts them
+CREATE TYPE resale_payout_status AS ENUM ('PENDING', 'PAID');
+
+ALTER TABLE resale_listings
+ADD COLUMN payout_status resale_payout_status;
+
+UPDATE resale_listings SET payout_status = 'PAID' WHERE status = 'SOLD';
+
+CREATE INDEX idx_resale_listings_payout_pending ON resale_listings(sold_at) WHERE payout_status = 'PENDING';
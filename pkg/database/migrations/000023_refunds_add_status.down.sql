@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_refunds_pending;
+
+ALTER TABLE refunds DROP COLUMN IF EXISTS status;
+
+DROP TYPE IF EXISTS refund_status;
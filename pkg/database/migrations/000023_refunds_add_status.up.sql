@@ -0,0 +1,9 @@
+-- Refunds are recorded PENDING with the booking change and sent to the gateway after it commits
+CREATE TYPE refund_status AS ENUM ('PENDING', 'COMPLETED');
+
+ALTER TABLE refunds
+ADD COLUMN status refund_status NOT NULL DEFAULT 'COMPLETED';
+
+ALTER TABLE refunds ALTER COLUMN status SET DEFAULT 'PENDING';
+
+CREATE INDEX idx_refunds_pending ON refunds(created_at) WHERE status = 'PENDING';
@@ -0,0 +1 @@
+ALTER TABLE booking_items DROP COLUMN IF EXISTS discount;
@@ -0,0 +1,30 @@
+-- Each seat carries its share of the promo discount, so it leaves the booking when the seat does
+ALTER TABLE booking_items
+ADD COLUMN discount BIGINT NOT NULL DEFAULT 0 CHECK (discount >= 0);
+
+-- Spread the discount of existing bookings over their active seats in the code's zone by price,
+-- the highest seat id takes the rounding remainder
+WITH eligible AS (
+    SELECT bi.booking_id, bi.seat_id, bi.unit_price, b.discount_amount,
+        SUM(bi.unit_price) OVER (PARTITION BY bi.booking_id) AS eligible_total,
+        ROW_NUMBER() OVER (PARTITION BY bi.booking_id ORDER BY bi.seat_id DESC) AS rn
+    FROM booking_items bi
+    JOIN bookings b ON b.id = bi.booking_id
+    JOIN promo_redemptions pr ON pr.booking_id = b.id
+    JOIN promo_codes pc ON pc.id = pr.promo_id
+    JOIN seats s ON s.id = bi.seat_id
+    WHERE b.discount_amount > 0 AND bi.released_at IS NULL
+        AND (pc.zone IS NULL OR pc.zone = s.zone)
+), shares AS (
+    SELECT booking_id, seat_id, rn, discount_amount,
+        discount_amount * unit_price / eligible_total AS share
+    FROM eligible
+    WHERE eligible_total > 0
+), spread AS (
+    SELECT booking_id, seat_id,
+        CASE WHEN rn = 1 THEN discount_amount - (SUM(share) OVER (PARTITION BY booking_id) - share) ELSE share END AS discount
+    FROM shares
+)
+UPDATE booking_items bi SET discount = spread.discount
+FROM spread
+WHERE bi.booking_id = spread.booking_id AND bi.seat_id = spread.seat_id;
@@ -0,0 +1,2 @@
+-- enum values cannot be dropped, TRANSFERRED stays on bookings_status
+UPDATE bookings SET status = 'PAID' WHERE status = 'TRANSFERRED';
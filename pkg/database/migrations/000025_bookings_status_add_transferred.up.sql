@@ -0,0 +1 @@
+ALTER TYPE bookings_status ADD VALUE IF NOT EXISTS 'TRANSFERRED';
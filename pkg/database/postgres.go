@@ -30,17 +30,21 @@ func ConnectPostgres(conn string) (*sql.DB, error) {
 
 type TxManager interface {
 	WithTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) 
+	// WithRetryTx : WithTx that runs fn again on deadlocks and serialization failures,
+	// fn must not have side effects outside the transaction
+	WithRetryTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error)
 }
 
 type txManager struct {
-	db *sql.DB
+	db    *sql.DB
+	retry RetryPolicy
 }
 
-func NewTransaction(db *sql.DB) (TxManager, error) {
+func NewTransaction(db *sql.DB, retry RetryPolicy) (TxManager, error) {
 	if db == nil {
 		return nil, errors.New("db is required")
 	}
-	return &txManager{db: db}, nil
+	return &txManager{db: db, retry: retry}, nil
 }
 
 func (t *txManager) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
@@ -55,7 +59,8 @@ func (t *txManager) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) (err
 			panic(r)
 		} else if err != nil {
 			if rbErr := tx.Rollback(); rbErr != nil {
-				log.Fatalf("rollback: %v", err)
+				log.Printf("rollback failed: %v", rbErr)
+				err = errors.Join(err, fmt.Errorf("rollback: %w", rbErr))
 			}
 		} else {
 			cmErr := tx.Commit()
@@ -66,3 +71,9 @@ func (t *txManager) WithTx(ctx context.Context, fn func(tx *sql.Tx) error) (err
 	err = fn(tx)
 	return err
 }
+
+func (t *txManager) WithRetryTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	return t.retry.run(ctx, func() error {
+		return t.WithTx(ctx, fn)
+	})
+}
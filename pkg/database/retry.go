@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	codeDeadlockDetected     = "40P01"
+	codeSerializationFailure = "40001"
+)
+
+// RetryPolicy : how WithRetryTx retries, 0 MaxRetries runs the transaction once
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+}
+
+// IsRetryable : the transaction lost a deadlock or serialization conflict and can run again
+func IsRetryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == codeDeadlockDetected || pqErr.Code == codeSerializationFailure
+}
+
+func (p RetryPolicy) run(ctx context.Context, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.MaxRetries || !IsRetryable(err) {
+			return err
+		}
+		log.Printf("retrying transaction (%d/%d): %v", attempt+1, p.MaxRetries, err)
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(p.backoff(attempt)):
+		}
+	}
+}
+
+// backoff : BaseDelay doubled per attempt, with jitter on the upper half so retries spread out
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << attempt
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
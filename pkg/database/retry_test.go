@@ -0,0 +1,53 @@
+package database_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/codepnw/stdlib-ticket-system/pkg/database"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryable(t *testing.T) {
+	type testCase struct {
+		name     string
+		err      error
+		expected bool
+	}
+
+	testCases := []testCase{
+		{
+			name:     "deadlock",
+			err:      &pq.Error{Code: "40P01"},
+			expected: true,
+		},
+		{
+			name:     "serialization failure wrapped",
+			err:      fmt.Errorf("create booking: %w", &pq.Error{Code: "40001"}),
+			expected: true,
+		},
+		{
+			name:     "unique violation",
+			err:      &pq.Error{Code: "23505"},
+			expected: false,
+		},
+		{
+			name:     "not a postgres error",
+			err:      errors.New("boom"),
+			expected: false,
+		},
+		{
+			name:     "nil",
+			err:      nil,
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, database.IsRetryable(tc.err))
+		})
+	}
+}
@@ -35,6 +35,7 @@ func NewJWT(secretKey, refreshKey string) (JWTToken, error) {
 type UserClaims struct {
 	ID       int64
 	Username string
+	Role     user.Role
 	*jwt.RegisteredClaims
 }
 
@@ -50,6 +51,7 @@ func (j *jwtToken) generateToken(key []byte, u user.User, duration time.Duration
 	claims := &UserClaims{
 		ID:       u.ID,
 		Username: u.Username,
+		Role:     u.Role,
 		RegisteredClaims: &jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
 		},
@@ -77,6 +79,7 @@ func (j *jwtToken) VerifyRefreshToken(tokenStr string) (*Payload, error) {
 type Payload struct {
 	UserID    int64     `json:"user_id"`
 	Username  string    `json:"username"`
+	Role      user.Role `json:"role"`
 }
 
 func (j *jwtToken) verifyToken(key []byte, tokenStr string) (*Payload, error) {
@@ -99,6 +102,7 @@ func (j *jwtToken) verifyToken(key []byte, tokenStr string) (*Payload, error) {
 	payload := &Payload{
 		UserID:    claims.ID,
 		Username:  claims.Username,
+		Role:      claims.Role,
 	}
 	return payload, nil
 }
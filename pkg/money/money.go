@@ -0,0 +1,199 @@
+package money
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+)
+
+// Money : an exact amount in the minor unit of an ISO 4217 currency, {1050, "THB"} is 10.50 baht.
+// Amounts of different currencies never mix, Add and friends fail with errs.ErrCurrencyMismatch.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+// exponents : minor unit digits of the currencies that don't use 2
+var exponents = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+func New(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Zero : nothing of currency, the start of a total
+func Zero(currency string) Money {
+	return Money{Currency: currency}
+}
+
+// Exponent : digits after the decimal point in currency
+func Exponent(currency string) int {
+	if e, ok := exponents[currency]; ok {
+		return e
+	}
+	return 2
+}
+
+// ValidCurrency : three upper case letters
+func ValidCurrency(currency string) bool {
+	if len(currency) != 3 {
+		return false
+	}
+	for _, c := range currency {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Parse : read a decimal like "10.5" in currency. Digits past the minor unit must be zeros,
+// so "1000.00" JPY is fine but "10.005" THB is rejected instead of rounded.
+func Parse(amount, currency string) (Money, error) {
+	if !ValidCurrency(currency) {
+		return Money{}, errs.ErrInvalidMoney
+	}
+
+	digits, negative := strings.CutPrefix(amount, "-")
+	whole, frac, _ := strings.Cut(digits, ".")
+	if whole == "" || !isDigits(whole) || !isDigits(frac) {
+		return Money{}, errs.ErrInvalidMoney
+	}
+
+	exp := Exponent(currency)
+	if len(frac) > exp {
+		if strings.Trim(frac[exp:], "0") != "" {
+			return Money{}, errs.ErrInvalidMoney
+		}
+		frac = frac[:exp]
+	}
+	frac += strings.Repeat("0", exp-len(frac))
+
+	minor, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return Money{}, errs.ErrInvalidMoney
+	}
+	if negative {
+		minor = -minor
+	}
+	return Money{Amount: minor, Currency: currency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Money) Add(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, errs.ErrCurrencyMismatch
+	}
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}, nil
+}
+
+func (m Money) Sub(o Money) (Money, error) {
+	if m.Currency != o.Currency {
+		return Money{}, errs.ErrCurrencyMismatch
+	}
+	return Money{Amount: m.Amount - o.Amount, Currency: m.Currency}, nil
+}
+
+// Cmp : -1, 0 or +1 as m is less than, equal to or more than o
+func (m Money) Cmp(o Money) (int, error) {
+	if m.Currency != o.Currency {
+		return 0, errs.ErrCurrencyMismatch
+	}
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Percent : percent of m rounded to the nearest minor unit, halves away from zero
+func (m Money) Percent(percent float64) Money {
+	product := m.Amount * basisPoints(percent)
+	half := int64(5000)
+	if product < 0 {
+		half = -half
+	}
+	return Money{Amount: (product + half) / 10000, Currency: m.Currency}
+}
+
+// PercentDown : percent of m with the fraction of a minor unit dropped
+func (m Money) PercentDown(percent float64) Money {
+	return Money{Amount: m.Amount * basisPoints(percent) / 10000, Currency: m.Currency}
+}
+
+// basisPoints : percentages are kept to two decimals, 12.5% is 1250
+func basisPoints(percent float64) int64 {
+	return int64(math.Round(percent * 100))
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsPositive() bool {
+	return m.Amount > 0
+}
+
+// Decimal : the amount in major units, e.g. "10.50"
+func (m Money) Decimal() string {
+	exp := Exponent(m.Currency)
+	digits := strconv.FormatInt(m.Amount, 10)
+	sign := ""
+	if m.Amount < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	if exp == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+}
+
+func (m Money) String() string {
+	return fmt.Sprintf("%s %s", m.Decimal(), m.Currency)
+}
+
+// MarshalJSON : {"amount": "10.50", "currency": "THB"}, the amount is a string so clients don't round it
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Amount   string `json:"amount"`
+		Currency string `json:"currency"`
+	}{m.Decimal(), m.Currency})
+}
+
+// UnmarshalJSON : the amount may be a string or a number
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var v struct {
+		Amount   json.Number `json:"amount"`
+		Currency string      `json:"currency"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	parsed, err := Parse(v.Amount.String(), v.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,92 @@
+package money_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/pkg/money"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	type testCase struct {
+		name        string
+		amount      string
+		currency    string
+		expected    money.Money
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{name: "whole", amount: "100", currency: "THB", expected: money.New(10000, "THB")},
+		{name: "short fraction", amount: "10.5", currency: "THB", expected: money.New(1050, "THB")},
+		{name: "no minor unit", amount: "1500", currency: "JPY", expected: money.New(1500, "JPY")},
+		{name: "trailing zeros past the minor unit", amount: "1500.00", currency: "JPY", expected: money.New(1500, "JPY")},
+		{name: "three decimals", amount: "1.234", currency: "KWD", expected: money.New(1234, "KWD")},
+		{name: "negative", amount: "-0.05", currency: "USD", expected: money.New(-5, "USD")},
+		{name: "fail fraction of a minor unit", amount: "10.005", currency: "THB", expectedErr: errs.ErrInvalidMoney},
+		{name: "fail not a number", amount: "1e3", currency: "THB", expectedErr: errs.ErrInvalidMoney},
+		{name: "fail missing whole part", amount: ".5", currency: "THB", expectedErr: errs.ErrInvalidMoney},
+		{name: "fail bad currency", amount: "10", currency: "thb", expectedErr: errs.ErrInvalidMoney},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := money.Parse(tc.amount, tc.currency)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, m)
+			}
+		})
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	assert.Equal(t, "10.50", money.New(1050, "THB").Decimal())
+	assert.Equal(t, "0.05", money.New(5, "THB").Decimal())
+	assert.Equal(t, "-0.05", money.New(-5, "THB").Decimal())
+	assert.Equal(t, "1500", money.New(1500, "JPY").Decimal())
+	assert.Equal(t, "0.001", money.New(1, "KWD").Decimal())
+}
+
+func TestAdd(t *testing.T) {
+	// 0.1 + 0.2 drifts as float64, not in minor units
+	total := money.Zero("THB")
+	for _, p := range []money.Money{money.New(10, "THB"), money.New(20, "THB")} {
+		var err error
+		total, err = total.Add(p)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, money.New(30, "THB"), total)
+
+	_, err := total.Add(money.New(30, "USD"))
+	assert.ErrorIs(t, err, errs.ErrCurrencyMismatch)
+}
+
+func TestPercent(t *testing.T) {
+	price := money.New(9999, "THB")
+
+	assert.Equal(t, money.New(1250, "THB"), price.Percent(12.5))
+	assert.Equal(t, money.New(1249, "THB"), price.PercentDown(12.5))
+	assert.Equal(t, money.New(5000, "THB"), money.New(9999, "THB").Percent(50.005))
+	assert.Equal(t, price, price.Percent(100))
+}
+
+func TestJSON(t *testing.T) {
+	data, err := json.Marshal(money.New(1050, "THB"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"10.50","currency":"THB"}`, string(data))
+
+	var fromString, fromNumber money.Money
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":"10.50","currency":"THB"}`), &fromString))
+	assert.NoError(t, json.Unmarshal([]byte(`{"amount":10.5,"currency":"THB"}`), &fromNumber))
+	assert.Equal(t, money.New(1050, "THB"), fromString)
+	assert.Equal(t, money.New(1050, "THB"), fromNumber)
+
+	var bad money.Money
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"amount":"10.555","currency":"THB"}`), &bad), errs.ErrInvalidMoney)
+}
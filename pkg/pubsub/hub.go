@@ -0,0 +1,94 @@
+package pubsub
+
+import "sync"
+
+// Hub : in-process fan-out of messages per topic.
+// Every subscription has its own buffer, a subscription that falls a full buffer behind is dropped.
+type Hub[K comparable, T any] struct {
+	mu     sync.Mutex
+	buffer int
+	topics map[K]map[*Subscription[K, T]]struct{}
+}
+
+func NewHub[K comparable, T any](buffer int) *Hub[K, T] {
+	if buffer < 1 {
+		buffer = 1
+	}
+	return &Hub[K, T]{
+		buffer: buffer,
+		topics: make(map[K]map[*Subscription[K, T]]struct{}),
+	}
+}
+
+// Subscription : messages of one topic, C is closed once the subscription is closed or dropped
+type Subscription[K comparable, T any] struct {
+	hub   *Hub[K, T]
+	topic K
+	ch    chan T
+}
+
+func (s *Subscription[K, T]) C() <-chan T {
+	return s.ch
+}
+
+// Close : stop receiving, safe to call more than once
+func (s *Subscription[K, T]) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.remove(s)
+}
+
+func (h *Hub[K, T]) Subscribe(topic K) *Subscription[K, T] {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub := &Subscription[K, T]{hub: h, topic: topic, ch: make(chan T, h.buffer)}
+
+	subs, ok := h.topics[topic]
+	if !ok {
+		subs = make(map[*Subscription[K, T]]struct{})
+		h.topics[topic] = subs
+	}
+	subs[sub] = struct{}{}
+	return sub
+}
+
+// Publish : never blocks, slow subscribers are dropped instead of holding up the publisher
+func (h *Hub[K, T]) Publish(topic K, msg T) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for sub := range h.topics[topic] {
+		select {
+		case sub.ch <- msg:
+		default:
+			h.remove(sub)
+		}
+	}
+}
+
+// Subscribers : open subscriptions of the topic
+func (h *Hub[K, T]) Subscribers(topic K) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.topics[topic])
+}
+
+// remove : caller holds h.mu
+func (h *Hub[K, T]) remove(sub *Subscription[K, T]) {
+	subs, ok := h.topics[sub.topic]
+	if !ok {
+		return
+	}
+	if _, ok := subs[sub]; !ok {
+		return
+	}
+
+	delete(subs, sub)
+	close(sub.ch)
+	if len(subs) == 0 {
+		delete(h.topics, sub.topic)
+	}
+}
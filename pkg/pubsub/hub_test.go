@@ -0,0 +1,63 @@
+package pubsub_test
+
+import (
+	"testing"
+
+	"github.com/codepnw/stdlib-ticket-system/pkg/pubsub"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublish(t *testing.T) {
+	hub := pubsub.NewHub[int64, string](2)
+
+	a := hub.Subscribe(1)
+	b := hub.Subscribe(1)
+	other := hub.Subscribe(2)
+	defer a.Close()
+	defer b.Close()
+	defer other.Close()
+
+	hub.Publish(1, "seat 11 reserved")
+
+	assert.Equal(t, "seat 11 reserved", <-a.C())
+	assert.Equal(t, "seat 11 reserved", <-b.C())
+	assert.Len(t, other.C(), 0)
+}
+
+func TestDropSlowSubscriber(t *testing.T) {
+	hub := pubsub.NewHub[int64, int](2)
+
+	slow := hub.Subscribe(1)
+	fast := hub.Subscribe(1)
+	defer fast.Close()
+
+	for i := 1; i <= 3; i++ {
+		hub.Publish(1, i)
+		<-fast.C()
+	}
+
+	// the buffered messages are still delivered, then the channel is closed
+	assert.Equal(t, 1, <-slow.C())
+	assert.Equal(t, 2, <-slow.C())
+	_, ok := <-slow.C()
+	assert.False(t, ok)
+	assert.Equal(t, 1, hub.Subscribers(1))
+
+	// closing a dropped subscription is a no-op
+	slow.Close()
+}
+
+func TestClose(t *testing.T) {
+	hub := pubsub.NewHub[int64, int](1)
+
+	sub := hub.Subscribe(1)
+	sub.Close()
+	sub.Close()
+
+	_, ok := <-sub.C()
+	assert.False(t, ok)
+	assert.Equal(t, 0, hub.Subscribers(1))
+
+	// publishing to a topic without subscribers is fine
+	hub.Publish(1, 1)
+}
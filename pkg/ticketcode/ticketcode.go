@@ -0,0 +1,90 @@
+package ticketcode
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+)
+
+// Code format : base64url("<booking_id>:<seat_id>:<event_id>") + "." + base64url(HMAC-SHA256)
+const separator = "."
+
+var encoding = base64.RawURLEncoding
+
+type Signer interface {
+	Sign(c Claims) string
+	Verify(code string) (Claims, error)
+}
+
+type Claims struct {
+	BookingID string
+	SeatID    int64
+	EventID   int64
+}
+
+type signer struct {
+	key []byte
+}
+
+func NewSigner(key string) (Signer, error) {
+	if key == "" {
+		return nil, errors.New("ticket signing key is required")
+	}
+	return &signer{key: []byte(key)}, nil
+}
+
+func (s *signer) Sign(c Claims) string {
+	payload := fmt.Sprintf("%s:%d:%d", c.BookingID, c.SeatID, c.EventID)
+	return encoding.EncodeToString([]byte(payload)) + separator + encoding.EncodeToString(s.mac([]byte(payload)))
+}
+
+// Verify : rejects codes that were not signed with this key or were changed after signing
+func (s *signer) Verify(code string) (Claims, error) {
+	encPayload, encSig, ok := strings.Cut(code, separator)
+	if !ok {
+		return Claims{}, errs.ErrInvalidTicketCode
+	}
+
+	payload, err := encoding.DecodeString(encPayload)
+	if err != nil {
+		return Claims{}, errs.ErrInvalidTicketCode
+	}
+	sig, err := encoding.DecodeString(encSig)
+	if err != nil {
+		return Claims{}, errs.ErrInvalidTicketCode
+	}
+	if !hmac.Equal(sig, s.mac(payload)) {
+		return Claims{}, errs.ErrInvalidTicketCode
+	}
+
+	parts := strings.Split(string(payload), ":")
+	if len(parts) != 3 {
+		return Claims{}, errs.ErrInvalidTicketCode
+	}
+	seatID, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return Claims{}, errs.ErrInvalidTicketCode
+	}
+	eventID, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return Claims{}, errs.ErrInvalidTicketCode
+	}
+
+	return Claims{
+		BookingID: parts[0],
+		SeatID:    seatID,
+		EventID:   eventID,
+	}, nil
+}
+
+func (s *signer) mac(payload []byte) []byte {
+	h := hmac.New(sha256.New, s.key)
+	h.Write(payload)
+	return h.Sum(nil)
+}
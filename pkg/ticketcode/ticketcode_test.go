@@ -0,0 +1,73 @@
+package ticketcode_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/codepnw/stdlib-ticket-system/internal/errs"
+	"github.com/codepnw/stdlib-ticket-system/pkg/ticketcode"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerify(t *testing.T) {
+	signer, err := ticketcode.NewSigner("test-key")
+	assert.NoError(t, err)
+
+	claims := ticketcode.Claims{BookingID: "mock-uuid-1", SeatID: 5, EventID: 10}
+	valid := signer.Sign(claims)
+	payload, sig, _ := strings.Cut(valid, ".")
+
+	other, _ := ticketcode.NewSigner("other-key")
+	forged := other.Sign(claims)
+
+	tampered := signer.Sign(ticketcode.Claims{BookingID: "mock-uuid-1", SeatID: 6, EventID: 10})
+	tamperedPayload, _, _ := strings.Cut(tampered, ".")
+
+	type testCase struct {
+		name        string
+		code        string
+		expected    ticketcode.Claims
+		expectedErr error
+	}
+
+	testCases := []testCase{
+		{
+			name:     "success",
+			code:     valid,
+			expected: claims,
+		},
+		{
+			name:        "fail signed with another key",
+			code:        forged,
+			expectedErr: errs.ErrInvalidTicketCode,
+		},
+		{
+			name:        "fail payload swapped",
+			code:        tamperedPayload + "." + sig,
+			expectedErr: errs.ErrInvalidTicketCode,
+		},
+		{
+			name:        "fail signature stripped",
+			code:        payload,
+			expectedErr: errs.ErrInvalidTicketCode,
+		},
+		{
+			name:        "fail not base64",
+			code:        "!!!." + sig,
+			expectedErr: errs.ErrInvalidTicketCode,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := signer.Verify(tc.code)
+
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, result)
+			}
+		})
+	}
+}